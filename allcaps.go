@@ -0,0 +1,117 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// allCapsCategory is the diagnostic category used by the ALL_CAPS rule.
+const allCapsCategory = "all-caps"
+
+// isAllCaps reports whether name is upper case words joined by underscores,
+// such as MAX_RETRY_COUNT. Names of two characters or fewer, not counting
+// underscores, are exempt.
+func isAllCaps(name string) bool {
+	if len(strings.ReplaceAll(name, "_", "")) <= 2 || !strings.Contains(name, "_") {
+		return false
+	}
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			// _FOO, FOO__BAR and FOO_ are left alone
+			return false
+		}
+		first := rune(word[0])
+		if !unicode.IsUpper(first) {
+			return false
+		}
+		for _, r := range word {
+			if !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// allCapsToCamel converts MAX_RETRY_COUNT to MaxRetryCount, keeping words
+// found in initialisms upper case (HTTP_TIMEOUT -> HTTPTimeout).
+func allCapsToCamel(name string, initialisms map[string]bool) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if initialisms[word] {
+			b.WriteString(word)
+			continue
+		}
+		b.WriteString(word[:1] + strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// checkAllCaps reports const declarations, and var declarations when vars is
+// set, whose names are written in SCREAMING_SNAKE_CASE.
+func checkAllCaps(pass *analysis.Pass, files []*ast.File, initialisms map[string]bool, vars bool) {
+	declared := make(map[string]bool)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			for _, ident := range topLevelIdents(decl) {
+				declared[ident.Name] = true
+			}
+		}
+	}
+
+	for _, file := range files {
+		linknamed := linknameTargets(file)
+		ast.Inspect(file, func(n ast.Node) bool {
+			decl, ok := n.(*ast.GenDecl)
+			if !ok || (decl.Tok != token.CONST && !(vars && decl.Tok == token.VAR)) {
+				return true
+			}
+			for _, spec := range decl.Specs {
+				for _, ident := range spec.(*ast.ValueSpec).Names {
+					if !isAllCaps(ident.Name) || linknamed[ident.Name] {
+						continue
+					}
+					reportAllCaps(pass, file, ident, allCapsToCamel(ident.Name, initialisms), declared)
+				}
+			}
+			return true
+		})
+	}
+}
+
+func reportAllCaps(pass *analysis.Pass, file *ast.File, ident *ast.Ident, suggestedName string, declared map[string]bool) {
+	message := fmt.Sprintf("suggest replacing '%s' with '%s'", ident.Name, suggestedName)
+	diagnostic := analysis.Diagnostic{
+		Pos:      ident.Pos(),
+		End:      ident.End(),
+		Category: allCapsCategory,
+		Message:  message,
+	}
+	// An existing declaration with the new name suppresses the fix
+	if !declared[suggestedName] {
+		if edits := variableRenameEdits(pass, file, ident, suggestedName); edits != nil {
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{Message: message, TextEdits: edits}}
+		}
+	}
+	pass.Report(diagnostic)
+}
+
+// linknameTargets returns the local names bound by //go:linkname directives
+// in file. Their names are fixed by the linked symbol.
+func linknameTargets(file *ast.File) map[string]bool {
+	targets := make(map[string]bool)
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			fields := strings.Fields(comment.Text)
+			if len(fields) >= 2 && fields[0] == "//go:linkname" {
+				targets[fields[1]] = true
+			}
+		}
+	}
+	return targets
+}
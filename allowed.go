@@ -0,0 +1,70 @@
+package gonamefix
+
+// GoStdlibAllowedNames returns identifiers that mirror names from the Go
+// standard library. They are usually declared to satisfy a standard interface
+// or follow a standard convention, so renaming them would break code or
+// surprise readers. NewAnalyzer always merges this list into
+// Config.AllowedLongNames.
+func GoStdlibAllowedNames() []string {
+	return []string{
+		// Interface methods implemented by user types
+		"ServeHTTP",
+		"RoundTrip",
+		"MarshalJSON",
+		"UnmarshalJSON",
+		"MarshalText",
+		"UnmarshalText",
+		"MarshalBinary",
+		"UnmarshalBinary",
+		"MarshalXML",
+		"UnmarshalXML",
+		"GobEncode",
+		"GobDecode",
+		"ReadFrom",
+		"WriteTo",
+		"ReadAt",
+		"WriteAt",
+		"WriteString",
+		"ReadByte",
+		"WriteByte",
+		"ReadRune",
+		"UnreadRune",
+		"UnreadByte",
+		"GoString",
+		"Format",
+		"Unwrap",
+		"Scan",
+		"Value",
+		"Deadline",
+		"Done",
+		"Err",
+
+		// Well-known verbose names
+		"ErrNotFound",
+		"ErrUnexpectedEOF",
+		"ErrShortWrite",
+		"ErrShortBuffer",
+		"ErrNoProgress",
+		"ErrClosedPipe",
+		"ErrServerClosed",
+		"ErrHandlerTimeout",
+		"ErrBodyNotAllowed",
+		"ErrNoRows",
+		"ErrTxDone",
+		"DeadlineExceeded",
+		"ContextDeadlineExceeded",
+		"DefaultMaxHeaderBytes",
+		"MaxHeaderBytes",
+		"ReadHeaderTimeout",
+	}
+}
+
+// allowedNames returns the names no mapping flags under config: those of the
+// standard library and config.AllowedLongNames.
+func allowedNames(config Config) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, name := range append(GoStdlibAllowedNames(), config.AllowedLongNames...) {
+		allowed[name] = true
+	}
+	return allowed
+}
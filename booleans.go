@@ -0,0 +1,343 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// boolNameCategory is the diagnostic category used by the boolean naming rule.
+const boolNameCategory = "bool-naming"
+
+// Kinds of declarations checked by the boolean naming rule.
+const (
+	BoolKindVar   = "var"
+	BoolKindField = "field"
+	BoolKindFunc  = "func"
+)
+
+// DefaultBoolPrefixes returns the prefixes that make a boolean name read as a
+// predicate, e.g. isValid or hasChildren.
+func DefaultBoolPrefixes() []string {
+	return []string{"is", "has", "are", "can", "should", "will", "was", "does"}
+}
+
+// DefaultBoolNamingExceptions returns idiomatic boolean names that are
+// accepted as they are. Less is required by sort.Interface.
+func DefaultBoolNamingExceptions() []string {
+	return []string{"ok", "found", "done", "loaded", "Less"}
+}
+
+// adjectiveSuffixes mark a last word that reads as a state (enabled, valid,
+// running), which gets the "is" prefix.
+var adjectiveSuffixes = []string{"ed", "en", "ing", "able", "ible", "ive", "id", "al", "ful", "ent", "ant", "ic", "ty"}
+
+// verbSuffixes mark a last word that is most likely a verb in the third
+// person (exists, contains, matches). No prefix fits those, so the rule only
+// reports them.
+var verbSuffixes = []string{"sts", "ains", "ches", "shes"}
+
+type boolRule struct {
+	prefixes   []string
+	exceptions map[string]bool
+	kinds      map[string]bool
+}
+
+func buildBoolRule(config Config) *boolRule {
+	prefixes := config.BoolPrefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultBoolPrefixes()
+	}
+
+	exceptions := make(map[string]bool)
+	for _, name := range append(DefaultBoolNamingExceptions(), config.BoolNamingExceptions...) {
+		exceptions[name] = true
+	}
+
+	kinds := make(map[string]bool)
+	for _, kind := range config.BoolNamingKinds {
+		kinds[kind] = true
+	}
+	if len(kinds) == 0 {
+		kinds = map[string]bool{BoolKindVar: true, BoolKindField: true, BoolKindFunc: true}
+	}
+
+	return &boolRule{prefixes: prefixes, exceptions: exceptions, kinds: kinds}
+}
+
+// isPredicate reports whether name already starts with one of the prefixes,
+// either alone or followed by an upper case letter (isValid, IsValid, has).
+func (r *boolRule) isPredicate(name string) bool {
+	if r.exceptions[name] {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if len(name) < len(prefix) || !strings.EqualFold(name[:len(prefix)], prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		if rest == "" {
+			return true
+		}
+		first, _ := utf8.DecodeRuneInString(rest)
+		if unicode.IsUpper(first) || unicode.IsDigit(first) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns name with a predicate prefix: participles and adjectives get
+// "is" (enabled -> isEnabled), plural nouns get "has" (children ->
+// hasChildren). The prefix is capitalized for exported names.
+func (r *boolRule) suggest(name string) (string, bool) {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return "", false
+	}
+	last := strings.ToLower(words[len(words)-1])
+
+	prefix := "is"
+	switch {
+	case hasAnySuffix(last, verbSuffixes):
+		return "", false
+	case strings.HasSuffix(last, "ren"):
+		// children, not a participle like hidden
+		prefix = "has"
+	case hasAnySuffix(last, adjectiveSuffixes):
+	case strings.HasSuffix(last, "s") && !hasAnySuffix(last, []string{"ss", "us"}):
+		prefix = "has"
+	}
+
+	first, size := utf8.DecodeRuneInString(name)
+	if unicode.IsUpper(first) {
+		prefix = strings.ToUpper(prefix[:1]) + prefix[1:]
+	}
+	return prefix + string(unicode.ToUpper(first)) + name[size:], true
+}
+
+func hasAnySuffix(word string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBoolNames reports variables, struct fields and functions of type bool
+// whose names do not read as predicates.
+func checkBoolNames(pass *analysis.Pass, files []*ast.File, rule *boolRule) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						if decl.Tok == token.VAR {
+							checkBoolValueSpec(pass, file, spec, rule)
+						}
+					case *ast.TypeSpec:
+						if structType, ok := spec.Type.(*ast.StructType); ok && rule.kinds[BoolKindField] {
+							checkBoolFields(pass, spec, structType, rule)
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if rule.kinds[BoolKindFunc] && !hasExportDirective(decl) && returnsBool(pass, decl) &&
+					!implementsImportedInterface(pass, decl) {
+					checkBoolName(pass, decl.Name, rule, boolFuncConflict(pass, decl), nil)
+				}
+				if decl.Body == nil {
+					continue
+				}
+				ast.Inspect(decl.Body, func(n ast.Node) bool {
+					switch n := n.(type) {
+					case *ast.ValueSpec:
+						checkBoolValueSpec(pass, file, n, rule)
+					case *ast.AssignStmt:
+						if n.Tok != token.DEFINE || !rule.kinds[BoolKindVar] {
+							return true
+						}
+						for i, lhs := range n.Lhs {
+							ident, ok := lhs.(*ast.Ident)
+							if !ok || !isDefinition(pass, ident, n) {
+								continue
+							}
+							var value ast.Expr
+							if len(n.Lhs) == len(n.Rhs) {
+								value = n.Rhs[i]
+							}
+							if isBoolVar(pass, ident, nil, value) {
+								checkBoolName(pass, ident, rule, nil, file)
+							}
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+func checkBoolValueSpec(pass *analysis.Pass, file *ast.File, spec *ast.ValueSpec, rule *boolRule) {
+	if !rule.kinds[BoolKindVar] {
+		return
+	}
+	for i, ident := range spec.Names {
+		var value ast.Expr
+		if len(spec.Names) == len(spec.Values) {
+			value = spec.Values[i]
+		}
+		if ident.Name != "_" && isBoolVar(pass, ident, spec.Type, value) {
+			checkBoolName(pass, ident, rule, nil, file)
+		}
+	}
+}
+
+func checkBoolFields(pass *analysis.Pass, spec *ast.TypeSpec, structType *ast.StructType, rule *boolRule) {
+	siblings := make(map[string]bool)
+	for _, field := range structType.Fields.List {
+		for _, name := range field.Names {
+			siblings[name.Name] = true
+		}
+	}
+
+	conflict := func(newName string) bool {
+		if siblings[newName] {
+			return true
+		}
+		if pass.TypesInfo != nil && pass.Pkg != nil {
+			if obj := pass.TypesInfo.Defs[spec.Name]; obj != nil {
+				member, _, _ := types.LookupFieldOrMethod(obj.Type(), true, pass.Pkg, newName)
+				return member != nil
+			}
+		}
+		return false
+	}
+
+	for _, field := range structType.Fields.List {
+		if !isBoolType(pass, field.Type) {
+			continue
+		}
+		for _, name := range field.Names {
+			checkBoolName(pass, name, rule, conflict, nil)
+		}
+	}
+}
+
+// checkBoolName reports ident unless it reads as a predicate. Variables are
+// renamed within file like other local renames; fields and functions are
+// renamed across the package when type information is available and conflict
+// reports no clash with the new name.
+func checkBoolName(pass *analysis.Pass, ident *ast.Ident, rule *boolRule, conflict func(string) bool, file *ast.File) {
+	if ident.Name == "_" || rule.isPredicate(ident.Name) {
+		return
+	}
+
+	suggestedName, ok := rule.suggest(ident.Name)
+	if !ok {
+		pass.Report(analysis.Diagnostic{
+			Pos:      ident.Pos(),
+			End:      ident.End(),
+			Category: boolNameCategory,
+			Message:  fmt.Sprintf("bool '%s' should read as a predicate, with a prefix such as %s", ident.Name, strings.Join(rule.prefixes, "/")),
+		})
+		return
+	}
+
+	diagnostic := analysis.Diagnostic{
+		Pos:      ident.Pos(),
+		End:      ident.End(),
+		Category: boolNameCategory,
+		Message:  fmt.Sprintf("bool '%s' should read as a predicate, e.g. '%s'", ident.Name, suggestedName),
+	}
+
+	var edits []analysis.TextEdit
+	switch {
+	case file != nil:
+		edits = variableRenameEdits(pass, file, ident, suggestedName)
+	case conflict != nil && !conflict(suggestedName):
+		edits = objectRenameEdits(pass, ident, suggestedName)
+	}
+	if edits != nil {
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Rename '%s' to '%s'", ident.Name, suggestedName),
+			TextEdits: edits,
+		}}
+	}
+	pass.Report(diagnostic)
+}
+
+// boolFuncConflict returns the conflict check for renaming the function or
+// method fn: the new name must not already be declared in the package or on
+// the receiver type.
+func boolFuncConflict(pass *analysis.Pass, fn *ast.FuncDecl) func(string) bool {
+	return func(newName string) bool {
+		if pass.TypesInfo == nil || pass.Pkg == nil {
+			return true
+		}
+		obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			return true
+		}
+		if recv := obj.Type().(*types.Signature).Recv(); recv != nil {
+			member, _, _ := types.LookupFieldOrMethod(recv.Type(), true, pass.Pkg, newName)
+			return member != nil
+		}
+		return pass.Pkg.Scope().Lookup(newName) != nil
+	}
+}
+
+// isBoolVar reports whether ident declares a bool variable. Without type
+// information an explicit bool type, a true/false literal, a comparison or a
+// negation is recognized.
+func isBoolVar(pass *analysis.Pass, ident *ast.Ident, typ, value ast.Expr) bool {
+	if pass.TypesInfo != nil {
+		obj, ok := pass.TypesInfo.Defs[ident].(*types.Var)
+		return ok && types.Identical(obj.Type(), types.Typ[types.Bool])
+	}
+	if typ != nil {
+		return isBoolType(pass, typ)
+	}
+
+	switch value := value.(type) {
+	case *ast.Ident:
+		return value.Name == "true" || value.Name == "false"
+	case *ast.UnaryExpr:
+		return value.Op == token.NOT
+	case *ast.BinaryExpr:
+		switch value.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			return true
+		}
+	}
+	return false
+}
+
+// isBoolType reports whether expr denotes the bool type.
+func isBoolType(pass *analysis.Pass, expr ast.Expr) bool {
+	if pass.TypesInfo != nil {
+		if typ := pass.TypesInfo.TypeOf(expr); typ != nil {
+			return types.Identical(typ, types.Typ[types.Bool])
+		}
+	}
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "bool"
+}
+
+// returnsBool reports whether fn returns a single bool result.
+func returnsBool(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	results := fn.Type.Results
+	if results.NumFields() != 1 {
+		return false
+	}
+	return isBoolType(pass, results.List[0].Type)
+}
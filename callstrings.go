@@ -0,0 +1,115 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// StringCall selects a string literal argument checked by Config.CheckStrings,
+// such as the key of a structured logging call: log.With("request_id", id).
+type StringCall struct {
+	// Call is the called function: Func, pkg.Func, Type.Method or pkg.Type.Method.
+	// A "*" qualifier, as in *.WithField, matches any package or receiver
+	Call string `mapstructure:"call"`
+	// Arg is the zero-based index of the checked argument
+	Arg int `mapstructure:"arg"`
+}
+
+// checkStringArgs applies the mappings to the string literal arguments of call
+// selected by rules. Keys are split at underscores and punctuation, so
+// "request_id" suggests "req_id".
+func checkStringArgs(pass *analysis.Pass, call *ast.CallExpr, rules []StringCall, patterns []namePattern, caseSensitive bool) {
+	for _, rule := range rules {
+		if rule.Arg < 0 || rule.Arg >= len(call.Args) || !matchesCall(pass, call, rule.Call) {
+			continue
+		}
+		lit, ok := call.Args[rule.Arg].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			continue
+		}
+		if suggested, pattern, ok := suggestStringKey(value, patterns, caseSensitive); ok {
+			reportStringLiteral(pass, lit, value, suggested, pattern)
+		}
+	}
+}
+
+// suggestStringKey applies the mappings to every part of a snake_case key,
+// each part being split into words like a test case name.
+func suggestStringKey(key string, patterns []namePattern, caseSensitive bool) (string, namePattern, bool) {
+	parts := strings.Split(key, "_")
+	var first namePattern
+	matched := false
+	for i, part := range parts {
+		suggested, pattern, ok := suggestTestName(part, patterns, caseSensitive)
+		if !ok {
+			continue
+		}
+		if !matched {
+			first, matched = pattern, true
+		}
+		parts[i] = suggested
+	}
+	return strings.Join(parts, "_"), first, matched
+}
+
+// matchesCall reports whether call calls the function described by target.
+// The qualifier of a selector matches the name written in the source, or with
+// type information the imported package path or the receiver's type.
+func matchesCall(pass *analysis.Pass, call *ast.CallExpr, target string) bool {
+	qualifier, name, ok := cutLast(target, ".")
+	if !ok {
+		ident, isIdent := call.Fun.(*ast.Ident)
+		return isIdent && ident.Name == target
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	if qualifier == "*" {
+		return true
+	}
+	if x, ok := sel.X.(*ast.Ident); ok && x.Name == qualifier {
+		return true
+	}
+	if pass.TypesInfo == nil {
+		return false
+	}
+
+	if x, ok := sel.X.(*ast.Ident); ok {
+		if pkgName, ok := pass.TypesInfo.Uses[x].(*types.PkgName); ok {
+			return pkgName.Imported().Path() == qualifier
+		}
+	}
+	typ := pass.TypesInfo.TypeOf(sel.X)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	if obj.Name() == qualifier {
+		return true
+	}
+	return obj.Pkg() != nil &&
+		(obj.Pkg().Name()+"."+obj.Name() == qualifier || obj.Pkg().Path()+"."+obj.Name() == qualifier)
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
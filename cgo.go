@@ -0,0 +1,25 @@
+package gonamefix
+
+import "go/ast"
+
+// isCgoFile reports whether file imports the cgo pseudo-package "C".
+func isCgoFile(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		if spec.Path.Value == `"C"` {
+			return true
+		}
+	}
+	return false
+}
+
+// isCgoSelector reports whether expr selects from the cgo pseudo-package, as
+// in C.int or C.struct_request. Those names are declared by C and cannot be
+// renamed from Go.
+func isCgoSelector(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == "C"
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// backupPolicy is how -fix backs up the files it changes, for -backup.
+type backupPolicy struct {
+	// suffix is appended to the name of a file to name its backup
+	suffix string
+	// force overwrites existing backups instead of refusing to fix
+	force bool
+}
+
+// path returns the backup file of filename.
+func (b *backupPolicy) path(filename string) string {
+	return filename + b.suffix
+}
+
+// write backs up the content of files as it was before fixing, with the
+// permissions of the files. It fails before writing anything when a backup
+// exists and backups are not forced, and removes the backups it wrote when
+// one cannot be written, so that either every file is backed up or none.
+func (b *backupPolicy) write(files []fixedFile) error {
+	if !b.force {
+		for _, file := range files {
+			if _, err := os.Lstat(b.path(file.filename)); err == nil {
+				return fmt.Errorf("backup %s already exists (use -force-backup to overwrite it)", b.path(file.filename))
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("writing backup: %w", err)
+			}
+		}
+	}
+
+	for i, file := range files {
+		if err := b.writeFile(file); err != nil {
+			b.remove(files[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *backupPolicy) writeFile(file fixedFile) error {
+	info, err := os.Stat(file.filename)
+	if err != nil {
+		return err
+	}
+	backup := b.path(file.filename)
+	if err := os.WriteFile(backup, file.src, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	// WriteFile keeps the permissions of an existing backup
+	if err := os.Chmod(backup, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	return nil
+}
+
+// remove deletes the backups of files, whose fixes were not written.
+func (b *backupPolicy) remove(files []fixedFile) {
+	for _, file := range files {
+		os.Remove(b.path(file.filename))
+	}
+}
+
+// restoreBackups moves the backups of files back in place, for
+// -restore-backups, and returns the names of the restored files. Files
+// without a backup are left alone.
+func restoreBackups(files []string, suffix string) ([]string, error) {
+	var restored []string
+	for _, filename := range files {
+		backup := filename + suffix
+		if _, err := os.Lstat(backup); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := os.Rename(backup, filename); err != nil {
+			return restored, fmt.Errorf("restoring backup: %w", err)
+		}
+		restored = append(restored, filename)
+	}
+	return restored, nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// writeBackupFiles writes files to a new directory with mode 0o640 and
+// returns the results of analyzing them.
+func writeBackupFiles(t *testing.T, files map[string]string) (string, []fileResult) {
+	t.Helper()
+	dir := t.TempDir()
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	analyzer := gonamefix.NewAnalyzer(config)
+	var results []fileResult
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		src, ok := files[name]
+		if !ok {
+			continue
+		}
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(src), 0o640); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(filename, 0o640); err != nil {
+			t.Fatal(err)
+		}
+		result, err := analyzeFile(analyzer, config, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	return dir, results
+}
+
+func TestFixInPlaceBackup(t *testing.T) {
+	files := map[string]string{
+		"a.go": "package p\n\nvar request string\n",
+		"b.go": "package p\n\nfunc use() string { return request }\n",
+		// Nothing to fix, so no backup
+		"c.go": "package p\n\nvar other string\n",
+	}
+	dir, results := writeBackupFiles(t, files)
+
+	backup := &backupPolicy{suffix: ".orig"}
+	fixed, err := fixInPlace(results, nil, backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixed.files) != 2 {
+		t.Fatalf("fixInPlace() changed %d files, want 2", len(fixed.files))
+	}
+
+	for _, name := range []string{"a.go", "b.go"} {
+		backupName := filepath.Join(dir, name+".orig")
+		got, err := os.ReadFile(backupName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != files[name] {
+			t.Errorf("%s = %q, want %q", backupName, got, files[name])
+		}
+		info, err := os.Stat(backupName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o640 {
+			t.Errorf("%s has mode %v, want 0640", backupName, perm)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.go.orig")); !os.IsNotExist(err) {
+		t.Errorf("unchanged c.go was backed up: %v", err)
+	}
+
+	// The backups put the files back as they were
+	restored, err := restoreBackups([]string{filepath.Join(dir, "a.go"), filepath.Join(dir, "b.go"), filepath.Join(dir, "c.go")}, ".orig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 2 {
+		t.Errorf("restoreBackups() = %v, want a.go and b.go", restored)
+	}
+	for name, src := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != src {
+			t.Errorf("restored %s = %q, want %q", name, got, src)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.go.orig")); !os.IsNotExist(err) {
+		t.Errorf("backup left after restoring: %v", err)
+	}
+}
+
+func TestFixInPlaceExistingBackup(t *testing.T) {
+	files := map[string]string{
+		"a.go": "package p\n\nvar request string\n",
+		"b.go": "package p\n\nfunc use() string { return request }\n",
+	}
+	dir, results := writeBackupFiles(t, files)
+	existing := filepath.Join(dir, "b.go.orig")
+	if err := os.WriteFile(existing, []byte("older backup"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// An existing backup stops the fix before anything is written
+	_, err := fixInPlace(results, nil, &backupPolicy{suffix: ".orig"})
+	if err == nil || !strings.Contains(err.Error(), "-force-backup") {
+		t.Fatalf("fixInPlace() error = %v, want a backup already exists error", err)
+	}
+	for name, src := range files {
+		if got, _ := os.ReadFile(filepath.Join(dir, name)); string(got) != src {
+			t.Errorf("%s changed despite the failed backup: %q", name, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.go.orig")); !os.IsNotExist(err) {
+		t.Errorf("a.go backed up despite the failed backup: %v", err)
+	}
+	if got, _ := os.ReadFile(existing); string(got) != "older backup" {
+		t.Errorf("existing backup overwritten: %q", got)
+	}
+
+	// -force-backup overwrites it, with the mode of the file
+	if _, err := fixInPlace(results, nil, &backupPolicy{suffix: ".orig", force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(existing); string(got) != files["b.go"] {
+		t.Errorf("forced backup = %q, want %q", got, files["b.go"])
+	}
+	info, err := os.Stat(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o640 {
+		t.Errorf("forced backup has mode %v, want 0640", perm)
+	}
+}
+
+func TestFixInPlaceBackupFailure(t *testing.T) {
+	files := map[string]string{
+		"a.go": "package p\n\nvar request string\n",
+		"b.go": "package p\n\nfunc use() string { return request }\n",
+	}
+	dir, results := writeBackupFiles(t, files)
+
+	// The backup of b.go cannot be written over a directory, even when forced
+	if err := os.Mkdir(filepath.Join(dir, "b.go.orig"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := fixInPlace(results, nil, &backupPolicy{suffix: ".orig", force: true})
+	if err == nil {
+		t.Fatal("fixInPlace() succeeded without a backup of b.go")
+	}
+	if len(fixed.files) != 0 {
+		t.Errorf("fixInPlace() reported %v as changed", fixed.files)
+	}
+	for name, src := range files {
+		if got, _ := os.ReadFile(filepath.Join(dir, name)); string(got) != src {
+			t.Errorf("%s changed despite the failed backup: %q", name, got)
+		}
+	}
+	// The backup of a.go, written first, is removed again
+	if _, err := os.Stat(filepath.Join(dir, "a.go.orig")); !os.IsNotExist(err) {
+		t.Errorf("backup of a.go left after the failure: %v", err)
+	}
+}
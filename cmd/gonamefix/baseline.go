@@ -0,0 +1,250 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// baselineFile is a snapshot of findings that -baseline suppresses.
+type baselineFile struct {
+	Version  int             `json:"version"`
+	Findings []baselineEntry `json:"findings"`
+}
+
+// baselineEntry is a finding of a baseline. Its fingerprint does not
+// depend on line numbers, so that edits elsewhere in the file keep it.
+type baselineEntry struct {
+	File       string `json:"file"`
+	Identifier string `json:"identifier,omitempty"`
+	Rule       string `json:"rule,omitempty"`
+	// Declaration is the top-level declaration the finding is in, such as
+	// "func handle" or "method Server.Serve"
+	Declaration string `json:"declaration,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// baselineEntries describes the findings of results, as entries of the same
+// index as each diagnostic of each result. The fingerprint of an entry
+// hashes its file, declaration, identifier and rule, and its position among
+// the findings sharing all four.
+func baselineEntries(results []fileResult) [][]baselineEntry {
+	sources := make(map[string][]byte)
+	entries := make([][]baselineEntry, len(results))
+	for i, result := range results {
+		decls := topLevelDecls(result.filename)
+		occurrences := make(map[string]int)
+		for _, d := range result.diagnostics {
+			v := violation{fset: result.fset, diagnostic: d}
+			entry := baselineEntry{
+				File:        filepath.ToSlash(relativePath(result.filename)),
+				Identifier:  findingText(v, sources),
+				Rule:        d.Category,
+				Declaration: enclosingDecl(decls, result.fset.Position(d.Pos).Offset),
+			}
+			// Findings without a flagged identifier are told apart by their message
+			what := entry.Identifier
+			if what == "" {
+				what = d.Message
+			}
+			key := entry.File + "\x00" + entry.Declaration + "\x00" + what + "\x00" + entry.Rule
+			sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(occurrences[key])))
+			occurrences[key]++
+			entry.Fingerprint = hex.EncodeToString(sum[:16])
+			entries[i] = append(entries[i], entry)
+		}
+	}
+	return entries
+}
+
+// declSpan is a top-level declaration by name and byte offsets.
+type declSpan struct {
+	name       string
+	start, end int
+}
+
+// topLevelDecls returns the top-level declarations of the Go file filename,
+// none if it cannot be parsed.
+func topLevelDecls(filename string) []declSpan {
+	if isMarkdown(filename) {
+		return nil
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	var decls []declSpan
+	for _, decl := range file.Decls {
+		decls = append(decls, declSpan{
+			name:  declName(decl),
+			start: fset.Position(decl.Pos()).Offset,
+			end:   fset.Position(decl.End()).Offset,
+		})
+	}
+	return decls
+}
+
+// declName names a top-level declaration by its kind and first name.
+func declName(decl ast.Decl) string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv != nil && len(decl.Recv.List) > 0 {
+			return "method " + receiverTypeName(decl.Recv.List[0].Type) + "." + decl.Name.Name
+		}
+		return "func " + decl.Name.Name
+	case *ast.GenDecl:
+		if len(decl.Specs) == 0 {
+			return decl.Tok.String()
+		}
+		switch spec := decl.Specs[0].(type) {
+		case *ast.TypeSpec:
+			return "type " + spec.Name.Name
+		case *ast.ValueSpec:
+			return decl.Tok.String() + " " + spec.Names[0].Name
+		}
+		return decl.Tok.String()
+	}
+	return ""
+}
+
+// receiverTypeName returns the name of the type of a receiver, without
+// pointer and type parameters.
+func receiverTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// enclosingDecl returns the name of the declaration of decls containing
+// offset, or "" outside of declarations.
+func enclosingDecl(decls []declSpan, offset int) string {
+	for _, decl := range decls {
+		if decl.start <= offset && offset < decl.end {
+			return decl.name
+		}
+	}
+	return ""
+}
+
+// writeBaseline snapshots the findings of results to path and returns how
+// many it wrote.
+func writeBaseline(path string, results []fileResult) (int, error) {
+	baseline := baselineFile{Version: baselineVersion, Findings: []baselineEntry{}}
+	for _, entries := range baselineEntries(results) {
+		baseline.Findings = append(baseline.Findings, entries...)
+	}
+	return len(baseline.Findings), saveBaseline(path, baseline)
+}
+
+func saveBaseline(path string, baseline baselineFile) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// readBaseline reads the baseline file at path.
+func readBaseline(path string) (baselineFile, error) {
+	var baseline baselineFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return baseline, err
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return baseline, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	if baseline.Version != baselineVersion {
+		return baseline, fmt.Errorf("invalid baseline %s: unsupported version %d", path, baseline.Version)
+	}
+	return baseline, nil
+}
+
+// baselineMatch is what applyBaseline found.
+type baselineMatch struct {
+	// suppressed is the number of findings in the baseline
+	suppressed int
+	// current is the baseline without the stale entries: those of analyzed
+	// files that no finding matches anymore
+	current baselineFile
+}
+
+// applyBaseline removes the findings of results that are in baseline. Each
+// entry suppresses one finding with its fingerprint.
+func applyBaseline(baseline baselineFile, results []fileResult) ([]fileResult, baselineMatch) {
+	remaining := make(map[string]int)
+	for _, entry := range baseline.Findings {
+		remaining[entry.Fingerprint]++
+	}
+
+	var match baselineMatch
+	used := make(map[string]int)
+	analyzed := make(map[string]bool)
+	filtered := make([]fileResult, len(results))
+	for i, entries := range baselineEntries(results) {
+		result := results[i]
+		analyzed[filepath.ToSlash(relativePath(result.filename))] = true
+		filtered[i] = result
+		filtered[i].diagnostics = nil
+		for j, entry := range entries {
+			if remaining[entry.Fingerprint] > 0 {
+				remaining[entry.Fingerprint]--
+				used[entry.Fingerprint]++
+				match.suppressed++
+				continue
+			}
+			filtered[i].diagnostics = append(filtered[i].diagnostics, result.diagnostics[j])
+		}
+	}
+
+	// Entries of files this run did not analyze may still be current
+	match.current = baselineFile{Version: baselineVersion, Findings: []baselineEntry{}}
+	for _, entry := range baseline.Findings {
+		if !analyzed[entry.File] {
+			match.current.Findings = append(match.current.Findings, entry)
+		} else if used[entry.Fingerprint] > 0 {
+			used[entry.Fingerprint]--
+			match.current.Findings = append(match.current.Findings, entry)
+		}
+	}
+	return filtered, match
+}
+
+// loadBaseline applies the baseline at path to results, and prunes its stale
+// entries from the file with prune. It returns the results left to report.
+func loadBaseline(path string, results []fileResult, prune bool) ([]fileResult, baselineMatch, error) {
+	baseline, err := readBaseline(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return results, baselineMatch{}, fmt.Errorf("baseline %s does not exist; create it with -write-baseline", path)
+	}
+	if err != nil {
+		return results, baselineMatch{}, err
+	}
+	filtered, match := applyBaseline(baseline, results)
+	if prune {
+		if err := saveBaseline(path, match.current); err != nil {
+			return filtered, match, err
+		}
+	}
+	return filtered, match, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "a.go")
+	baselinePath := filepath.Join(dir, "baseline.json")
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	analyze := func(src string) []fileResult {
+		t.Helper()
+		if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []fileResult{result}
+	}
+
+	written, err := writeBaseline(baselinePath, analyze("package p\n\nfunc handle(request string) {}\n\nvar requestCount int\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 2 {
+		t.Fatalf("writeBaseline() wrote %d findings, want 2", written)
+	}
+
+	// Lines inserted above the findings keep them in the baseline, a new one is reported
+	results, match, err := loadBaseline(baselinePath,
+		analyze("package p\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n\nfunc handle(request string) {}\n\nfunc serve(request string) {}\n"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.suppressed != 1 {
+		t.Errorf("loadBaseline() suppressed %d findings, want 1", match.suppressed)
+	}
+	if len(results[0].diagnostics) != 1 || results[0].fset.Position(results[0].diagnostics[0].Pos).Line != 9 {
+		t.Errorf("loadBaseline() kept %+v, want the finding in serve", results[0].diagnostics)
+	}
+
+	// requestCount was removed, so pruning dropped its entry
+	pruned, err := readBaseline(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned.Findings) != 1 || pruned.Findings[0].Declaration != "func handle" || pruned.Findings[0].Identifier != "request" {
+		t.Errorf("pruned baseline = %+v, want the entry of handle", pruned.Findings)
+	}
+}
+
+func TestApplyBaselineKeepsUnanalyzedFiles(t *testing.T) {
+	baseline := baselineFile{Version: baselineVersion, Findings: []baselineEntry{{File: "other.go", Fingerprint: "0"}}}
+	_, match := applyBaseline(baseline, nil)
+	if len(match.current.Findings) != 1 {
+		t.Errorf("applyBaseline() pruned the entry of a file that was not analyzed")
+	}
+}
+
+func TestTopLevelDecls(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "a.go")
+	src := "package p\n\nfunc f() {}\n\nfunc (s *Set[T]) Add() {}\n\ntype T int\n\nconst (\n\tA = 1\n\tB = 2\n)\n"
+	if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, decl := range topLevelDecls(filename) {
+		got = append(got, decl.name)
+	}
+	want := []string{"func f", "method Set.Add", "type T", "const A"}
+	if !slices.Equal(got, want) {
+		t.Errorf("topLevelDecls() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"go/build"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// buildContext returns the host build context with the extra build tags
+// given to -tags, separated by commas or spaces like go build -tags.
+func buildContext(tags string) build.Context {
+	ctx := build.Default
+	ctx.BuildTags = append([]string(nil), ctx.BuildTags...)
+	ctx.BuildTags = append(ctx.BuildTags, strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})...)
+	return ctx
+}
+
+// buildSelection is the set of builds whose files selectBuildFiles keeps.
+type buildSelection int
+
+const (
+	// buildHost keeps the files of a build on the host
+	buildHost buildSelection = iota
+	// buildAllConfigs keeps the files of any build: of any combination of
+	// the build tags their //go:build lines mention, on any GOOS and GOARCH
+	buildAllConfigs
+	// buildAllFiles keeps every file
+	buildAllFiles
+)
+
+// buildSelectionOf returns the selection asked for by -all-files and
+// -all-build-configs.
+func buildSelectionOf(allFiles, allBuildConfigs bool) buildSelection {
+	switch {
+	case allFiles:
+		return buildAllFiles
+	case allBuildConfigs:
+		return buildAllConfigs
+	}
+	return buildHost
+}
+
+// selectBuildFiles returns the files that are part of a build in ctx, judged
+// by their //go:build lines and GOOS/GOARCH file name suffixes, and beyond
+// those the files of the other builds of selection. The files that would
+// not build in ctx are mapped to the constraint excluding them so
+// diagnostics can name it. Files that cannot be read are kept for
+// analyzeFile to report.
+func selectBuildFiles(ctx build.Context, files []string, selection buildSelection) ([]string, map[string]string) {
+	var selected []string
+	constraints := make(map[string]string)
+	for _, filename := range files {
+		dir, name := filepath.Split(filename)
+		match, err := ctx.MatchFile(dir, name)
+		if err != nil || match {
+			selected = append(selected, filename)
+			continue
+		}
+		if selection == buildAllFiles || selection == buildAllConfigs && satisfiableConstraint(filename) {
+			selected = append(selected, filename)
+			constraints[filename] = constraintLabel(ctx, filename)
+			continue
+		}
+		verbosef("%s: not built, %s (use -all-files)", filename, constraintLabel(ctx, filename))
+	}
+	return selected, constraints
+}
+
+// maxConstraintTags bounds the tags of a //go:build line whose combinations
+// satisfiableConstraint tries; lines with more are taken to be satisfiable.
+const maxConstraintTags = 12
+
+// satisfiableConstraint reports whether the //go:build line of filename
+// holds for some combination of the tags it mentions, so that some build
+// includes the file. File name suffixes always hold on their GOOS and
+// GOARCH. Files that cannot be parsed are reported as satisfiable.
+func satisfiableConstraint(filename string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return true
+	}
+	line := gonamefix.BuildConstraint(file)
+	if line == "" {
+		return true
+	}
+	expr, err := constraint.Parse("//go:build " + line)
+	if err != nil {
+		return true
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	expr.Eval(func(tag string) bool {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+		return false
+	})
+	if len(tags) > maxConstraintTags {
+		return true
+	}
+	for set := 0; set < 1<<len(tags); set++ {
+		if expr.Eval(func(tag string) bool {
+			for i, t := range tags {
+				if t == tag {
+					return set&(1<<i) != 0
+				}
+			}
+			return false
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintLabel describes why filename does not build in ctx: its
+// //go:build expression, or its file name suffix when it has none.
+func constraintLabel(ctx build.Context, filename string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err == nil {
+		if expr := gonamefix.BuildConstraint(file); expr != "" {
+			return "//go:build " + expr
+		}
+	}
+	return "file name, not built on " + ctx.GOOS + "/" + ctx.GOARCH
+}
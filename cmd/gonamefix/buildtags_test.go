@@ -0,0 +1,84 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSelectBuildFiles(t *testing.T) {
+	dir := filepath.Join("testdata", "buildtags")
+	plain := filepath.Join(dir, "plain.go")
+	windows := filepath.Join(dir, "conn_windows.go")
+	integration := filepath.Join(dir, "integration.go")
+	darwin := filepath.Join(dir, "darwin_nocgo.go")
+	never := filepath.Join(dir, "never.go")
+	files := []string{windows, integration, darwin, never, plain}
+
+	tests := []struct {
+		name            string
+		tags            string
+		selection       buildSelection
+		wantFiles       []string
+		wantConstraints map[string]string
+	}{
+		{
+			name:            "host only",
+			selection:       buildHost,
+			wantFiles:       []string{plain},
+			wantConstraints: map[string]string{},
+		},
+		{
+			name:            "with tags",
+			tags:            "integration,tools",
+			selection:       buildHost,
+			wantFiles:       []string{integration, plain},
+			wantConstraints: map[string]string{},
+		},
+		{
+			name:      "all files",
+			selection: buildAllFiles,
+			wantFiles: files,
+			wantConstraints: map[string]string{
+				windows:     "file name, not built on linux/amd64",
+				integration: "//go:build integration",
+				darwin:      "//go:build (darwin || freebsd) && !cgo",
+				never:       "//go:build linux && !linux",
+			},
+		},
+		{
+			// Every build but none includes never.go
+			name:      "all build configs",
+			selection: buildAllConfigs,
+			wantFiles: []string{windows, integration, darwin, plain},
+			wantConstraints: map[string]string{
+				windows:     "file name, not built on linux/amd64",
+				integration: "//go:build integration",
+				darwin:      "//go:build (darwin || freebsd) && !cgo",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := buildContext(tt.tags)
+			ctx.GOOS, ctx.GOARCH = "linux", "amd64"
+
+			selected, constraints := selectBuildFiles(ctx, files, tt.selection)
+			if !reflect.DeepEqual(selected, tt.wantFiles) {
+				t.Errorf("selectBuildFiles() files = %v, want %v", selected, tt.wantFiles)
+			}
+			if !reflect.DeepEqual(constraints, tt.wantConstraints) {
+				t.Errorf("selectBuildFiles() constraints = %v, want %v", constraints, tt.wantConstraints)
+			}
+		})
+	}
+}
+
+func TestBuildContextTags(t *testing.T) {
+	ctx := buildContext("integration, tools e2e")
+	want := []string{"integration", "tools", "e2e"}
+	if !reflect.DeepEqual(ctx.BuildTags, want) {
+		t.Errorf("buildContext() tags = %v, want %v", ctx.BuildTags, want)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkList is the value of -check, which can be repeated. Every occurrence
+// holds one or more old:new mappings separated by commas.
+type checkList []string
+
+func (c *checkList) String() string { return strings.Join(*c, ",") }
+
+func (c *checkList) Set(value string) error {
+	*c = append(*c, value)
+	return nil
+}
+
+// parseCheck returns the mappings of the -check occurrences in values, in
+// order.
+func parseCheck(values []string) ([][]string, error) {
+	var pairs [][]string
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		for _, pair := range strings.Split(value, ",") {
+			parts := strings.Split(pair, ":")
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid mapping format: %s (expected 'old:new')", pair)
+			}
+			pairs = append(pairs, []string{strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])})
+		}
+	}
+	return pairs, nil
+}
+
+// checkDuplicates reports a word mapped twice by the -check mappings in
+// pairs, within an occurrence or across them, instead of letting one mapping
+// silently win, like in a mapping file. Words differing in case only are the
+// same word unless matching is caseSensitive.
+func checkDuplicates(pairs [][]string, caseSensitive bool) error {
+	mapped := make(map[string]string)
+	for _, pair := range pairs {
+		key := pair[0]
+		if !caseSensitive {
+			key = strings.ToLower(key)
+		}
+		mapping := pair[0] + ":" + pair[1]
+		if first, ok := mapped[key]; ok {
+			return fmt.Errorf("-check maps %s twice: %s and %s", pair[0], first, mapping)
+		}
+		mapped[key] = mapping
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckList(t *testing.T) {
+	var list checkList
+	for _, value := range []string{"request:req", "response:res,password:pwd"} {
+		if err := list.Set(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := list.String(), "request:req,response:res,password:pwd"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    [][]string
+		wantErr string
+	}{
+		{name: "none"},
+		{
+			name:   "comma-joined",
+			values: []string{"request:req, response : res"},
+			want:   [][]string{{"request", "req"}, {"response", "res"}},
+		},
+		{
+			name:   "repeated",
+			values: []string{"request:req", "response:res,password:pwd"},
+			want:   [][]string{{"request", "req"}, {"response", "res"}, {"password", "pwd"}},
+		},
+		{
+			name:    "malformed",
+			values:  []string{"request:req", "response"},
+			wantErr: "invalid mapping format: response (expected 'old:new')",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCheck(tt.values)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseCheck(%q) error = %v, want %q", tt.values, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCheck(%q) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDuplicates(t *testing.T) {
+	tests := []struct {
+		name          string
+		pairs         [][]string
+		caseSensitive bool
+		wantErr       string
+	}{
+		{name: "distinct", pairs: [][]string{{"request", "req"}, {"response", "res"}}},
+		{
+			name:    "mapped twice",
+			pairs:   [][]string{{"request", "req"}, {"Request", "rq"}},
+			wantErr: "-check maps Request twice: request:req and Request:rq",
+		},
+		{
+			name:    "same mapping twice",
+			pairs:   [][]string{{"request", "req"}, {"request", "req"}},
+			wantErr: "-check maps request twice: request:req and request:req",
+		},
+		{
+			name:          "case-sensitive",
+			pairs:         [][]string{{"Request", "Req"}, {"request", "req"}},
+			caseSensitive: true,
+		},
+		{
+			name:          "case-sensitive mapped twice",
+			pairs:         [][]string{{"request", "req"}, {"request", "rq"}},
+			caseSensitive: true,
+			wantErr:       "-check maps request twice: request:req and request:rq",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDuplicates(tt.pairs, tt.caseSensitive)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("checkDuplicates(%q, %t) = %v, want %q", tt.pairs, tt.caseSensitive, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// checkstyleVersion is the checkstyle version whose format -format checkstyle
+// follows, as CI tools expect it in the root element.
+const checkstyleVersion = "4.3"
+
+// checkstyleReport is the document written by -format checkstyle.
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// newCheckstyleReport describes results with a file element for every
+// analyzed file, empty for the files without findings, so that tools
+// tracking files over time see them fixed rather than gone.
+func newCheckstyleReport(results []fileResult) checkstyleReport {
+	report := checkstyleReport{Version: checkstyleVersion, Files: make([]checkstyleFile, 0, len(results))}
+	for _, result := range results {
+		file := checkstyleFile{Name: displayPath(result.filename)}
+		for _, d := range result.diagnostics {
+			pos := result.fset.Position(d.Pos)
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Severity: severityOf(d),
+				Message:  d.Message,
+				Source:   "gonamefix",
+			})
+		}
+		report.Files = append(report.Files, file)
+	}
+	return report
+}
+
+// writeCheckstyle writes results to out as a checkstyle XML document.
+func writeCheckstyle(out io.Writer, results []fileResult) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(newCheckstyleReport(results)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
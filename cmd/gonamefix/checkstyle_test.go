@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestWriteCheckstyle(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	var results []fileResult
+	for _, name := range []string{"nested/main.go", "nested/pkg/pkg.go"} {
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filepath.Join("testdata", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+
+	var out bytes.Buffer
+	if err := writeCheckstyle(&out, results); err != nil {
+		t.Fatal(err)
+	}
+	var got checkstyleReport
+	if err := xml.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML %q: %v", out.String(), err)
+	}
+	want := checkstyleReport{
+		XMLName: xml.Name{Local: "checkstyle"},
+		Version: checkstyleVersion,
+		Files: []checkstyleFile{
+			{Name: results[0].filename, Errors: []checkstyleError{{
+				Line: 3, Column: 5, Severity: "error", Message: "suggest replacing 'request' with 'req'", Source: "gonamefix",
+			}}},
+			// Files without findings are kept, empty
+			{Name: results[1].filename},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("writeCheckstyle() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteCheckstyleEscaping(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile(`a&b<"c">.go`, -1, 10)
+	message := `suggest replacing 'x<y' with "a&b"`
+	results := []fileResult{{
+		filename:    file.Name(),
+		fset:        fset,
+		diagnostics: []analysis.Diagnostic{{Pos: file.Pos(0), Message: message}},
+	}}
+
+	var out bytes.Buffer
+	if err := writeCheckstyle(&out, results); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "a&b<") {
+		t.Errorf("writeCheckstyle() did not escape the file name: %s", out.String())
+	}
+	var got checkstyleReport
+	if err := xml.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML %q: %v", out.String(), err)
+	}
+	if got.Files[0].Name != file.Name() || got.Files[0].Errors[0].Message != message {
+		t.Errorf("writeCheckstyle() round trip = %+v", got.Files[0])
+	}
+
+	out.Reset()
+	if err := writeCheckstyle(&out, nil); err != nil || !strings.Contains(out.String(), `<checkstyle version="4.3"></checkstyle>`) {
+		t.Errorf("writeCheckstyle() without files = %q, %v", out.String(), err)
+	}
+}
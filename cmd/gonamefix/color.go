@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences of the colors of the text format.
+const (
+	colorReset      = "\033[0m"
+	colorPosition   = "\033[1m"
+	colorIdentifier = "\033[31m"
+	colorSuggestion = "\033[32m"
+	colorLabel      = "\033[2m"
+	colorWarning    = "\033[33m"
+	colorInfo       = "\033[36m"
+)
+
+// colorModes are the values of -color.
+var colorModes = []string{"auto", "always", "never"}
+
+// useColor reports whether output to out is colored in mode. In auto mode
+// only a terminal is, unless NO_COLOR is set or TERM is dumb.
+func useColor(mode string, out *os.File) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(out)
+}
+
+// textWriter prints findings in the text format, colored or not. The other
+// formats write to their output directly and are never colored.
+type textWriter struct {
+	out   io.Writer
+	color bool
+}
+
+func newTextWriter(out io.Writer, color bool) *textWriter {
+	return &textWriter{out: out, color: color}
+}
+
+// paint wraps s in the color sequence, when colored.
+func (w *textWriter) paint(color, s string) string {
+	if !w.color || s == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// diagnostic prints v, labeled with its severity unless it is an error and
+// with its file's build constraint when the file would not build on the
+// host. With -show-related its related information follows, indented.
+func (w *textWriter) diagnostic(v violation) {
+	pos := v.fset.Position(v.diagnostic.Pos)
+	location := w.paint(colorPosition, fmt.Sprintf("%s:%d:%d:", displayPath(pos.Filename), pos.Line, pos.Column))
+	// Errors, the default, are printed without a label
+	switch v.severity {
+	case severityWarning:
+		location += " " + w.paint(colorWarning, "warning:")
+	case severityInfo:
+		location += " " + w.paint(colorInfo, "info:")
+	}
+	if v.constraint != "" {
+		fmt.Fprintf(w.out, "%s %s %s\n", location, w.message(v.diagnostic.Message), w.paint(colorLabel, "["+v.constraint+"]"))
+	} else {
+		fmt.Fprintf(w.out, "%s %s\n", location, w.message(v.diagnostic.Message))
+	}
+
+	if *showRelatedFlag {
+		for _, related := range v.diagnostic.Related {
+			pos := v.fset.Position(related.Pos)
+			fmt.Fprintf(w.out, "    %s %s\n", w.paint(colorPosition, fmt.Sprintf("%s:%d:%d:", displayPath(pos.Filename), pos.Line, pos.Column)), related.Message)
+		}
+	}
+}
+
+// message highlights the names quoted in message: the first one, the
+// flagged identifier, and the suggestion following "with".
+func (w *textWriter) message(message string) string {
+	if !w.color {
+		return message
+	}
+	var b strings.Builder
+	rest := message
+	for quoted := 0; ; quoted++ {
+		start := strings.IndexByte(rest, '\'')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start+1:], '\'')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+		name := rest[start+1 : end]
+		switch {
+		case strings.HasSuffix(rest[:start], "with "):
+			name = w.paint(colorSuggestion, name)
+		case quoted == 0:
+			name = w.paint(colorIdentifier, name)
+		}
+		b.WriteString(rest[:start+1] + name + "'")
+		rest = rest[end+1:]
+	}
+	b.WriteString(rest)
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestTextWriterColor(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("handler.go", -1, 100)
+	file.SetLines([]int{0, 10, 20, 30, 40})
+	v := violation{fset: fset, diagnostic: analysis.Diagnostic{
+		Pos:     file.Pos(45),
+		Message: "suggest replacing 'processRequest' with 'processReq'",
+	}, constraint: "//go:build linux"}
+
+	var out bytes.Buffer
+	newTextWriter(&out, true).diagnostic(v)
+	want := "\033[1mhandler.go:5:6:\033[0m suggest replacing '\033[31mprocessRequest\033[0m' with '\033[32mprocessReq\033[0m' \033[2m[//go:build linux]\033[0m\n"
+	if out.String() != want {
+		t.Errorf("colored diagnostic = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	newTextWriter(&out, false).diagnostic(v)
+	if want := "handler.go:5:6: suggest replacing 'processRequest' with 'processReq' [//go:build linux]\n"; out.String() != want {
+		t.Errorf("plain diagnostic = %q, want %q", out.String(), want)
+	}
+}
+
+func TestTextWriterMessage(t *testing.T) {
+	w := newTextWriter(nil, true)
+	tests := []struct {
+		message, want string
+	}{
+		{"identifier 'requestHandlerFactory' is longer than 20 characters", "identifier '\033[31mrequestHandlerFactory\033[0m' is longer than 20 characters"},
+		{"no quotes", "no quotes"},
+		{"unterminated 'quote", "unterminated 'quote"},
+	}
+	for _, tt := range tests {
+		if got := w.message(tt.message); got != tt.want {
+			t.Errorf("message(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	if useColor("auto", f) {
+		t.Error("useColor(auto) colors a regular file")
+	}
+	if !useColor("always", f) || useColor("never", f) {
+		t.Error("useColor() does not force always and never")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if !useColor("always", f) {
+		t.Error("NO_COLOR overrides -color always")
+	}
+}
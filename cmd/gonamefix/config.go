@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/xbpk3t/gonamefix"
+	"github.com/xbpk3t/gonamefix/internal/yaml"
+)
+
+// readConfigFile decodes the YAML configuration file at path over config.
+// Settings the file does not mention keep their value.
+func readConfigFile(path string, config *gonamefix.Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading configuration: %w", err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+	return nil
+}
+
+// overrideSetFlags copies the settings of flagConfig whose flags are in set,
+// the flags given on the command line, to config. Flags are named after the
+// configuration keys they set.
+func overrideSetFlags(config *gonamefix.Config, flagConfig gonamefix.Config, set map[string]bool) {
+	dst := reflect.ValueOf(config).Elem()
+	src := reflect.ValueOf(flagConfig)
+	for i := 0; i < dst.NumField(); i++ {
+		key, _, _ := strings.Cut(dst.Type().Field(i).Tag.Get("mapstructure"), ",")
+		if key != "" && key != "-" && set[key] {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
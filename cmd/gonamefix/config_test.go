@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	config := gonamefix.Config{ExcludeFiles: []string{"*_test.go"}, ExcludeDirs: []string{".git"}, MaxRelated: 5}
+	if err := readConfigFile(filepath.Join("testdata", "config", "gonamefix.yml"), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	want := gonamefix.Config{
+		Check:         [][]string{{"request", "req"}, {"response", "res"}},
+		Mappings:      []gonamefix.Mapping{{Original: "password", Replacement: "pwd", Kinds: []string{"var", "param"}}},
+		ExcludeFiles:  []string{"*.pb.go"},
+		ExcludeDirs:   []string{"testdata"},
+		CaseSensitive: true,
+		MaxLength:     30,
+		Exclude:       []gonamefix.ExcludeRule{{Pattern: "**/zz_generated.go", Reason: "generated"}},
+		// Not in the file
+		MaxRelated: 5,
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("readConfigFile() = %+v\nwant %+v", config, want)
+	}
+}
+
+func TestReadConfigFileErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gonamefix.yml")
+	if err := os.WriteFile(path, []byte("check:\n  - [request, req]\nexclude-file: ['*.go']\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var config gonamefix.Config
+	err := readConfigFile(path, &config)
+	if err == nil || !strings.Contains(err.Error(), path+": line 3: unknown key \"exclude-file\"") {
+		t.Errorf("readConfigFile() = %v, want the unknown key reported with the path", err)
+	}
+
+	if err := readConfigFile(filepath.Join(t.TempDir(), "missing.yml"), &config); err == nil {
+		t.Error("readConfigFile() of a missing file returned no error")
+	}
+}
+
+func TestOverrideSetFlags(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}, CaseSensitive: true, MaxLength: 30}
+	flagConfig := gonamefix.Config{Check: [][]string{{"user", "usr"}}, MaxLength: 20}
+
+	overrideSetFlags(&config, flagConfig, map[string]bool{"check": true, "tags": true})
+	want := gonamefix.Config{Check: [][]string{{"user", "usr"}}, CaseSensitive: true, MaxLength: 30}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("overrideSetFlags() = %+v, want %+v", config, want)
+	}
+}
+
+// The settings documented in the golangci-lint reference are a valid
+// configuration file.
+func TestReadReferenceConfig(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", ".golangci.next.reference.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, section, ok := strings.Cut(string(data), "\n  gonamefix:\n")
+	if !ok {
+		t.Fatal("no gonamefix section in the reference")
+	}
+	var lines []string
+	for _, line := range strings.Split(section, "\n") {
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "    ") {
+			break
+		}
+		lines = append(lines, strings.TrimPrefix(line, "    "))
+	}
+
+	path := filepath.Join(t.TempDir(), "gonamefix.yml")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var config gonamefix.Config
+	if err := readConfigFile(path, &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Check) == 0 || len(config.Mappings) == 0 {
+		t.Errorf("reference configuration has no mappings: %+v", config)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("reference configuration is invalid: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// runCoverage prints how many identifiers each configured mapping matches in
+// files, most used first, and warns about mappings that match nothing.
+func runCoverage(out, warnings io.Writer, files []string, config gonamefix.Config) error {
+	coverage, err := gonamefix.PatternCoverage(files, config)
+	if err != nil {
+		return err
+	}
+
+	replacements := make(map[string]string)
+	for _, pair := range config.MappingPairs() {
+		if len(pair) == 2 {
+			replacements[pair[0]] = pair[1]
+		}
+	}
+
+	originals := make([]string, 0, len(coverage))
+	for original := range coverage {
+		originals = append(originals, original)
+	}
+	sort.Slice(originals, func(i, j int) bool {
+		if coverage[originals[i]] != coverage[originals[j]] {
+			return coverage[originals[i]] > coverage[originals[j]]
+		}
+		return originals[i] < originals[j]
+	})
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATTERN\tMATCHES")
+	for _, original := range originals {
+		fmt.Fprintf(w, "%s\t%d\n", gonamefix.MappingCategory(original, replacements[original]), coverage[original])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, original := range originals {
+		if coverage[original] == 0 {
+			fmt.Fprintf(warnings, "warning: pattern '%s' matched 0 identifiers — consider removing\n",
+				gonamefix.MappingCategory(original, replacements[original]))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/csv"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// csvHeader names the columns written by -format csv.
+var csvHeader = []string{"file", "line", "column", "identifier", "suggestion", "mapping", "kind"}
+
+// writeCSV writes violations to out as CSV with a header row, one row per
+// finding, sorted by file and position so that repeated exports diff
+// cleanly. The mapping is written as in -check, e.g. request:req, and the
+// rule ID for findings of other rules. The kind is that of the declaration
+// flagged, if the finding is one, read from a parse of the analyzed source
+// in results, or of the file on disk for results without one.
+func writeCSV(out io.Writer, violations []violation, results []fileResult, config gonamefix.Config) error {
+	mappings := ruleNames(config)
+	sources := make(map[string][]byte)
+	for _, result := range results {
+		if result.src != nil {
+			sources[result.filename] = result.src
+		}
+	}
+	kinds := make(map[string]map[[2]int]string)
+
+	type row struct {
+		file         string
+		line, column int
+		record       []string
+	}
+	rows := make([]row, 0, len(violations))
+	for _, v := range violations {
+		pos := v.fset.Position(v.diagnostic.Pos)
+		mapping, ok := mappings[v.diagnostic.Category]
+		if !ok {
+			mapping = v.diagnostic.Category
+		}
+		identifier := findingText(v, sources)
+		fileKinds, ok := kinds[pos.Filename]
+		if !ok {
+			if _, read := sources[pos.Filename]; !read {
+				sources[pos.Filename], _ = os.ReadFile(pos.Filename)
+			}
+			fileKinds = declarationKinds(pos.Filename, sources[pos.Filename], config)
+			kinds[pos.Filename] = fileKinds
+		}
+		file := displayPath(pos.Filename)
+		rows = append(rows, row{file, pos.Line, pos.Column, []string{
+			file,
+			strconv.Itoa(pos.Line),
+			strconv.Itoa(pos.Column),
+			identifier,
+			suggestion(v),
+			mapping,
+			fileKinds[[2]int{pos.Line, pos.Column}],
+		}})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return a.column < b.column
+	})
+
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := w.Write(r.record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// declarationKinds returns the kinds of the identifiers declared in src, the
+// content of filename, by line and column. Content that does not parse
+// declares nothing.
+func declarationKinds(filename string, src []byte, config gonamefix.Config) map[[2]int]string {
+	kinds := make(map[[2]int]string)
+	if src == nil {
+		return kinds
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return kinds
+	}
+	identifiers, err := gonamefix.Inventory(fset, []*ast.File{file}, config)
+	if err != nil {
+		return kinds
+	}
+	for _, identifier := range identifiers {
+		kinds[[2]int{identifier.Line, identifier.Column}] = identifier.Kind
+	}
+	return kinds
+}
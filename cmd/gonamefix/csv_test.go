@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestWriteCSV(t *testing.T) {
+	dir := t.TempDir()
+	// The name needs quoting in CSV
+	filename := filepath.Join(dir, `a,"b".go`)
+	src := "package p\n\ntype requestInfo struct {\n\tresponseCode int\n}\n\nfunc handleRequest() {}\n\nvar request string\n"
+	if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}}}
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations := collectViolations([]fileResult{result})
+	// The rows are sorted whatever the order of the findings
+	slices.Reverse(violations)
+	// The kinds are those of the analyzed source, not of the file on disk
+	if err := os.Remove(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := writeCSV(&out, violations, []fileResult{result}, config); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV %q: %v", out.String(), err)
+	}
+	want := [][]string{
+		csvHeader,
+		{filename, "3", "6", "requestInfo", "reqInfo", "request:req", "type"},
+		{filename, "4", "2", "responseCode", "resCode", "response:res", "field"},
+		{filename, "7", "6", "handleRequest", "handleReq", "request:req", "func"},
+		{filename, "9", "5", "request", "req", "request:req", "var"},
+	}
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Errorf("writeCSV() = %q, want %q", records, want)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{
+			name:   "equal",
+			before: "a\nb\n",
+			after:  "a\nb\n",
+		},
+		{
+			name:   "changes far apart get their own hunks",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			after:  "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			want:   "--- a/f.go\n+++ b/f.go\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+		{
+			name:   "changes close together share a hunk",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			after:  "1\nx\n3\n4\n5\n6\ny\n8\n",
+			want:   "--- a/f.go\n+++ b/f.go\n@@ -1,8 +1,8 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n-7\n+y\n 8\n",
+		},
+		{
+			name:   "insertion into an empty file",
+			before: "",
+			after:  "a\n",
+			want:   "--- a/f.go\n+++ b/f.go\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:   "no newline at end of file",
+			before: "a\nb",
+			after:  "a\nc",
+			want:   "--- a/f.go\n+++ b/f.go\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a/f.go", "b/f.go", []byte(tt.before), []byte(tt.after)); got != tt.want {
+				t.Errorf("unifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(3)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range diffLines(a, b) {
+			if op.kind != '+' {
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != '-' {
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != ' ' {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("diffLines(%q, %q) does not turn one into the other", a, b)
+		}
+		// A shortest script keeps a longest common subsequence
+		if want := len(a) + len(b) - 2*lcsLength(a, b); edits != want {
+			t.Fatalf("diffLines(%q, %q) has %d edits, want %d", a, b, edits, want)
+		}
+	}
+}
+
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func TestComputeFixesLeavesFiles(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "a.go")
+	src := "package p\n\nfunc handle(request string) string {\n\treturn request\n}\n"
+	if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := computeFixes([]fileResult{result})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixed.files) != 1 || string(fixed.files[0].src) != src {
+		t.Fatalf("computeFixes() = %+v, want a.go with its content", fixed)
+	}
+
+	want := "--- a/a.go\n+++ b/a.go\n@@ -1,5 +1,5 @@\n package p\n \n-func handle(request string) string {\n-\treturn request\n+func handle(req string) string {\n+\treturn req\n }\n"
+	if got := unifiedDiff("a/a.go", "b/a.go", fixed.files[0].src, fixed.files[0].fixed); got != want {
+		t.Errorf("diff of the fixes = %q, want %q", got, want)
+	}
+	if got, err := os.ReadFile(filename); err != nil || string(got) != src {
+		t.Errorf("computeFixes() changed a.go to %q, %v", got, err)
+	}
+}
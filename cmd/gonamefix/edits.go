@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// jsonFixEdit is an analysis.TextEdit as written by -format edits: the byte
+// range it replaces in the file on disk and its replacement.
+type jsonFixEdit struct {
+	File string `json:"file"`
+	// Offset and EndOffset are byte offsets; the replaced bytes are
+	// content[Offset:EndOffset]
+	Offset    int    `json:"offset"`
+	EndOffset int    `json:"end_offset"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	NewText   string `json:"new_text"`
+}
+
+// jsonFix is a fixed finding as written by -format edits.
+type jsonFix struct {
+	File    string        `json:"file"`
+	Line    int           `json:"line"`
+	Column  int           `json:"column"`
+	Rule    string        `json:"rule,omitempty"`
+	Message string        `json:"message"`
+	Edits   []jsonFixEdit `json:"edits"`
+}
+
+// newJSONFix describes fix with the edits of its first suggested fix, sorted
+// by file and offset.
+func newJSONFix(fix appliedFix) jsonFix {
+	pos := fix.fset.Position(fix.diagnostic.Pos)
+	described := jsonFix{
+		File:    displayPath(pos.Filename),
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Rule:    fix.diagnostic.Category,
+		Message: fix.diagnostic.Message,
+		Edits:   []jsonFixEdit{},
+	}
+	for _, edit := range fix.diagnostic.SuggestedFixes[0].TextEdits {
+		start := fix.fset.Position(edit.Pos)
+		end := start
+		if edit.End.IsValid() {
+			end = fix.fset.Position(edit.End)
+		}
+		described.Edits = append(described.Edits, jsonFixEdit{
+			File:      displayPath(start.Filename),
+			Offset:    start.Offset,
+			EndOffset: end.Offset,
+			Line:      start.Line,
+			Column:    start.Column,
+			EndLine:   end.Line,
+			EndColumn: end.Column,
+			NewText:   string(edit.NewText),
+		})
+	}
+	sort.Slice(described.Edits, func(i, j int) bool {
+		a, b := described.Edits[i], described.Edits[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Offset < b.Offset
+	})
+	return described
+}
+
+// writeEdits writes the fixes -fix would apply to out as an indented JSON
+// array, sorted by position. Together the edits turn the files on disk into
+// the files -fix writes; findings without a safe fix are left out.
+func writeEdits(out io.Writer, fixed fixResult) error {
+	fixes := make([]jsonFix, 0, len(fixed.applied))
+	for _, fix := range fixed.applied {
+		fixes = append(fixes, newJSONFix(fix))
+	}
+	sort.SliceStable(fixes, func(i, j int) bool {
+		a, b := fixes[i], fixes[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fixes)
+}
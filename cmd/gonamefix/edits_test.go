@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// TestEditsRoundTrip applies the edits written by -format edits to the files
+// on disk, the way an external tool would, and compares with -fix.
+func TestEditsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": "package p\n\nvar requestCount int\n\nfunc handle(request string) string {\n\trequestCount++\n\treturn request + request\n}\n",
+		"b.go": "package p\n\n// Exported names need a manual fix\nvar Request string\n\nfunc count() int { return requestCount }\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	var results []fileResult
+	for _, name := range []string{"a.go", "b.go"} {
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	fixed, err := computeFixes(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := writeEdits(&out, fixed); err != nil {
+		t.Fatal(err)
+	}
+	var fixes []jsonFix
+	if err := json.Unmarshal(out.Bytes(), &fixes); err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 2 {
+		t.Fatalf("writeEdits() wrote %d fixes, want the 2 safe ones:\n%s", len(fixes), out.String())
+	}
+
+	// Edits are applied from the end of each file, so that offsets stay valid
+	byFile := make(map[string][]jsonFixEdit)
+	for _, fix := range fixes {
+		for _, edit := range fix.Edits {
+			byFile[edit.File] = append(byFile[edit.File], edit)
+		}
+	}
+	if len(byFile) != len(fixed.files) {
+		t.Errorf("edits change %d files, -fix %d", len(byFile), len(fixed.files))
+	}
+	for _, file := range fixed.files {
+		src, err := os.ReadFile(file.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edits := byFile[file.filename]
+		sort.Slice(edits, func(i, j int) bool { return edits[i].Offset > edits[j].Offset })
+		for _, edit := range edits {
+			src = append(src[:edit.Offset:edit.Offset], append([]byte(edit.NewText), src[edit.EndOffset:]...)...)
+		}
+		if !bytes.Equal(src, file.fixed) {
+			t.Errorf("%s with the edits applied =\n%s\nwant the -fix result\n%s", file.filename, src, file.fixed)
+		}
+	}
+
+	first := fixes[0].Edits[0]
+	if first.Line != 3 || first.Column != 5 || first.EndColumn != 17 || first.NewText != "reqCount" {
+		t.Errorf("first edit = %+v, want requestCount at 3:5-3:17 replaced with reqCount", first)
+	}
+}
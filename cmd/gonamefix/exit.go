@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+// The exit codes of a run. A clean run exits 0.
+const (
+	// exitFindings is the exit code of findings that fail the run
+	exitFindings = 1
+	// exitUsage is the exit code of invalid flags, configurations and
+	// arguments, found before anything is analyzed
+	exitUsage = 2
+	// exitFailure is the exit code of a run that failed, e.g. on a file that
+	// could not be read or parsed, or a fix that could not be written
+	exitFailure = 3
+)
+
+// exitStatus returns the exit code of a run. A run that failed exits
+// exitFailure, whatever its findings. Findings only decide the exit code
+// where a mode makes them fail the run, like the files listed by -l or a
+// non-empty -diff; they exit exitFindings, unless exitZero reports them
+// without failing.
+func exitStatus(failed, failingFindings, exitZero bool) int {
+	if failed {
+		return exitFailure
+	}
+	if failingFindings && !exitZero {
+		return exitFindings
+	}
+	return 0
+}
+
+// fatalUsage logs an invalid use of the command and exits exitUsage.
+func fatalUsage(v ...any) {
+	log.Print(v...)
+	os.Exit(exitUsage)
+}
+
+// fatalUsagef is fatalUsage with a format.
+func fatalUsagef(format string, v ...any) {
+	log.Printf(format, v...)
+	os.Exit(exitUsage)
+}
+
+// fatal logs the failure of a run and exits exitFailure.
+func fatal(v ...any) {
+	log.Print(v...)
+	os.Exit(exitFailure)
+}
+
+// fatalf is fatal with a format.
+func fatalf(format string, v ...any) {
+	log.Printf(format, v...)
+	os.Exit(exitFailure)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		failed, findings, exitZero bool
+		want                       int
+	}{
+		{want: 0},
+		{exitZero: true, want: 0},
+		{findings: true, want: exitFindings},
+		{findings: true, exitZero: true, want: 0},
+		// Failures are never reported as success, nor as findings
+		{failed: true, want: exitFailure},
+		{failed: true, exitZero: true, want: exitFailure},
+		{failed: true, findings: true, want: exitFailure},
+		{failed: true, findings: true, exitZero: true, want: exitFailure},
+	}
+	for _, tt := range tests {
+		if got := exitStatus(tt.failed, tt.findings, tt.exitZero); got != tt.want {
+			t.Errorf("exitStatus(failed %t, findings %t, exit-zero %t) = %d, want %d", tt.failed, tt.findings, tt.exitZero, got, tt.want)
+		}
+	}
+}
+
+// TestMain runs the command instead of the tests when runMainEnv is set, so
+// that the tests below can check the exit codes of whole runs.
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMainEnv is set for the test binary to run the command.
+const runMainEnv = "GONAMEFIX_RUN_MAIN"
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"clean.go":    "package main\n\nfunc main() {}\n",
+		"findings.go": "package main\n\nfunc handle() {\n\trequestBody := 1\n\t_ = requestBody\n}\n",
+		"broken.go":   "package main\n\nfunc main( {\n",
+		"fixable.go":  "package main\n\nfunc fixable() {\n\trequestBody := 1\n\t_ = requestBody\n}\n",
+		"trend.json":  "[]\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"clean", []string{"-check", "request:req", "clean.go"}, 0},
+		{"findings", []string{"-check", "request:req", "findings.go"}, exitFindings},
+		{"findings in json", []string{"-check", "request:req", "-format", "json", "findings.go"}, exitFindings},
+		{"findings below -fail-severity", []string{"-check", "request:req", "-severity", "warning", "-fail-severity", "error", "findings.go"}, 0},
+		{"findings with -exit-zero", []string{"-check", "request:req", "-exit-zero", "findings.go"}, 0},
+		{"listed files", []string{"-check", "request:req", "-l", "findings.go"}, exitFindings},
+		{"exit zero", []string{"-check", "request:req", "-l", "-exit-zero", "findings.go"}, 0},
+		{"unknown flag", []string{"-no-such-flag", "clean.go"}, exitUsage},
+		{"invalid flag", []string{"-check", "request:req", "-format", "yaml", "clean.go"}, exitUsage},
+		{"invalid mapping", []string{"-check", "request", "clean.go"}, exitUsage},
+		{"mapped twice", []string{"-check", "request:req", "-check", "request:rq", "clean.go"}, exitUsage},
+		{"case-sensitive mappings", []string{"-case-sensitive", "-check", "Request:Req,request:req", "clean.go"}, 0},
+		{"repeated check", []string{"-check", "response:res", "-check", "request:req", "-fail-severity", "error", "findings.go"}, exitFindings},
+		{"no mappings", []string{"clean.go"}, exitUsage},
+		// Opt-in rules run without mappings, except the synonyms of mappings
+		{"synonym consistency only", []string{"-synonym-consistency", "clean.go"}, exitUsage},
+		{"synonym consistency", []string{"-synonym-consistency", "-check", "request:req", "clean.go"}, 0},
+		{"getter naming only", []string{"-getter-naming", "clean.go"}, 0},
+		{"no files", []string{"-check", "request:req"}, exitUsage},
+		{"parse error", []string{"-check", "request:req", "broken.go"}, exitFailure},
+		{"no violations", []string{"-check", "request:req", "-error-on-no-violations", "clean.go"}, exitUsage},
+		{"parse error without violations", []string{"-check", "request:req", "-error-on-no-violations", "broken.go"}, exitFailure},
+		{"trend summary", []string{"trend-summary", "trend.json"}, 0},
+		{"trend summary of -trend-file", []string{"trend-summary", "-trend-file", "trend.json"}, 0},
+		{"trend summary without a file", []string{"trend-summary"}, exitUsage},
+		{"missing trend file", []string{"trend-summary", "missing.json"}, 0},
+		{"missing file", []string{"-check", "request:req", "missing.go"}, exitFailure},
+		{"missing directory", []string{"-check", "request:req", "./nosuchdir"}, exitFailure},
+		{"missing directory tree", []string{"-check", "request:req", "./nosuchdir/..."}, exitFailure},
+		{"missing package", []string{"-check", "request:req", "example.com/nosuch/..."}, exitFailure},
+		// A failure is reported over the findings of the other files
+		{"parse error and findings", []string{"-check", "request:req", "-l", "broken.go", "findings.go"}, exitFailure},
+		// Findings fixed by -fix do not fail the run; fixable.go is changed last
+		{"fixed findings", []string{"-check", "request:req", "-fix", "fixable.go"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], tt.args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), runMainEnv+"=1", "GITHUB_ACTIONS=")
+			out, err := cmd.CombinedOutput()
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if code != tt.want {
+				t.Errorf("gonamefix %s exited %d, want %d; output:\n%s", strings.Join(tt.args, " "), code, tt.want, out)
+			}
+		})
+	}
+}
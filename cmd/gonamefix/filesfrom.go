@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// readFilesFrom reads the file list of -files-from from path, or from
+// standard input if path is "-".
+func readFilesFrom(path string, nul bool) ([]string, error) {
+	if path == "-" {
+		return readFileList(os.Stdin, nul)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readFileList(f, nul)
+}
+
+// readFileList reads paths separated by NUL, as written by git ls-files -z
+// and find -print0, or by newlines. NUL is used with nul or when the input
+// contains one. Empty entries are skipped; the paths are otherwise kept as
+// they are, spaces included.
+func readFileList(r io.Reader, nul bool) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	if nul || bytes.IndexByte(data, 0) >= 0 {
+		entries = strings.Split(string(data), "\x00")
+	} else {
+		entries = strings.Split(string(data), "\n")
+		for i, entry := range entries {
+			entries[i] = strings.TrimSuffix(entry, "\r")
+		}
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry != "" {
+			paths = append(paths, entry)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadFileList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		nul   bool
+		want  []string
+	}{
+		{"newlines", "a.go\npkg/b.go\n", false, []string{"a.go", "pkg/b.go"}},
+		{"CRLF and blank lines", "a.go\r\n\r\nb.go", false, []string{"a.go", "b.go"}},
+		{"spaces are kept", "my file.go\n", false, []string{"my file.go"}},
+		{"NUL detected", "a.go\x00dir/with\nnewline.go\x00", false, []string{"a.go", "dir/with\nnewline.go"}},
+		{"-z", "a.go\x00", true, []string{"a.go"}},
+		{"-z without a NUL", "a.go\nb.go", true, []string{"a.go\nb.go"}},
+		{"empty", "", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readFileList(strings.NewReader(tt.input), tt.nul)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("readFileList(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFilesFrom(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "files")
+	if err := os.WriteFile(list, []byte("main.go\x00pkg/pkg.go\x00"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readFilesFrom(list, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"main.go", "pkg/pkg.go"}; !slices.Equal(got, want) {
+		t.Errorf("readFilesFrom() = %q, want %q", got, want)
+	}
+
+	if _, err := readFilesFrom(filepath.Join(t.TempDir(), "missing"), false); err == nil {
+		t.Error("readFilesFrom() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// byteEdit is an analysis.TextEdit resolved to byte offsets in a file.
+type byteEdit struct {
+	start, end int
+	newText    []byte
+}
+
+// overlaps reports whether e and other touch the same bytes. Two insertions
+// at the same offset overlap as well, since their order would be arbitrary.
+func (e byteEdit) overlaps(other byteEdit) bool {
+	if e.start == other.start {
+		return true
+	}
+	return e.start < other.end && other.start < e.end
+}
+
+// applyFixes returns src, the content of the file fset positions refer to,
+// with the first suggested fix of every diagnostic applied, and the number
+// of fixes applied. A fix is left out when one of its edits overlaps an edit
+// of an earlier fix, so the result never mixes two renames of the same text.
+func applyFixes(fset *token.FileSet, src []byte, diagnostics []analysis.Diagnostic) ([]byte, int) {
+	var accepted []byteEdit
+	fixed := 0
+	for _, d := range diagnostics {
+		if len(d.SuggestedFixes) == 0 {
+			continue
+		}
+		edits, ok := resolveEdits(fset, len(src), d.SuggestedFixes[0].TextEdits)
+		if !ok || conflicts(edits, accepted) {
+			continue
+		}
+		accepted = append(accepted, edits...)
+		fixed++
+	}
+
+	return splice(src, accepted), fixed
+}
+
+// applyPackageFixes is applyFixes for the files of a package, whose fixes
+// may edit several files. srcs holds the content of every file fixes may
+// edit. A fix is applied to all of its files or left out. The result holds
+// the new content of the files that changed and the diagnostics whose fixes
+// were applied.
+func applyPackageFixes(fset *token.FileSet, srcs map[*token.File][]byte, diagnostics []analysis.Diagnostic) (map[*token.File][]byte, []analysis.Diagnostic) {
+	accepted := make(map[*token.File][]byteEdit)
+	var applied []analysis.Diagnostic
+	for _, d := range diagnostics {
+		if len(d.SuggestedFixes) == 0 {
+			continue
+		}
+		byFile := make(map[*token.File][]analysis.TextEdit)
+		for _, edit := range d.SuggestedFixes[0].TextEdits {
+			file := fset.File(edit.Pos)
+			byFile[file] = append(byFile[file], edit)
+		}
+
+		resolved := make(map[*token.File][]byteEdit, len(byFile))
+		ok := true
+		for file, edits := range byFile {
+			src, known := srcs[file]
+			if !known {
+				ok = false
+				break
+			}
+			fileEdits, resolvedOK := resolveEdits(fset, len(src), edits)
+			if !resolvedOK || conflicts(fileEdits, accepted[file]) {
+				ok = false
+				break
+			}
+			resolved[file] = fileEdits
+		}
+		if !ok {
+			continue
+		}
+		for file, edits := range resolved {
+			accepted[file] = append(accepted[file], edits...)
+		}
+		applied = append(applied, d)
+	}
+
+	changed := make(map[*token.File][]byte, len(accepted))
+	for file, edits := range accepted {
+		changed[file] = splice(srcs[file], edits)
+	}
+	return changed, applied
+}
+
+// splice returns src with edits, which may not overlap, applied.
+func splice(src []byte, edits []byteEdit) []byte {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var out bytes.Buffer
+	last := 0
+	for _, edit := range edits {
+		out.Write(src[last:edit.start])
+		out.Write(edit.newText)
+		last = edit.end
+	}
+	out.Write(src[last:])
+	return out.Bytes()
+}
+
+// resolveEdits converts edits to byte offsets. It fails for edits outside a
+// file of size bytes, and for edits overlapping each other.
+func resolveEdits(fset *token.FileSet, size int, edits []analysis.TextEdit) ([]byteEdit, bool) {
+	resolved := make([]byteEdit, 0, len(edits))
+	for _, edit := range edits {
+		file := fset.File(edit.Pos)
+		if file == nil || file.Size() != size {
+			return nil, false
+		}
+		end := edit.End
+		if !end.IsValid() {
+			end = edit.Pos
+		}
+		start, stop := file.Offset(edit.Pos), file.Offset(end)
+		if start > stop {
+			return nil, false
+		}
+		e := byteEdit{start: start, end: stop, newText: edit.NewText}
+		if conflicts([]byteEdit{e}, resolved) {
+			return nil, false
+		}
+		resolved = append(resolved, e)
+	}
+	return resolved, true
+}
+
+// conflicts reports whether any of edits overlaps any of others.
+func conflicts(edits, others []byteEdit) bool {
+	for _, e := range edits {
+		for _, other := range others {
+			if e.overlaps(other) {
+				return true
+			}
+		}
+	}
+	return false
+}
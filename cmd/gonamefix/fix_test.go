@@ -0,0 +1,49 @@
+package main
+
+import (
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestApplyFixes(t *testing.T) {
+	src := []byte("var request = request + 1\n")
+	fset := token.NewFileSet()
+	file := fset.AddFile("fix.go", -1, len(src))
+	pos := func(offset int) token.Pos { return file.Pos(offset) }
+	rename := func(newText string, offsets ...int) analysis.Diagnostic {
+		var edits []analysis.TextEdit
+		for _, offset := range offsets {
+			edits = append(edits, analysis.TextEdit{Pos: pos(offset), End: pos(offset + len("request")), NewText: []byte(newText)})
+		}
+		return analysis.Diagnostic{SuggestedFixes: []analysis.SuggestedFix{{TextEdits: edits}}}
+	}
+
+	diagnostics := []analysis.Diagnostic{
+		{Message: "no fix"},
+		rename("req", 4, 14),
+		// Overlaps the first rename, so it is left out
+		rename("rq", 14),
+	}
+
+	got, fixed := applyFixes(fset, src, diagnostics)
+	if string(got) != "var req = req + 1\n" || fixed != 1 {
+		t.Errorf("applyFixes() = %q, %d; want %q, 1", got, fixed, "var req = req + 1\n")
+	}
+}
+
+func TestResolveEditsRejectsOverlaps(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("fix.go", -1, 10)
+	edits := []analysis.TextEdit{
+		{Pos: file.Pos(0), End: file.Pos(4)},
+		{Pos: file.Pos(2), End: file.Pos(6)},
+	}
+	if _, ok := resolveEdits(fset, 10, edits); ok {
+		t.Error("resolveEdits() accepted overlapping edits")
+	}
+	if _, ok := resolveEdits(fset, 12, edits[:1]); ok {
+		t.Error("resolveEdits() accepted edits of a file of another size")
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// fixedFile is a file with fixes applied.
+type fixedFile struct {
+	filename string
+	// src is the content on disk and fixed the content with the fixes applied
+	src, fixed []byte
+}
+
+// fixResult is what fixing changed.
+type fixResult struct {
+	// fixed is the number of findings fixed
+	fixed int
+	// files are the changed files, sorted by name
+	files []fixedFile
+	// renames are the renames of the fixed findings, sorted by position
+	renames []plannedRename
+	// applied are the fixed findings, with the edits of their fix extended
+	// to the references they rename
+	applied []appliedFix
+}
+
+// appliedFix is a finding whose fix was applied. Its positions refer to fset,
+// a parse of the files as they were on disk.
+type appliedFix struct {
+	fset       *token.FileSet
+	diagnostic analysis.Diagnostic
+}
+
+// findingKey identifies a finding across the parses of its file, since the
+// fixes are computed on a new parse of every file.
+type findingKey struct {
+	filename string
+	offset   int
+	message  string
+}
+
+// findingKeyOf returns the key of d, reported in a file of fset.
+func findingKeyOf(fset *token.FileSet, d analysis.Diagnostic) findingKey {
+	pos := fset.Position(d.Pos)
+	return findingKey{filename: filepath.Clean(pos.Filename), offset: pos.Offset, message: d.Message}
+}
+
+// fixesByFinding indexes the fixes applied by fixed by their finding, for the
+// reports that suggest the fixes -fix would apply.
+func fixesByFinding(fixed fixResult) map[findingKey]appliedFix {
+	fixes := make(map[findingKey]appliedFix, len(fixed.applied))
+	for _, fix := range fixed.applied {
+		fixes[findingKeyOf(fix.fset, fix.diagnostic)] = fix
+	}
+	return fixes
+}
+
+// confirmFunc selects the fixes to apply among diagnostics, the safe fixes
+// of a package in the files of srcs, and returns them in order.
+type confirmFunc func(fset *token.FileSet, srcs map[*token.File][]byte, diagnostics []analysis.Diagnostic) []analysis.Diagnostic
+
+// fixInPlace applies the fixes of the findings in results to the files on
+// disk, as computed by computeFixes. With confirm, only the fixes it selects
+// are applied. With backup, the changed files are backed up first, and
+// nothing is changed when that fails.
+func fixInPlace(results []fileResult, confirm confirmFunc, backup *backupPolicy) (fixResult, error) {
+	fixed, err := confirmedFixes(results, confirm)
+	if err != nil {
+		// Nothing is written
+		return fixResult{}, err
+	}
+	if backup != nil {
+		if err := backup.write(fixed.files); err != nil {
+			fixed.files = nil
+			return fixed, err
+		}
+	}
+	for i, file := range fixed.files {
+		if err := writeFixed(file.filename, file.fixed); err != nil {
+			// Only the files written before are reported, and backed up
+			if backup != nil {
+				backup.remove(fixed.files[i:])
+			}
+			fixed.files = fixed.files[:i]
+			return fixed, err
+		}
+	}
+	return fixed, nil
+}
+
+// computeFixes applies the fixes of the findings in results to the content
+// of their files, without writing them. Files are fixed a package at a time, so that renames of unexported
+// package-level names reach their references in every file of the package,
+// including files that were excluded from the analysis. Only the fixes that
+// safeFixes accepts are applied; fixes overlapping an earlier one are left
+// out. The fixed files are formatted like gofmt does unless -no-format is
+// given, and formatting errors fail the whole computation.
+func computeFixes(results []fileResult) (fixResult, error) {
+	return confirmedFixes(results, nil)
+}
+
+// confirmedFixes is computeFixes applying only the fixes confirm selects,
+// all if it is nil.
+func confirmedFixes(results []fileResult, confirm confirmFunc) (fixResult, error) {
+	byDir := make(map[string][]fileResult)
+	for _, result := range results {
+		if len(result.diagnostics) > 0 && !isMarkdown(result.filename) {
+			dir := filepath.Dir(result.filename)
+			byDir[dir] = append(byDir[dir], result)
+		}
+	}
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var fixed fixResult
+	for _, dir := range dirs {
+		dirFixed, err := fixDir(dir, byDir[dir], confirm)
+		fixed.fixed += dirFixed.fixed
+		fixed.files = append(fixed.files, dirFixed.files...)
+		fixed.renames = append(fixed.renames, dirFixed.renames...)
+		fixed.applied = append(fixed.applied, dirFixed.applied...)
+		if err != nil {
+			return fixed, err
+		}
+	}
+	sort.Slice(fixed.files, func(i, j int) bool { return fixed.files[i].filename < fixed.files[j].filename })
+	fixed.renames = sortRenames(fixed.renames)
+	return fixed, nil
+}
+
+// fixDir fixes the findings in results, which are files of dir, together
+// with the other Go files of their packages in dir, as confirmed by confirm.
+func fixDir(dir string, results []fileResult, confirm confirmFunc) (fixResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fixResult{}, err
+	}
+
+	// A directory may hold a package and its external test package
+	fset := token.NewFileSet()
+	srcs := make(map[*token.File][]byte)
+	parsed := make(map[string]*ast.File)
+	packages := make(map[string][]*ast.File)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return fixResult{}, err
+		}
+		// Files that do not parse were reported by the analysis
+		file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		srcs[fset.File(file.Pos())] = src
+		parsed[filename] = file
+		packages[file.Name.Name] = append(packages[file.Name.Name], file)
+	}
+
+	diagnostics := make(map[string][]analysis.Diagnostic)
+	for _, result := range results {
+		file := parsed[filepath.Join(dir, filepath.Base(result.filename))]
+		if file == nil {
+			continue
+		}
+		tokFile := fset.File(file.Pos())
+		for _, d := range result.diagnostics {
+			if rebased, ok := rebaseDiagnostic(d, result.fset, tokFile); ok {
+				diagnostics[file.Name.Name] = append(diagnostics[file.Name.Name], rebased)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(diagnostics))
+	for name := range diagnostics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fixed fixResult
+	for _, name := range names {
+		index := newRenameIndex(packages[name], true)
+		safe := safeFixes(index, diagnostics[name])
+		if confirm != nil {
+			safe = confirm(fset, srcs, safe)
+		}
+		changed, applied := applyPackageFixes(fset, srcs, safe)
+		fixed.fixed += len(applied)
+		fixed.renames = append(fixed.renames, plannedRenames(fset, srcs, applied)...)
+		for _, d := range applied {
+			fixed.applied = append(fixed.applied, appliedFix{fset: fset, diagnostic: d})
+		}
+
+		for tokFile, src := range changed {
+			formatted, err := formatFixed(tokFile.Name(), src)
+			if err != nil {
+				return fixed, err
+			}
+			fixed.files = append(fixed.files, fixedFile{filename: tokFile.Name(), src: srcs[tokFile], fixed: formatted})
+		}
+	}
+	return fixed, nil
+}
+
+// formatFixed formats src, the content of filename with fixes applied, unless
+// -no-format is given. Renames change the width of names, which breaks the
+// alignment of declaration blocks and composite literals. A formatting error
+// means the fixes produced invalid Go, so src must not be written.
+func formatFixed(filename string, src []byte) ([]byte, error) {
+	if *noFormatFlag {
+		return src, nil
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("formatting %s after fixing: %w; no fixes were applied", filename, err)
+	}
+	return formatted, nil
+}
+
+// rebaseDiagnostic moves d, reported in a file of from, to the same offsets
+// in to, a new parse of the file. It fails when the file changed size since.
+func rebaseDiagnostic(d analysis.Diagnostic, from *token.FileSet, to *token.File) (analysis.Diagnostic, bool) {
+	fromFile := from.File(d.Pos)
+	if fromFile == nil || fromFile.Size() != to.Size() {
+		return d, false
+	}
+	move := func(pos token.Pos) token.Pos {
+		if !pos.IsValid() || from.File(pos) != fromFile {
+			return token.NoPos
+		}
+		return to.Pos(fromFile.Offset(pos))
+	}
+
+	rebased := d
+	rebased.Pos, rebased.End = move(d.Pos), move(d.End)
+	rebased.Related = nil
+	rebased.SuggestedFixes = nil
+	for _, fix := range d.SuggestedFixes {
+		moved := analysis.SuggestedFix{Message: fix.Message}
+		for _, edit := range fix.TextEdits {
+			pos := move(edit.Pos)
+			if !pos.IsValid() {
+				return d, false
+			}
+			moved.TextEdits = append(moved.TextEdits, analysis.TextEdit{Pos: pos, End: move(edit.End), NewText: edit.NewText})
+		}
+		rebased.SuggestedFixes = append(rebased.SuggestedFixes, moved)
+	}
+	return rebased, true
+}
+
+// writeFixed replaces the content of filename, keeping its permissions.
+func writeFixed(filename string, src []byte) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, src, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing fixes: %w", err)
+	}
+	return nil
+}
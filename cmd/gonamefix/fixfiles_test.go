@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestFixInPlace(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": "package p\n\nvar request string\n\ntype RequestHandler struct{ request string }\n\nfunc handle(response string) string {\n\treturn response + request\n}\n",
+		"b.go": "package p\n\nfunc use() string {\n\treturn request\n}\n",
+		// Excluded from the analysis, but its references are renamed as well
+		"a_test.go": "package p\n\nvar _ = request\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := gonamefix.DefaultConfig()
+	config.Check = [][]string{{"request", "req"}, {"response", "res"}}
+	analyzer := gonamefix.NewAnalyzer(config)
+	var results []fileResult
+	for _, name := range []string{"a.go", "b.go"} {
+		result, err := analyzeFile(analyzer, config, filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+
+	fixed, err := fixInPlace(results, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The exported type and the field need a manual fix
+	if fixed.fixed != 2 || len(fixed.files) != 3 {
+		t.Errorf("fixInPlace() fixed %d findings in %v, want 2 in 3 files", fixed.fixed, fixed.files)
+	}
+
+	want := map[string]string{
+		"a.go":      "package p\n\nvar req string\n\ntype RequestHandler struct{ request string }\n\nfunc handle(res string) string {\n\treturn res + req\n}\n",
+		"b.go":      "package p\n\nfunc use() string {\n\treturn req\n}\n",
+		"a_test.go": "package p\n\nvar _ = req\n",
+	}
+	for name, src := range want {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != src {
+			t.Errorf("%s = %q, want %q", name, got, src)
+		}
+	}
+}
+
+func TestFixInPlaceNameInUse(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nvar request string\n\nfunc use() string {\n\treq := \"x\"\n\treturn req + request\n}\n"
+	filename := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := fixInPlace([]fileResult{result}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixed.fixed != 0 || len(fixed.files) != 0 {
+		t.Errorf("fixInPlace() renamed request to a name in use: %+v", fixed)
+	}
+}
+
+func TestFixInPlaceFormats(t *testing.T) {
+	// Renaming request and response changes the alignment gofmt wants
+	src := "package p\n\nvar (\n\trequest  = 1\n\tresponse = 2\n\tcount    = 3\n)\n\ntype pair struct {\n\tleft, right int\n}\n\nvar total = pair{\n\tleft:  request,\n\tright: response,\n}\n"
+	formatted := "package p\n\nvar (\n\treq   = 1\n\tres   = 2\n\tcount = 3\n)\n\ntype pair struct {\n\tleft, right int\n}\n\nvar total = pair{\n\tleft:  req,\n\tright: res,\n}\n"
+	unformatted := "package p\n\nvar (\n\treq  = 1\n\tres = 2\n\tcount    = 3\n)\n\ntype pair struct {\n\tleft, right int\n}\n\nvar total = pair{\n\tleft:  req,\n\tright: res,\n}\n"
+
+	for _, noFormat := range []bool{false, true} {
+		t.Run(fmt.Sprintf("no-format=%t", noFormat), func(t *testing.T) {
+			saved := *noFormatFlag
+			*noFormatFlag = noFormat
+			t.Cleanup(func() { *noFormatFlag = saved })
+
+			filename := filepath.Join(t.TempDir(), "a.go")
+			if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}}}
+			result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := fixInPlace([]fileResult{result}, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			want := formatted
+			if noFormat {
+				want = unformatted
+			}
+			if got, _ := os.ReadFile(filename); string(got) != want {
+				t.Errorf("fixed a.go = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFormatFixedInvalid(t *testing.T) {
+	if _, err := formatFixed("a.go", []byte("package p\n\nvar = 1\n")); err == nil || !strings.Contains(err.Error(), "no fixes were applied") {
+		t.Errorf("formatFixed() error = %v, want a formatting error", err)
+	}
+}
+
+func TestFixInPlaceCompositeLiteralKeys(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "fix", "keys.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "keys.go")
+	if err := os.WriteFile(filename, src, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := gonamefix.DefaultConfig()
+	config.Check = [][]string{{"request", "req"}, {"response", "res"}}
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fixInPlace([]fileResult{result}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "fix", "keys.go.golden")
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(filename); err != nil {
+		t.Fatal(err)
+	} else if string(got) != string(want) {
+		t.Errorf("fixed file differs from %s:\n%s", golden, got)
+	}
+}
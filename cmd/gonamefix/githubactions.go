@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// inGitHubActions reports whether gonamefix runs in a GitHub Actions
+// workflow, where -format defaults to github-actions.
+func inGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// writeGitHubActions writes violations to out as GitHub Actions workflow
+// commands, which the runner shows as annotations on the flagged lines of a
+// pull request.
+func writeGitHubActions(out io.Writer, violations []violation) error {
+	for _, v := range violations {
+		d := v.diagnostic
+		pos := v.fset.Position(d.Pos)
+		properties := fmt.Sprintf("file=%s,line=%d,col=%d",
+			escapeGitHubProperty(filepath.ToSlash(reportPath(pos.Filename))), pos.Line, pos.Column)
+		// Annotations spanning lines cannot have columns
+		if end := v.fset.Position(d.End); d.End.IsValid() && end.Line == pos.Line {
+			properties += fmt.Sprintf(",endColumn=%d", end.Column)
+		}
+		if _, err := fmt.Fprintf(out, "::%s %s::%s\n", githubLevel(v.severity), properties, escapeGitHubData(d.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubLevel returns the workflow command annotating a finding of severity.
+func githubLevel(severity string) string {
+	if severity == severityInfo {
+		return "notice"
+	}
+	return severity
+}
+
+// escapeGitHubData escapes the message of a workflow command, which ends at
+// the end of the line.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command, which
+// also ends at a comma or a colon.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
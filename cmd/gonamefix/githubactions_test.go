@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestWriteGitHubActions(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("pkg/x.go", -1, 100)
+	file.SetLines([]int{0, 20, 40})
+	violations := []violation{
+		{fset: fset, diagnostic: analysis.Diagnostic{Pos: file.Pos(44), End: file.Pos(51), Message: "suggest replacing 'request' with 'req'"}, severity: severityWarning},
+		{fset: fset, diagnostic: analysis.Diagnostic{Pos: file.Pos(2), Message: "100% of\r\nnames"}, severity: severityInfo},
+		{fset: fset, diagnostic: analysis.Diagnostic{Pos: file.Pos(10), End: file.Pos(30), Message: "spans lines"}, severity: severityError},
+	}
+
+	var out bytes.Buffer
+	if err := writeGitHubActions(&out, violations); err != nil {
+		t.Fatal(err)
+	}
+	want := "::warning file=pkg/x.go,line=3,col=5,endColumn=12::suggest replacing 'request' with 'req'\n" +
+		"::notice file=pkg/x.go,line=1,col=3::100%25 of%0D%0Anames\n" +
+		"::error file=pkg/x.go,line=1,col=11::spans lines\n"
+	if out.String() != want {
+		t.Errorf("writeGitHubActions() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestEscapeGitHubProperty(t *testing.T) {
+	if got, want := escapeGitHubProperty("a,b:c%d\n"), "a%2Cb%3Ac%25d%0A"; got != want {
+		t.Errorf("escapeGitHubProperty() = %q, want %q", got, want)
+	}
+}
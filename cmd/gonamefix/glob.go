@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// isGlobPattern reports whether arg is a glob pattern, such as
+// internal/**/handler*.go. Paths that exist are never patterns, even if they
+// contain glob characters.
+func isGlobPattern(arg string) bool {
+	if !strings.ContainsAny(arg, "*?[") {
+		return false
+	}
+	_, err := os.Stat(arg)
+	return err != nil
+}
+
+// expandGlob returns the files and directories matching pattern, sorted. The
+// segments of pattern are matched like path.Match, and a ** segment matches
+// any number of directories. Relative patterns are matched relative to the
+// working directory. Vendor directories, unless included, and directories
+// whose name starts with a dot are only searched when they are among the
+// leading segments of pattern without wildcards. Only the files that would
+// be analyzed in a directory are returned.
+func expandGlob(pattern string) ([]string, error) {
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	segments := strings.Split(pattern, "/")
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// The walk starts at the directory of the segments without wildcards
+	static := 0
+	for static < len(segments) && !strings.ContainsAny(segments[static], "*?[") {
+		static++
+	}
+	root := strings.Join(segments[:static], "/")
+	switch {
+	case root == "" && strings.HasPrefix(pattern, "/"):
+		root = "/"
+	case root == "":
+		root = "."
+	}
+
+	var matches []string
+	err := filepath.WalkDir(filepath.FromSlash(root), func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// A prefix that does not exist matches nothing
+			if name == filepath.FromSlash(root) && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() && name != filepath.FromSlash(root) &&
+			(strings.HasPrefix(entry.Name(), ".") || gonamefix.SkipVendor(name, *includeVendorFlag)) {
+			return filepath.SkipDir
+		}
+		if name != "." && (entry.IsDir() || isSourceFile(name)) && matchGlob(segments, strings.Split(filepath.ToSlash(name), "/")) {
+			matches = append(matches, name)
+		}
+		return nil
+	})
+	sort.Strings(matches)
+	return matches, err
+}
+
+// matchGlob reports whether the segments of a name match the segments of a
+// pattern, where a ** segment matches any number of segments.
+func matchGlob(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"internal/**/handler*.go", "internal/handler.go", true},
+		{"internal/**/handler*.go", "internal/api/v1/handler_user.go", true},
+		{"internal/**/handler*.go", "internal/api/server.go", false},
+		{"internal/**/handler*.go", "cmd/handler.go", false},
+		{"**/*.go", "a.go", true},
+		{"**", "a/b", true},
+		{"*/a.go", "x/y/a.go", false},
+		{"internal/*", "internal/api", true},
+	}
+	for _, tt := range tests {
+		got := matchGlob(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/"))
+		if got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollectFilesGlobs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"main.go",
+		"internal/handler.go",
+		"internal/api/handler_user.go",
+		"internal/api/server.go",
+		"internal/vendor/x/handler.go",
+		"internal/.cache/handler.go",
+		"pkg/util/util.go",
+		"lit[1].go",
+	} {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte("package p\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "literal and glob",
+			args: []string{"main.go", "internal/**/handler*.go"},
+			want: []string{"main.go", "internal/api/handler_user.go", "internal/handler.go"},
+		},
+		{
+			// A matched directory is scanned like a directory argument
+			name: "directories",
+			args: []string{"pkg/*"},
+			want: []string{"pkg/util/util.go"},
+		},
+		{
+			// Directories are scanned recursively, dot directories included
+			name: "duplicates",
+			args: []string{"internal/handler.go", "internal/*.go", "internal"},
+			want: []string{"internal/handler.go", "internal/.cache/handler.go", "internal/api/handler_user.go", "internal/api/server.go"},
+		},
+		{
+			// Existing paths are never patterns
+			name: "literal with glob characters",
+			args: []string{"lit[1].go"},
+			want: []string{"lit[1].go"},
+		},
+		{
+			name: "no match",
+			args: []string{"missing/**/*.go"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			files, err := collectFiles(tt.args, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, filename := range files {
+				got = append(got, filepath.ToSlash(filename))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("collectFiles(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
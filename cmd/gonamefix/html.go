@@ -0,0 +1,125 @@
+package main
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// htmlTemplate renders -format html: a single page with its styles and
+// scripts inline, which works without a network connection.
+//
+//go:embed html.tmpl
+var htmlTemplate string
+
+var htmlPage = template.Must(template.New("report").Parse(htmlTemplate))
+
+// htmlReport is the data of the page written by -format html.
+type htmlReport struct {
+	Generated string
+	Total     int
+	// Mappings counts the findings of every mapping, written as in -check,
+	// and of every other rule, most first
+	Mappings []topEntry
+	Files    []htmlFile
+}
+
+// htmlFile is the section of a file with findings.
+type htmlFile struct {
+	Name     string
+	Findings []htmlFinding
+}
+
+// htmlFinding is a finding with an excerpt of its line, split around the
+// flagged span so that the page can highlight it.
+type htmlFinding struct {
+	Line, Column int
+	Severity     string
+	Mapping      string
+	Message      string
+	Suggestion   string
+	// Before, Flagged and After are the line of the finding; Flagged is
+	// empty for findings that span lines
+	Before, Flagged, After string
+}
+
+// newHTMLReport describes the findings of results, quoting the lines they
+// are on from the analyzed source, or from the file on disk for results
+// without one.
+func newHTMLReport(results []fileResult, config gonamefix.Config, generated time.Time) htmlReport {
+	mappings := ruleNames(config)
+	counts := make(map[string]int)
+	report := htmlReport{Generated: generated.Format(time.RFC3339)}
+
+	for _, result := range results {
+		if len(result.diagnostics) == 0 {
+			continue
+		}
+		src := result.src
+		if src == nil {
+			src, _ = os.ReadFile(result.filename)
+		}
+		file := htmlFile{Name: displayPath(result.filename)}
+		for _, d := range result.diagnostics {
+			mapping, ok := mappings[d.Category]
+			if !ok {
+				mapping = d.Category
+			}
+			if mapping == "" {
+				mapping = "gonamefix"
+			}
+			counts[mapping]++
+
+			v := violation{fset: result.fset, diagnostic: d, severity: severityOf(d)}
+			pos := result.fset.Position(d.Pos)
+			finding := htmlFinding{
+				Line:       pos.Line,
+				Column:     pos.Column,
+				Severity:   v.severity,
+				Mapping:    mapping,
+				Message:    d.Message,
+				Suggestion: suggestion(v),
+			}
+			end := pos
+			if d.End.IsValid() {
+				end = result.fset.Position(d.End)
+			}
+			finding.Before, finding.Flagged, finding.After = excerpt(src, pos.Offset, end.Offset)
+			file.Findings = append(file.Findings, finding)
+			report.Total++
+		}
+		report.Files = append(report.Files, file)
+	}
+	report.Mappings = rank(counts, report.Total, 0)
+	return report
+}
+
+// excerpt returns the line of src containing offset, split into the text
+// before offset, from offset to end and after end. The whole line is before
+// when end is on another line or either offset is out of range.
+func excerpt(src []byte, offset, end int) (before, flagged, after string) {
+	if offset < 0 || offset > len(src) {
+		return "", "", ""
+	}
+	start := strings.LastIndexByte(string(src[:offset]), '\n') + 1
+	stop := len(src)
+	if i := strings.IndexByte(string(src[offset:]), '\n'); i >= 0 {
+		stop = offset + i
+	}
+	line := strings.TrimSuffix(string(src[start:stop]), "\r")
+	if end <= offset || end > start+len(line) {
+		return line, "", ""
+	}
+	return string(src[start:offset]), string(src[offset:end]), line[end-start:]
+}
+
+// writeHTML writes the findings of results to out as a self-contained HTML
+// page generated at the given time.
+func writeHTML(out io.Writer, results []fileResult, config gonamefix.Config, generated time.Time) error {
+	return htmlPage.Execute(out, newHTMLReport(results, config, generated))
+}
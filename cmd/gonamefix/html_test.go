@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestWriteHTML(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}}}
+	var results []fileResult
+	for _, name := range []string{"nested/main.go", "nested/pkg/pkg.go", "nested/tools/tools.go", "rdjson/rename.go"} {
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filepath.Join("testdata", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	// Files without findings get no section
+	results = append(results, fileResult{filename: "clean.go", fset: token.NewFileSet()})
+
+	// The time of generation is fixed so that the page can be compared
+	var got bytes.Buffer
+	if err := writeHTML(&got, results, config, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "html", "report.html")
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("HTML report differs from %s:\n%s", golden, got.String())
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	src := []byte("package p\n\nvar request, response string\r\nvar x = `a\nb`\n")
+	tests := []struct {
+		offset, end            int
+		before, flagged, after string
+	}{
+		{15, 22, "var ", "request", ", response string"},
+		// Spans over lines are not highlighted
+		{45, 53, "var x = `a", "", ""},
+		{100, 101, "", "", ""},
+	}
+	for _, tt := range tests {
+		before, flagged, after := excerpt(src, tt.offset, tt.end)
+		if before != tt.before || flagged != tt.flagged || after != tt.after {
+			t.Errorf("excerpt(%d, %d) = %q, %q, %q, want %q, %q, %q", tt.offset, tt.end, before, flagged, after, tt.before, tt.flagged, tt.after)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// interactiveContext is the number of lines shown around a proposed rename.
+const interactiveContext = 2
+
+// ignoredRename is a rename declined with -interactive, as recorded in the
+// -interactive-ignore file: a line with the slash-separated path relative to
+// the working directory, the enclosing top-level declaration as in baseline
+// files, the old and the new name, separated by tabs.
+type ignoredRename struct {
+	file, declaration, old, new string
+}
+
+// confirmer asks whether to apply each safe fix, for -fix -interactive.
+type confirmer struct {
+	in  *bufio.Reader
+	out io.Writer
+	// names are the names of the rules by ID, as for -dry-run
+	names map[string]string
+	// ignored are the renames declined in earlier sessions, which are not
+	// asked again, and declined those declined in this one
+	ignored  map[ignoredRename]bool
+	declined []ignoredRename
+	// allFiles are the files whose renames are all accepted
+	allFiles map[string]bool
+	quit     bool
+	// decls are the top-level declarations of the files, read once
+	decls map[string][]declSpan
+}
+
+func newConfirmer(in io.Reader, out io.Writer, names map[string]string, ignored []ignoredRename) *confirmer {
+	c := &confirmer{
+		in:       bufio.NewReader(in),
+		out:      out,
+		names:    names,
+		ignored:  make(map[ignoredRename]bool),
+		allFiles: make(map[string]bool),
+		decls:    make(map[string][]declSpan),
+	}
+	for _, r := range ignored {
+		c.ignored[r] = true
+	}
+	return c
+}
+
+// confirm is a confirmFunc asking for every fix of diagnostics whose rename
+// is not ignored. Fixes overlapping an accepted one, such as a second rename
+// of the same name, are skipped without asking, since they cannot be applied
+// together. After q nothing more is accepted.
+func (c *confirmer) confirm(fset *token.FileSet, srcs map[*token.File][]byte, diagnostics []analysis.Diagnostic) []analysis.Diagnostic {
+	var accepted []analysis.Diagnostic
+	for _, d := range diagnostics {
+		if c.quit {
+			break
+		}
+		renames := plannedRenames(fset, srcs, []analysis.Diagnostic{d})
+		if len(renames) == 0 {
+			continue
+		}
+		r := renames[0]
+		decls, ok := c.decls[r.filename]
+		if !ok {
+			decls = topLevelDecls(r.filename)
+			c.decls[r.filename] = decls
+		}
+		key := ignoredRename{
+			file:        filepath.ToSlash(relativePath(r.filename)),
+			declaration: enclosingDecl(decls, fset.Position(d.Pos).Offset),
+			old:         r.old,
+			new:         r.new,
+		}
+		if c.ignored[key] {
+			continue
+		}
+		if _, applied := applyPackageFixes(fset, srcs, append(accepted[:len(accepted):len(accepted)], d)); len(applied) == len(accepted) {
+			continue
+		}
+		if c.allFiles[r.filename] {
+			accepted = append(accepted, d)
+			continue
+		}
+
+		c.show(r, srcs[fset.File(d.Pos)])
+		switch c.ask() {
+		case 'y':
+			accepted = append(accepted, d)
+		case 'a':
+			c.allFiles[r.filename] = true
+			accepted = append(accepted, d)
+		case 'n':
+			c.ignored[key] = true
+			c.declined = append(c.declined, key)
+		case 'q':
+			c.quit = true
+		}
+	}
+	return accepted
+}
+
+// show prints rename r with the lines of src around it.
+func (c *confirmer) show(r plannedRename, src []byte) {
+	rule := r.rule
+	if name, ok := c.names[rule]; ok {
+		rule = name
+	}
+	fmt.Fprintf(c.out, "\n%s:%d:%d: rename %s -> %s (rule %s)\n", reportPath(r.filename), r.line, r.column, r.old, r.new, rule)
+	lines := strings.Split(string(src), "\n")
+	width := len(fmt.Sprint(min(r.line+interactiveContext, len(lines))))
+	for n := max(r.line-interactiveContext, 1); n <= min(r.line+interactiveContext, len(lines)); n++ {
+		marker := " "
+		if n == r.line {
+			marker = ">"
+		}
+		fmt.Fprintf(c.out, "%s %*d | %s\n", marker, width, n, lines[n-1])
+	}
+}
+
+// answers are the accepted answers to the prompt.
+var answers = map[string]byte{"y": 'y', "yes": 'y', "n": 'n', "no": 'n', "a": 'a', "all": 'a', "q": 'q', "quit": 'q'}
+
+// ask prompts until it reads one of the answers. The end of the input
+// answers q.
+func (c *confirmer) ask() byte {
+	for {
+		fmt.Fprint(c.out, "Apply this rename? [y]es, [n]o, [a]ll in file, [q]uit: ")
+		line, err := c.in.ReadString('\n')
+		if answer, ok := answers[strings.ToLower(strings.TrimSpace(line))]; ok {
+			return answer
+		}
+		if err != nil {
+			fmt.Fprintln(c.out)
+			return 'q'
+		}
+	}
+}
+
+// readIgnoredRenames reads an -interactive-ignore file. A missing file holds
+// no renames.
+func readIgnoredRenames(path string) ([]ignoredRename, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var renames []ignoredRename
+	for n, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("%s:%d: expected file, declaration, old and new name separated by tabs", path, n+1)
+		}
+		renames = append(renames, ignoredRename{file: fields[0], declaration: fields[1], old: fields[2], new: fields[3]})
+	}
+	return renames, nil
+}
+
+// appendIgnoredRenames adds renames to the -interactive-ignore file at path.
+func appendIgnoredRenames(path string, renames []ignoredRename) error {
+	if len(renames) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	for _, r := range renames {
+		fmt.Fprintf(f, "%s\t%s\t%s\t%s\n", r.file, r.declaration, r.old, r.new)
+	}
+	return f.Close()
+}
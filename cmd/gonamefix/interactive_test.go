@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestInteractiveFix(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	files := map[string]string{
+		a: "package p\n\nfunc handle(request, response string) string {\n\treturn request + response\n}\n\nfunc serve(request string) {}\n",
+		b: "package p\n\nfunc other(request, response string) {}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(name, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}}}
+	var results []fileResult
+	for _, name := range []string{a, b} {
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+
+	// request in handle was declined before; response is accepted after an
+	// invalid answer with the rest of a.go, the first rename of b.go is
+	// accepted, then q stops
+	ignored := []ignoredRename{{file: filepath.ToSlash(relativePath(a)), declaration: "func handle", old: "request", new: "req"}}
+	var out strings.Builder
+	c := newConfirmer(strings.NewReader("maybe\na\ny\nq\n"), &out, ruleNames(config), ignored)
+	fixed, err := fixInPlace(results, c.confirm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixed.fixed != 3 {
+		t.Errorf("fixInPlace() fixed %d renames, want 3", fixed.fixed)
+	}
+
+	want := map[string]string{
+		a: "package p\n\nfunc handle(request, res string) string {\n\treturn request + res\n}\n\nfunc serve(req string) {}\n",
+		b: "package p\n\nfunc other(req, response string) {}\n",
+	}
+	for name, src := range want {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != src {
+			t.Errorf("%s =\n%s\nwant\n%s", filepath.Base(name), got, src)
+		}
+	}
+
+	prompt := out.String()
+	for _, wantText := range []string{
+		":3:21: rename response -> res (rule response:res)",
+		"> 3 | func handle(request, response string) string {",
+		"  5 | }",
+	} {
+		if !strings.Contains(prompt, wantText) {
+			t.Errorf("prompt does not contain %q:\n%s", wantText, prompt)
+		}
+	}
+	if n := strings.Count(prompt, "Apply this rename?"); n != 4 {
+		t.Errorf("asked %d times, want 4:\n%s", n, prompt)
+	}
+}
+
+func TestIgnoredRenames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ignore")
+	if renames, err := readIgnoredRenames(path); err != nil || renames != nil {
+		t.Fatalf("readIgnoredRenames() of a missing file = %v, %v", renames, err)
+	}
+	want := []ignoredRename{{"a.go", "type User", "user", "usr"}, {"pkg/b.go", "", "request", "req"}}
+	for _, r := range want {
+		if err := appendIgnoredRenames(path, []ignoredRename{r}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := readIgnoredRenames(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readIgnoredRenames() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// dumpIdentifiers writes the identifiers declared in files to out as JSON
+// lines, one object per identifier. Files that fail to parse are reported in
+// the returned error after the others have been written.
+func dumpIdentifiers(out io.Writer, files []string, config gonamefix.Config) error {
+	enc := json.NewEncoder(out)
+	var errs []error
+	for _, filename := range files {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parse error: %w", err))
+			continue
+		}
+
+		identifiers, err := gonamefix.Inventory(fset, []*ast.File{file}, config)
+		if err != nil {
+			return err
+		}
+		for _, identifier := range identifiers {
+			if err := enc.Encode(identifier); err != nil {
+				return err
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestDumpIdentifiers(t *testing.T) {
+	files := []string{filepath.Join("testdata", "nested", "main.go"), filepath.Join("testdata", "broken.go")}
+
+	var out bytes.Buffer
+	if err := dumpIdentifiers(&out, files, gonamefix.DefaultConfig()); err == nil {
+		t.Error("dumpIdentifiers() reported no parse error for broken.go")
+	}
+
+	var got []gonamefix.Identifier
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var id gonamefix.Identifier
+		if err := dec.Decode(&id); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, id)
+	}
+	if len(got) != 2 || got[0].Name != "request" || got[0].Kind != gonamefix.KindVar || got[1].Name != "main" || got[1].Kind != gonamefix.KindFunc {
+		t.Errorf("dumpIdentifiers() = %+v, want request and main", got)
+	}
+	if got[0].File != files[0] || got[0].Line != 3 {
+		t.Errorf("request at %s:%d, want %s:3", got[0].File, got[0].Line, files[0])
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// analyzeFiles analyzes files with up to jobs workers, each file with its
+// own file set and pass, and returns the results and errors in the order of
+// files, whatever order they finish in. The files of a workspace module in
+// modules are analyzed with its configuration, the others with analyzer and
+// config. The build constraint of each file is looked up in constraints. Every file analyzed is reported to progress.
+// Once limit findings are found, no further files are started, and the
+// results stop before the first file not analyzed. A limit of 0 analyzes
+// every file.
+func analyzeFiles(analyzer *analysis.Analyzer, config gonamefix.Config, modules moduleConfigs, files []string, constraints map[string]string,
+	jobs, limit int, progress *progress,
+) ([]fileResult, []error) {
+	results := make([]fileResult, len(files))
+	errs := make([]error, len(files))
+	skipped := make([]bool, len(files))
+	// The limit is checked against the findings of the first files, those
+	// before the first file not finished yet, so that the files analyzed
+	// always hold the first limit findings
+	var mu sync.Mutex
+	done := make([]bool, len(files))
+	finished, found := 0, 0
+	reached := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return limit > 0 && found >= limit
+	}
+	finish := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		done[i] = true
+		for finished < len(files) && done[finished] {
+			found += len(results[finished].diagnostics)
+			finished++
+		}
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range max(min(jobs, len(files)), 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every worker writes to its own slots only
+			for i := range indexes {
+				// Files are received in order and found only grows, so the
+				// files analyzed up to the first skipped one are the first ones
+				if reached() {
+					skipped[i] = true
+					continue
+				}
+				fileAnalyzer, fileConfig := modules.forFile(files[i], analyzer, config)
+				results[i], errs[i] = analyzeFile(fileAnalyzer, fileConfig, files[i])
+				results[i].constraint = constraints[files[i]]
+				finish(i)
+				progress.fileDone(files[i])
+			}
+		}()
+	}
+	sent := 0
+	for ; sent < len(files) && !reached(); sent++ {
+		indexes <- sent
+	}
+	close(indexes)
+	wg.Wait()
+
+	analyzed := sent
+	for i := range sent {
+		if skipped[i] {
+			analyzed = i
+			break
+		}
+	}
+	return results[:analyzed], errs[:analyzed]
+}
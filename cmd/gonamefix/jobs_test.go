@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestAnalyzeFilesParallel(t *testing.T) {
+	config := gonamefix.DefaultConfig()
+	config.Check = [][]string{{"request", "req"}, {"response", "res"}}
+	files, err := findGoFiles("testdata", true, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := gonamefix.NewAnalyzer(config)
+
+	// describe flattens results, whose file sets differ between runs
+	describe := func(results []fileResult, errs []error) []string {
+		var lines []string
+		for i, result := range results {
+			lines = append(lines, fmt.Sprintf("%s: %v", result.filename, errs[i] != nil))
+			for _, d := range result.diagnostics {
+				lines = append(lines, fmt.Sprintf("%s: %s", result.fset.Position(d.Pos), d.Message))
+			}
+		}
+		return lines
+	}
+
+	want := describe(analyzeFiles(analyzer, config, nil, files, nil, 1, 0, nil))
+	for _, jobs := range []int{4, len(files) + 1} {
+		if got := describe(analyzeFiles(analyzer, config, nil, files, nil, jobs, 0, nil)); !reflect.DeepEqual(got, want) {
+			t.Errorf("analyzeFiles() with %d jobs = %q, want %q", jobs, got, want)
+		}
+	}
+}
+
+func TestAnalyzeFilesLimit(t *testing.T) {
+	config := gonamefix.DefaultConfig()
+	config.Check = [][]string{{"request", "req"}}
+	files, err := findGoFiles("testdata", true, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := gonamefix.NewAnalyzer(config)
+
+	// With one job, files are analyzed one after the other, so analysis
+	// stops right after the first file with findings
+	results, errs := analyzeFiles(analyzer, config, nil, files, nil, 1, 1, nil)
+	if len(results) != len(errs) || len(results) == 0 || len(results) == len(files) {
+		t.Fatalf("analyzeFiles() with a limit analyzed %d of %d files", len(results), len(files))
+	}
+	for i, result := range results[:len(results)-1] {
+		if len(result.diagnostics) > 0 {
+			t.Errorf("file %d of %d has findings, but analysis went on", i+1, len(results))
+		}
+	}
+	if len(results[len(results)-1].diagnostics) == 0 {
+		t.Error("the last file analyzed has no findings")
+	}
+}
+
+func TestLimitFindings(t *testing.T) {
+	results := []fileResult{
+		{filename: "a.go", diagnostics: make([]analysis.Diagnostic, 2)},
+		{filename: "b.go"},
+		{filename: "c.go", diagnostics: make([]analysis.Diagnostic, 3)},
+		{filename: "d.go", diagnostics: make([]analysis.Diagnostic, 1)},
+	}
+	limited, dropped := limitFindings(results, 4)
+	var counts []int
+	for _, result := range limited {
+		counts = append(counts, len(result.diagnostics))
+	}
+	if !dropped || !reflect.DeepEqual(counts, []int{2, 0, 2, 0}) {
+		t.Errorf("limitFindings(4) = %v, %v, want [2 0 2 0], true", counts, dropped)
+	}
+	if _, dropped := limitFindings(results, 6); dropped {
+		t.Error("limitFindings(6) dropped findings of 6")
+	}
+	if got, dropped := limitFindings(results, 0); dropped || !reflect.DeepEqual(got, results) {
+		t.Error("limitFindings(0) changed the results")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonFinding is a finding as written by -format json.
+type jsonFinding struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	// Identifier is the flagged source text and Suggestion its suggested
+	// replacement, if the finding offers one
+	Identifier string `json:"identifier,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	// Rule is the rule ID, e.g. gonamefix/request-req for a mapping
+	Rule     string `json:"rule,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	URL      string `json:"url,omitempty"`
+}
+
+// newJSONFinding describes v, reading the source of its file into sources.
+func newJSONFinding(v violation, sources map[string][]byte) jsonFinding {
+	pos, end := v.fset.Position(v.diagnostic.Pos), v.fset.Position(v.diagnostic.End)
+	if !v.diagnostic.End.IsValid() {
+		end = pos
+	}
+	return jsonFinding{
+		File:       displayPath(pos.Filename),
+		Line:       pos.Line,
+		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
+		Identifier: findingText(v, sources),
+		Suggestion: suggestion(v),
+		Rule:       v.diagnostic.Category,
+		Severity:   v.severity,
+		Message:    v.diagnostic.Message,
+		URL:        v.diagnostic.URL,
+	}
+}
+
+// suggestion returns the text the first fix of v puts in place of the
+// flagged span, or "" if it has none.
+func suggestion(v violation) string {
+	if len(v.diagnostic.SuggestedFixes) == 0 {
+		return ""
+	}
+	for _, edit := range v.diagnostic.SuggestedFixes[0].TextEdits {
+		if edit.Pos == v.diagnostic.Pos {
+			return string(edit.NewText)
+		}
+	}
+	return ""
+}
+
+// writeJSON writes violations to out as an indented JSON array, which is
+// empty rather than null without findings.
+func writeJSON(out io.Writer, violations []violation) error {
+	sources := make(map[string][]byte)
+	findings := make([]jsonFinding, 0, len(violations))
+	for _, v := range violations {
+		findings = append(findings, newJSONFinding(v, sources))
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
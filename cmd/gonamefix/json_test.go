@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestWriteJSON(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	filename := filepath.Join("testdata", "nested", "main.go")
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := writeJSON(&out, collectViolations([]fileResult{result})); err != nil {
+		t.Fatal(err)
+	}
+	var got []jsonFinding
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	want := []jsonFinding{{
+		File:       filename,
+		Line:       3,
+		Column:     5,
+		EndLine:    3,
+		EndColumn:  12,
+		Identifier: "request",
+		Suggestion: "req",
+		Rule:       "gonamefix/request-req",
+		Severity:   "error",
+		Message:    "suggest replacing 'request' with 'req'",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("writeJSON() = %+v, want %+v", got, want)
+	}
+
+	out.Reset()
+	if err := writeJSON(&out, nil); err != nil || out.String() != "[]\n" {
+		t.Errorf("writeJSON() without findings = %q, %v; want an empty array", out.String(), err)
+	}
+}
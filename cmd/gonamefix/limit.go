@@ -0,0 +1,20 @@
+package main
+
+// limitFindings keeps the first limit findings of results, in file order,
+// and reports whether any were dropped. A limit of 0 keeps them all.
+func limitFindings(results []fileResult, limit int) ([]fileResult, bool) {
+	if limit <= 0 {
+		return results, false
+	}
+	limited := make([]fileResult, len(results))
+	dropped := false
+	for i, result := range results {
+		limited[i] = result
+		if len(result.diagnostics) > limit {
+			limited[i].diagnostics = result.diagnostics[:limit]
+			dropped = true
+		}
+		limit -= len(limited[i].diagnostics)
+	}
+	return limited, dropped
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// listFiles prints the path of every file with at least one finding, once,
+// relative to the working directory or the base of -relative-paths and in
+// sorted order, like gofmt -l. It returns the number of files listed.
+func listFiles(out io.Writer, results []fileResult) int {
+	listed := make(map[string]bool)
+	var names []string
+	for _, result := range results {
+		name := reportPath(result.filename)
+		if len(result.diagnostics) == 0 || listed[name] {
+			continue
+		}
+		listed[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(out, name)
+	}
+	return len(names)
+}
+
+// relativePath returns path relative to the working directory, or path
+// itself when it cannot be made relative.
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// writeFileNames prints the path of every file with at least one of
+// violations, once, sorted and relative to the working directory or the base
+// of -relative-paths. Names are terminated by NUL with print0 and by newline
+// otherwise.
+func writeFileNames(out io.Writer, violations []violation, print0 bool) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, v := range violations {
+		name := reportPath(v.fset.Position(v.diagnostic.Pos).Filename)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	terminator := "\n"
+	if print0 {
+		terminator = "\x00"
+	}
+	for _, name := range names {
+		fmt.Fprint(out, name, terminator)
+	}
+}
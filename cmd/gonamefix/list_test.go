@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestListFiles(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	finding := []analysis.Diagnostic{{Message: "suggest replacing 'request' with 'req'"}}
+	results := []fileResult{
+		{filename: filepath.Join(wd, "testdata", "nested", "main.go"), diagnostics: finding},
+		{filename: filepath.Join("testdata", "nested", "pkg", "pkg.go")},
+		{filename: filepath.Join("testdata", "broken.go"), diagnostics: finding},
+		// Listed once even when given twice, also by another path
+		{filename: filepath.Join("testdata", "broken.go"), diagnostics: finding},
+		{filename: filepath.Join(wd, "testdata", "broken.go"), diagnostics: finding},
+	}
+
+	var out bytes.Buffer
+	if listed := listFiles(&out, results); listed != 2 {
+		t.Errorf("listFiles() = %d, want 2", listed)
+	}
+	want := filepath.Join("testdata", "broken.go") + "\n" + filepath.Join("testdata", "nested", "main.go") + "\n"
+	if out.String() != want {
+		t.Errorf("listFiles() printed %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteFileNames(t *testing.T) {
+	fset := token.NewFileSet()
+	at := func(filename string) violation {
+		file := fset.AddFile(filename, -1, 10)
+		return violation{fset: fset, diagnostic: analysis.Diagnostic{Pos: file.Pos(0)}}
+	}
+	violations := []violation{
+		at(filepath.Join("testdata", "with space", "b.go")),
+		at(filepath.Join("testdata", "a.go")),
+		at(filepath.Join("testdata", "with space", "b.go")),
+	}
+
+	var out bytes.Buffer
+	writeFileNames(&out, violations, false)
+	want := filepath.Join("testdata", "a.go") + "\n" + filepath.Join("testdata", "with space", "b.go") + "\n"
+	if out.String() != want {
+		t.Errorf("writeFileNames() printed %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	writeFileNames(&out, violations, true)
+	want = filepath.Join("testdata", "a.go") + "\x00" + filepath.Join("testdata", "with space", "b.go") + "\x00"
+	if out.String() != want {
+		t.Errorf("writeFileNames() with print0 printed %q, want %q", out.String(), want)
+	}
+}
@@ -1,134 +1,1152 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"golang.org/x/tools/go/analysis"
 
 	"github.com/xbpk3t/gonamefix"
+	"github.com/xbpk3t/gonamefix/lsp"
 )
 
 var (
-	checkFlag         = flag.String("check", "", "Name mappings in format 'old1:new1,old2:new2'")
-	excludeFilesFlag  = flag.String("exclude-files", "*.pb.go,*_test.go", "File patterns to exclude")
-	excludeDirsFlag   = flag.String("exclude-dirs", "vendor,node_modules,.git", "Directory patterns to exclude")
-	caseSensitiveFlag = flag.Bool("case-sensitive", false, "Case sensitive matching")
-	recursiveFlag     = flag.Bool("recursive", false, "Recursively scan directories")
-	configFileFlag    = flag.String("config", "", "Configuration file path")
-	helpFlag          = flag.Bool("help", false, "Show help")
+	mappingFileFlag         = flag.String("mapping-file", "", "Text file of old:new mappings, one per line, merged beneath -check")
+	checkURLFlag            = flag.String("check-url", "", "https URL of a shared mapping file merged beneath the local mappings")
+	refreshFlag             = flag.Bool("refresh", false, "Download the -check-url file even if the cached copy is current")
+	requireRemoteFlag       = flag.Bool("require-remote", false, "Fail instead of using the cached -check-url file when it cannot be downloaded")
+	onlyFlag                = flag.String("only", "", "Comma-separated originals or rule IDs of the only mappings to check")
+	skipMappingFlag         = flag.String("skip-mapping", "", "Comma-separated originals or rule IDs of mappings not to check")
+	docsBaseURLFlag         = flag.String("docs-base-url", "", "Documentation link appended to mapping findings; {id}, {original} and {replacement} are filled in")
+	excludeFilesFlag        = flag.String("exclude-files", "*.pb.go,*_test.go", "File patterns to exclude")
+	excludeFilesRegexFlag   = flag.String("exclude-files-regex", "", "Comma-separated regular expressions of file paths to exclude")
+	excludeDirsFlag         = flag.String("exclude-dirs", "node_modules,.git", "Directory patterns to exclude")
+	includeMarkdownFlag     = flag.Bool("include-markdown", false, "Also analyze go code blocks in .md files, without fixes")
+	includeVendorFlag       = flag.Bool("include-vendor", false, "Analyze code in vendor directories, which is skipped by default")
+	includeTestsFlag        = flag.Bool("include-tests", false, "Analyze _test.go files, dropping *_test.go from -exclude-files")
+	includeGeneratedFlag    = flag.Bool("include-generated", false, "Analyze files marked \"// Code generated ... DO NOT EDIT.\", which are skipped by default")
+	excludeConstraintsFlag  = flag.String("exclude-build-constraints", "ignore", "Build tags whose //go:build-guarded files are skipped")
+	tagsFlag                = flag.String("tags", "", "Comma-separated build tags to satisfy, like go build -tags")
+	allFilesFlag            = flag.Bool("all-files", false, "Analyze every .go file regardless of build constraints")
+	allBuildConfigsFlag     = flag.Bool("all-build-configs", false, "Analyze the files of every build tag combination their constraints mention")
+	skipCgoFlag             = flag.Bool("skip-cgo", false, "Skip files that import \"C\"")
+	caseSensitiveFlag       = flag.Bool("case-sensitive", false, "Case sensitive matching")
+	recursiveFlag           = flag.Bool("recursive", true, "Recursively scan directories, the default")
+	noRecursiveFlag         = flag.Bool("no-recursive", false, "Only scan the files directly in the directories given")
+	modulesFlag             = flag.String("modules", "", "Comma-separated go.work modules to scan (default all)")
+	includeSubmodulesFlag   = flag.Bool("include-submodules", false, "Descend into nested Go modules when scanning recursively")
+	priorityPatternsFlag    = flag.String("priority-patterns", "", "Comma-separated originals whose mappings take precedence over all others")
+	initialismsFlag         = flag.Bool("initialisms", false, "Flag initialisms written in mixed case (userId -> userID)")
+	allowedLongNamesFlag    = flag.String("allowed-long-names", "", "Comma-separated identifiers that are never flagged")
+	excludeNamesFlag        = flag.String("exclude-names", "", "Comma-separated identifiers or globs the mappings never flag")
+	hungarianFlag           = flag.Bool("hungarian", false, "Flag Hungarian notation prefixes (strName -> name)")
+	noSnakeCaseFlag         = flag.Bool("no-snake-case", false, "Flag snake_case identifiers and suggest camelCase")
+	interfaceNamingFlag     = flag.Bool("interface-naming", false, "Flag single-method interfaces not named after their method")
+	getterNamingFlag        = flag.Bool("getter-naming", false, "Flag GetXxx getters and suggest Xxx")
+	errorNamingFlag         = flag.Bool("error-naming", false, "Flag error variables not named ErrXxx, err or errXxx")
+	boolNamingFlag          = flag.Bool("bool-naming", false, "Flag bool names that do not read as predicates (valid -> isValid)")
+	boolNamingKindsFlag     = flag.String("bool-naming-kinds", "", "Comma-separated kinds checked by -bool-naming: var, field, func (default all)")
+	noStutterFlag           = flag.Bool("no-stutter", false, "Flag exported top-level names that repeat the package name")
+	stutterUnexportedFlag   = flag.Bool("stutter-unexported", false, "Also apply -no-stutter to unexported names")
+	noAllCapsFlag           = flag.Bool("no-all-caps", false, "Flag SCREAMING_SNAKE_CASE constants and suggest CamelCase")
+	allCapsVarsFlag         = flag.Bool("all-caps-vars", false, "Also apply -no-all-caps to variables")
+	synonymConsistencyFlag  = flag.Bool("synonym-consistency", false, "Report synonyms of a canonical replacement in packages that also use the canonical form")
+	receiverConsistencyFlag = flag.Bool("receiver-consistency", false, "Flag receivers named differently from the other methods of the type")
+	maxLengthFlag           = flag.Int("max-length", 0, "Flag identifiers longer than this many characters (0 disables)")
+	testTableNamesFlag      = flag.Bool("replace-in-test-table-names", false, "Also check test case names in table-driven tests")
+	checkStringsFlag        = flag.String("check-strings", "", "Comma-separated call:arg string arguments to check, e.g. 'slog.With:0,*.WithField:0'")
+	checkCommentsFlag       = flag.Bool("check-comments", false, "Also report mapped words in comments, without fixes")
+	checkFilenamesFlag      = flag.Bool("check-filenames", false, "Also apply the mappings to file names; -fix renames the files")
+	checkLoopVarsFlag       = flag.Bool("check-loop-vars", false, "Also check short loop variables such as i and k, v")
+	onlyKindsFlag           = flag.String("only-kinds", "", "Comma-separated declaration kinds to check, e.g. 'func,method' (default all)")
+	showRelatedFlag         = flag.Bool("show-related", false, "Print the other occurrences of each flagged identifier under its diagnostic")
+	maxRelatedFlag          = flag.Int("max-related", 0, "Other occurrences listed per diagnostic (0 uses the default of 10, -1 none)")
+	traceMappingsFlag       = flag.Bool("trace-mappings", false, "Log every identifier tested against every pattern to stderr")
+	fallbackTokenizerFlag   = flag.Bool("fallback-to-tokenizer", false, "Scan identifier tokens of files that fail to parse")
+	listFlag                = flag.Bool("l", false, "List the files with findings, or changed by -fix, instead of the findings")
+	fixFlag                 = flag.Bool("fix", false, "Apply the safe suggested fixes to the files, or to stdin with -stdin")
+	interactiveFlag         = flag.Bool("interactive", false, "With -fix, ask before applying each rename")
+	interactiveIgnoreFlag   = flag.String("interactive-ignore", "", "File recording the renames declined with -interactive, which are not asked again")
+	noFormatFlag            = flag.Bool("no-format", false, "Write the fixed files as the fixes leave them, without formatting them like gofmt")
+	backupFlag              = flag.Bool("backup", false, "With -fix, back up each changed file before writing it, as file.go.orig")
+	backupSuffixFlag        = flag.String("backup-suffix", ".orig", "Suffix of the backups written with -backup")
+	forceBackupFlag         = flag.Bool("force-backup", false, "Overwrite existing backups instead of refusing to fix")
+	restoreBackupsFlag      = flag.Bool("restore-backups", false, "Move the backups of the files given back in place, then exit")
+	dryRunFlag              = flag.Bool("dry-run", false, "List the renames -fix would apply instead of the findings")
+	diffFlag                = flag.Bool("diff", false, "Print the safe fixes as a unified diff instead of applying them")
+	stdinFlag               = flag.Bool("stdin", false, "Read one file from stdin and write it to stdout, fixed with -fix")
+	stdinFilenameFlag       = flag.String("stdin-filename", "", "File name of the source read from stdin, for positions and exclusions")
+	colorFlag               = flag.String("color", "auto", "Color the text output: auto (on a terminal without NO_COLOR), always or never")
+	formatFlag              = flag.String("format", "text", "Output format: text, json, sarif, checkstyle, files or edits; text or json for the top subcommand")
+	filesFromFlag           = flag.String("files-from", "", "Read the files to analyze from this file, or from stdin if -, one per line or NUL-separated")
+	nulFlag                 = flag.Bool("z", false, "The list of -files-from is NUL-separated, as detected when it contains a NUL")
+	print0Flag              = flag.Bool("print0", false, "Separate the file names of -format files with NUL instead of newline")
+	sampleViolationsFlag    = flag.Int("sample-violations", 0, "Show only a random sample of N violations (0 shows all)")
+	sampleSeedFlag          = flag.Int64("sample-seed", 0, "Seed for -sample-violations; 0 picks a new sample every run")
+	baselineFlag            = flag.String("baseline", "", "JSON baseline file whose findings are not reported")
+	writeBaselineFlag       = flag.String("write-baseline", "", "Write the current findings to this JSON baseline file, then exit")
+	pruneBaselineFlag       = flag.Bool("prune-baseline", false, "Remove the entries of -baseline that no longer match a finding")
+	trendFileFlag           = flag.String("trend-file", "", "Append a run summary to this JSON history file and print the trend")
+	trendKeepLastFlag       = flag.Int("trend-keep-last", 0, "Keep only the last N runs in the trend file (0 keeps all)")
+	dumpIdentifiersFlag     = flag.Bool("dump-identifiers", false, "Print every declared identifier as JSON lines instead of checking, with or without mappings")
+	whyExcludedFlag         = flag.String("why-excluded", "", "Explain which exclusion rule, if any, applies to the given path")
+	sinceFlag               = flag.String("since", "", "Analyze only the files changed between this git ref and HEAD, e.g. origin/main")
+	statsFlag               = flag.String("stats", "", "Write the number of findings, files and example locations of every mapping to this JSON file")
+	outputFlag              = flag.String("output", "", "Write the findings of -format to this file instead of stdout")
+	stagedFlag              = flag.Bool("staged", false, "Analyze the staged content of the Go files staged in git, failing on findings, for pre-commit hooks")
+	maxIssuesFlag           = flag.Int("max-issues", 0, "Stop after reporting this many findings and exit 1 (0 reports all)")
+	summaryFlag             = flag.Bool("summary", false, "Print the files scanned and the findings per mapping on stderr after the run")
+	watchFlag               = flag.Bool("watch", false, "Keep running and re-analyze the files that change, until interrupted")
+	errorOnNoViolationsFlag = flag.Bool("error-on-no-violations", false, "Exit with code 2 when no violations are found (smoke test mode)")
+	configFileFlag          = flag.String("config", "", "YAML configuration file; flags given on the command line take precedence")
+	helpFlag                = flag.Bool("help", false, "Show help")
+	jobsFlag                = flag.Int("jobs", runtime.NumCPU(), "Number of files analyzed in parallel")
+	progressFlag            = flag.Bool("progress", false, "Report the progress of the analysis on stderr")
+	severityFlag            = flag.String("severity", "error", "Severity of the findings: error, warning or info")
+	failSeverityFlag        = flag.String("fail-severity", severityInfo, "Exit 1 when a finding has at least this severity: error, warning or info, any finding")
+	verboseFlag             = flag.Bool("verbose", false, "Log on stderr why each file was skipped, or how many findings it had")
+	exitZeroFlag            = flag.Bool("exit-zero", false, "Exit 0 even when -l or -diff report findings; failures still exit 3")
+	versionFlag             = flag.Bool("version", false, "Print the version, VCS revision and Go version of the build")
 )
 
+// checkFlag is -check, which can be repeated.
+var checkFlag checkList
+
+// relativePathsFlag is -relative-paths, which takes an optional value.
+var relativePathsFlag pathBase
+
+// outputFormats are the values of -format for a run; the top subcommand
+// takes text and json.
+var outputFormats = []string{"text", "json", "sarif", "checkstyle", "files", "edits", "github-actions", "rdjson", "csv", "html"}
+
 func main() {
+	// -list is the long name of -l
+	flag.BoolVar(listFlag, "list", false, "Same as -l")
+	flag.Var(&checkFlag, "check", "Name mappings in format 'old1:new1,old2:new2'; can be repeated")
+	flag.Var(&relativePathsFlag, "relative-paths", "Print file paths relative to the working directory (cwd, the default) or the module root (module)")
 	flag.Parse()
 
+	// Subcommands take the same flags, given before or after their name
+	subcommand := ""
+	if flag.Arg(0) == "coverage" || flag.Arg(0) == "lsp" || flag.Arg(0) == "top" || flag.Arg(0) == "trend-summary" {
+		subcommand = flag.Arg(0)
+		if err := flag.CommandLine.Parse(flag.Args()[1:]); err != nil {
+			fatalUsage(err)
+		}
+	}
+
 	if *helpFlag {
 		showHelp()
 		return
 	}
 
-	config, err := loadConfiguration()
+	if *versionFlag {
+		if err := writeVersion(os.Stdout, readBuildInfo(), *formatFlag); err != nil {
+			fatalUsage(err)
+		}
+		return
+	}
+
+	// Findings become pull request annotations in GitHub Actions, unless
+	// another format is asked for, such as -format text to debug locally
+	formatSet := false
+	flag.Visit(func(f *flag.Flag) { formatSet = formatSet || f.Name == "format" })
+	if !formatSet && inGitHubActions() && subcommand == "" && !*watchFlag && !*interactiveFlag {
+		*formatFlag = "github-actions"
+	}
+
+	if subcommand == "top" && *formatFlag != "text" && *formatFlag != "json" {
+		fatalUsagef("invalid -format %q for top (expected text or json)", *formatFlag)
+	}
+	if subcommand != "top" && !slices.Contains(outputFormats, *formatFlag) {
+		fatalUsagef("invalid -format %q (expected one of %s)", *formatFlag, strings.Join(outputFormats, ", "))
+	}
+	if !slices.Contains(severities, *severityFlag) {
+		fatalUsagef("invalid -severity %q (expected one of %s)", *severityFlag, strings.Join(severities, ", "))
+	}
+	if !slices.Contains(severities, *failSeverityFlag) {
+		fatalUsagef("invalid -fail-severity %q (expected one of %s)", *failSeverityFlag, strings.Join(severities, ", "))
+	}
+	if !slices.Contains(colorModes, *colorFlag) {
+		fatalUsagef("invalid -color %q (expected one of %s)", *colorFlag, strings.Join(colorModes, ", "))
+	}
+	if *jobsFlag < 1 {
+		fatalUsagef("invalid -jobs %d (expected at least 1)", *jobsFlag)
+	}
+	if *print0Flag && *formatFlag != "files" {
+		fatalUsage("-print0 requires -format files")
+	}
+	if *diffFlag && (*fixFlag || *listFlag) {
+		fatalUsage("-diff cannot be combined with -fix or -l")
+	}
+	if *formatFlag == "edits" && (*fixFlag || *listFlag || *diffFlag || *dryRunFlag) {
+		fatalUsage("-format edits cannot be combined with -fix, -l, -diff or -dry-run")
+	}
+	if *interactiveFlag && (!*fixFlag || *listFlag || *formatFlag != "text" || isStdinMode(flag.Args())) {
+		fatalUsage("-interactive requires -fix and cannot be combined with -l, -format or standard input")
+	}
+	if *filesFromFlag != "" && isStdinMode(flag.Args()) {
+		fatalUsage("-files-from cannot be combined with standard input")
+	}
+	if *filesFromFlag == "-" && *interactiveFlag {
+		fatalUsage("-files-from - cannot be combined with -interactive, which reads its answers from standard input")
+	}
+	if *stagedFlag && (subcommand != "" || flag.NArg() > 0 || *filesFromFlag != "" || *stdinFlag || *sinceFlag != "" || *watchFlag ||
+		*fixFlag || *diffFlag || *dryRunFlag || *formatFlag == "edits") {
+		fatalUsage("-staged analyzes the git index and cannot be combined with subcommands, files, -files-from, -stdin, -since, -watch, -fix, -diff, -dry-run or -format edits")
+	}
+	if *outputFlag != "" && (subcommand != "" || *listFlag || *diffFlag || *dryRunFlag || *watchFlag) {
+		fatalUsage("-output cannot be combined with subcommands, -l, -diff, -dry-run or -watch")
+	}
+	if *nulFlag && *filesFromFlag == "" {
+		fatalUsage("-z requires -files-from")
+	}
+	if *interactiveIgnoreFlag != "" && !*interactiveFlag {
+		fatalUsage("-interactive-ignore requires -interactive")
+	}
+	if *dryRunFlag && (*fixFlag || *listFlag || *diffFlag) {
+		fatalUsage("-dry-run cannot be combined with -fix, -l or -diff")
+	}
+	if *backupFlag && (!*fixFlag || isStdinMode(flag.Args())) {
+		fatalUsage("-backup requires -fix and cannot be combined with standard input")
+	}
+	if *forceBackupFlag && !*backupFlag {
+		fatalUsage("-force-backup requires -backup")
+	}
+	if *backupSuffixFlag == "" {
+		fatalUsage("-backup-suffix cannot be empty")
+	}
+	if *restoreBackupsFlag && (subcommand != "" || *fixFlag || *backupFlag || *watchFlag) {
+		fatalUsage("-restore-backups cannot be combined with subcommands, -fix, -backup or -watch")
+	}
+	// Directories used to be scanned only with -recursive
+	recursiveSet := false
+	flag.Visit(func(f *flag.Flag) { recursiveSet = recursiveSet || f.Name == "recursive" })
+	if recursiveSet && *recursiveFlag && *noRecursiveFlag {
+		fatalUsage("-recursive cannot be combined with -no-recursive")
+	}
+	if recursiveSet && *recursiveFlag {
+		fmt.Fprintln(os.Stderr, "note: directories are scanned recursively by default; -recursive can be dropped")
+	}
+
+	if *watchFlag && (subcommand != "" || *fixFlag || *listFlag || *diffFlag || *dryRunFlag || *stdinFlag || *formatFlag != "text") {
+		fatalUsage("-watch prints findings as text and cannot be combined with subcommands, -fix, -l, -diff, -dry-run, -stdin or -format")
+	}
+	if *maxIssuesFlag < 0 {
+		fatalUsagef("invalid -max-issues %d (expected 0 or more)", *maxIssuesFlag)
+	}
+	if *maxIssuesFlag > 0 && (*fixFlag || *diffFlag || *dryRunFlag || *watchFlag || *writeBaselineFlag != "") {
+		fatalUsage("-max-issues cannot be combined with -fix, -diff, -dry-run, -watch or -write-baseline")
+	}
+	if *pruneBaselineFlag && *baselineFlag == "" {
+		fatalUsage("-prune-baseline requires -baseline")
+	}
+	if *writeBaselineFlag != "" && (*baselineFlag != "" || *fixFlag || *diffFlag || *dryRunFlag) {
+		fatalUsage("-write-baseline cannot be combined with -baseline, -fix, -diff or -dry-run")
+	}
+
+	if err := resolvePathBase(relativePathsFlag); err != nil {
+		fatalUsage(err)
+	}
+
+	// The trend summary reads the history file only
+	if subcommand == "trend-summary" {
+		path := *trendFileFlag
+		switch flag.NArg() {
+		case 0:
+		case 1:
+			path = flag.Arg(0)
+		default:
+			fatalUsage("trend-summary takes a single trend file")
+		}
+		if path == "" {
+			fatalUsage("trend-summary needs a trend file, as its argument or -trend-file")
+		}
+		if err := printTrendSummary(path); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	config, err := loadConfiguration("")
 	if err != nil {
-		log.Fatal(err)
+		fatalUsage(err)
+	}
+
+	// Restoring backups needs the files only, not the mappings
+	if *restoreBackupsFlag {
+		if flag.NArg() == 0 {
+			fatalUsage("-restore-backups needs files or directories")
+		}
+		files, err := collectFiles(flag.Args(), config.ExcludeDirs)
+		if err != nil {
+			fatal(err)
+		}
+		restored, err := restoreBackups(files, *backupSuffixFlag)
+		for _, filename := range restored {
+			fmt.Println(relativePath(filename))
+		}
+		fmt.Fprintf(os.Stderr, "restored %s files\n", formatCount(len(restored)))
+		if err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if *whyExcludedFlag != "" {
+		explainExclusion(*whyExcludedFlag, config)
+		return
+	}
+
+	// The inventory lists what the analyzer sees and needs no mappings
+	if *dumpIdentifiersFlag {
+		if flag.NArg() == 0 {
+			fatalUsage("-dump-identifiers needs files or directories")
+		}
+		collected, err := collectFiles(flag.Args(), config.ExcludeDirs)
+		if err != nil {
+			fatal(err)
+		}
+		files, _ := selectBuildFiles(buildContext(*tagsFlag), collected, buildSelectionOf(*allFilesFlag, *allBuildConfigsFlag))
+		if err := dumpIdentifiers(os.Stdout, files, config); err != nil {
+			fatal(err)
+		}
+		return
 	}
 
-	// If no check mappings provided, show help
-	if len(config.Check) == 0 {
+	// If no check mappings or rules provided, show help
+	if !config.HasRules() {
 		fmt.Println("Error: No name mappings provided.")
 		fmt.Println()
 		showHelp()
-		os.Exit(1)
+		os.Exit(exitUsage)
+	}
+
+	if subcommand == "lsp" {
+		if err := lsp.NewServer(config).Serve(os.Stdin, os.Stdout); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	// Formatter mode for editors: fixed source on stdout, diagnostics on
+	// stderr. Without -fix standard input is analyzed like a file, below
+	readStdin := isStdinMode(flag.Args())
+	if readStdin && *fixFlag {
+		out, errOut := io.Writer(os.Stdout), io.Writer(os.Stderr)
+		if *listFlag {
+			out, errOut = io.Discard, io.Discard
+		}
+		changed, err := fixStdin(os.Stdin, out, errOut, stdinName(), config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitFailure)
+		}
+		if *listFlag && changed {
+			fmt.Println(stdinName())
+			os.Exit(exitStatus(false, true, *exitZeroFlag))
+		}
+		return
 	}
 
 	analyzer := gonamefix.NewAnalyzer(config)
 
 	args := flag.Args()
-	if len(args) == 0 {
+	// Listed files are analyzed like arguments; an empty list finds no files
+	if *filesFromFlag != "" {
+		listed, err := readFilesFrom(*filesFromFlag, *nulFlag)
+		if err != nil {
+			fatalUsagef("reading -files-from: %v", err)
+		}
+		args = append(args, listed...)
+	}
+	if len(args) == 0 && !readStdin && *filesFromFlag == "" && !*stagedFlag {
 		fmt.Println("Error: No files or directories specified.")
 		showHelp()
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 
-	// Check if we're processing directories or files
-	var files []string
-	for _, arg := range args {
-		if info, err := os.Stat(arg); err == nil && info.IsDir() {
-			if *recursiveFlag {
-				dirFiles, err := findGoFiles(arg)
-				if err != nil {
-					log.Printf("Error scanning directory %s: %v", arg, err)
-					continue
-				}
-				files = append(files, dirFiles...)
-			} else {
-				dirFiles, err := findGoFilesInDir(arg)
-				if err != nil {
-					log.Printf("Error scanning directory %s: %v", arg, err)
-					continue
-				}
-				files = append(files, dirFiles...)
-			}
-		} else {
-			files = append(files, arg)
+	// Standard input is the only file read in stdin mode
+	var goFiles, markdownFiles []string
+	if !readStdin && !*stagedFlag {
+		collected, err := collectFiles(args, config.ExcludeDirs)
+		if err != nil {
+			fatal(err)
+		}
+		goFiles, markdownFiles = splitMarkdown(collected)
+	}
+	if *sinceFlag != "" && !readStdin {
+		goFiles, markdownFiles, err = sinceFilter(*sinceFlag, goFiles, markdownFiles)
+		if err != nil {
+			fatalUsage(err)
+		}
+	}
+	files, constraints := selectBuildFiles(buildContext(*tagsFlag), goFiles, buildSelectionOf(*allFilesFlag, *allBuildConfigsFlag))
+	// The modules of a go.work workspace may have configuration files of
+	// their own
+	modules, err := loadModuleConfigs(files, loadConfiguration)
+	if err != nil {
+		fatalUsage(err)
+	}
+
+	// Machine-readable output keeps stdout free of anything else
+	messages := io.Writer(os.Stdout)
+	if *formatFlag != "text" {
+		messages = os.Stderr
+	}
+
+	// A pre-commit hook passes when there is nothing to check
+	var stagedRoot string
+	var staged []string
+	if *stagedFlag {
+		stagedRoot, staged, err = stagedFiles(".", config)
+		if errors.Is(err, errNoRepository) {
+			fmt.Fprintln(messages, "-staged: not in a git repository, nothing to check")
+			return
+		}
+		if err != nil {
+			fatal(err)
+		}
+		if len(staged) == 0 {
+			fmt.Fprintln(messages, "-staged: no staged Go files, nothing to check")
+			return
+		}
+	}
+
+	var backup *backupPolicy
+	if *backupFlag {
+		backup = &backupPolicy{suffix: *backupSuffixFlag, force: *forceBackupFlag}
+	}
+
+	// Files are collected again on every rescan, so that new files are picked up
+	if *watchFlag {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		w := &watcher{
+			config: config,
+			scan: func() ([]string, map[string]string) {
+				// The arguments were collected without an error before
+				collected, _ := collectFiles(args, config.ExcludeDirs)
+				goFiles, _ := splitMarkdown(collected)
+				return selectBuildFiles(buildContext(*tagsFlag), goFiles, buildSelectionOf(*allFilesFlag, *allBuildConfigsFlag))
+			},
+			analyze: func(files []string, constraints map[string]string) ([]fileResult, []error) {
+				return analyzeFiles(analyzer, config, modules, files, constraints, *jobsFlag, 0, nil)
+			},
+			out:   newTextWriter(os.Stdout, useColor(*colorFlag, os.Stdout)),
+			clear: isTerminal(os.Stdout),
+		}
+		code := w.run(ctx)
+		stop()
+		os.Exit(code)
+	}
+
+	if len(files) == 0 && len(markdownFiles) == 0 && !readStdin && len(staged) == 0 {
+		fmt.Fprintln(messages, "No Go files found to analyze.")
+		report := createReport()
+		switch *formatFlag {
+		case "json":
+			err = writeJSON(report, nil)
+		case "sarif":
+			err = writeSARIF(report, nil, nil, config)
+		case "checkstyle":
+			err = writeCheckstyle(report, nil)
+		case "rdjson":
+			err = writeRDJSON(report, nil, nil)
+		case "csv":
+			err = writeCSV(report, nil, nil, config)
+		case "html":
+			err = writeHTML(report, nil, config, time.Now())
+		case "edits":
+			err = writeEdits(report, fixResult{})
+		}
+		if closeErr := closeReport(report); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			fatal(err)
 		}
+		return
 	}
 
-	if len(files) == 0 {
-		fmt.Println("No Go files found to analyze.")
+	if subcommand == "coverage" {
+		if err := runCoverage(os.Stdout, os.Stderr, files, config); err != nil {
+			fatal(err)
+		}
 		return
 	}
 
-	// Process each file
+	// Process each file, errors reported in file order once all are done
 	exitCode := 0
-	for _, file := range files {
-		if err := analyzeFile(analyzer, file); err != nil {
+	var status *progress
+	if *progressFlag && *formatFlag != "json" && *formatFlag != "sarif" && *formatFlag != "checkstyle" && *formatFlag != "edits" && *formatFlag != "rdjson" && *formatFlag != "html" {
+		status = newProgress(os.Stderr, len(files))
+	}
+	// Files stop being analyzed once enough findings are found, unless the
+	// baseline may suppress some of them
+	limit := *maxIssuesFlag
+	if *baselineFlag != "" || subcommand != "" || *statsFlag != "" {
+		limit = 0
+	}
+	results, errs := analyzeFiles(analyzer, config, modules, files, constraints, *jobsFlag, limit, status)
+	status.finish()
+	logDispositions(files, results, errs, config)
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Error analyzing %s: %v", files[i], err)
+			exitCode = exitFailure
+		}
+	}
+	// The blocks that do not parse are noted with -verbose only
+	markdownNotes := io.Discard
+	if *verboseFlag {
+		markdownNotes = os.Stderr
+	}
+	for _, file := range markdownFiles {
+		result, err := analyzeMarkdown(config, file, markdownNotes)
+		if err != nil {
 			log.Printf("Error analyzing %s: %v", file, err)
-			exitCode = 1
+			exitCode = exitFailure
+		}
+		verbosef("%s: %s", file, fileDisposition(result, err, config))
+		results = append(results, result)
+	}
+	if readStdin {
+		result, err := analyzeStdin(os.Stdin, stdinName(), config)
+		if err != nil {
+			log.Printf("Error analyzing %s: %v", result.filename, err)
+			exitCode = exitFailure
+		}
+		verbosef("%s: %s", result.filename, fileDisposition(result, err, config))
+		results = append(results, result)
+	}
+	for _, name := range staged {
+		result, err := analyzeStaged(stagedRoot, name, config)
+		if err != nil {
+			log.Printf("Error analyzing %s: %v", result.filename, err)
+			exitCode = exitFailure
+		}
+		verbosef("%s: %s", result.filename, fileDisposition(result, err, config))
+		results = append(results, result)
+	}
+
+	// The statistics count every finding, including those a baseline
+	// suppresses or -max-issues leaves out
+	if *statsFlag != "" {
+		if err := writeStats(*statsFlag, collectViolations(results), config); err != nil {
+			log.Printf("Error writing stats: %v", err)
+			exitCode = exitFailure
+		}
+	}
+
+	if *writeBaselineFlag != "" {
+		written, err := writeBaseline(*writeBaselineFlag, results)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Fprintf(messages, "wrote %s findings to baseline %s\n", formatCount(written), *writeBaselineFlag)
+		os.Exit(exitCode)
+	}
+	if *baselineFlag != "" {
+		var match baselineMatch
+		results, match, err = loadBaseline(*baselineFlag, results, *pruneBaselineFlag)
+		if err != nil {
+			fatalUsage(err)
+		}
+		if match.suppressed > 0 {
+			fmt.Fprintf(messages, "%s findings suppressed by baseline %s\n", formatCount(match.suppressed), *baselineFlag)
+		}
+		if *pruneBaselineFlag {
+			fmt.Fprintf(messages, "kept %s findings in baseline %s\n", formatCount(len(match.current.Findings)), *baselineFlag)
+		}
+	}
+
+	limited := false
+	if subcommand != "top" {
+		results, limited = limitFindings(results, *maxIssuesFlag)
+	}
+	// Reaching -max-issues fails the run like the findings of -l
+	noteLimit := func(out io.Writer) {
+		if limited {
+			fmt.Fprintln(out, "…and more (limit reached)")
+			exitCode = exitStatus(exitCode == exitFailure, true, *exitZeroFlag)
+		}
+	}
+
+	// The summary follows the output of every mode
+	printSummary := func() {
+		if *summaryFlag {
+			writeSummary(os.Stderr, newRunSummary(results, len(goFiles)-len(files), config))
+		}
+	}
+
+	all := collectViolations(results)
+	if subcommand == "top" {
+		if err := writeTop(os.Stdout, newTopReport(all), *formatFlag); err != nil {
+			fatal(err)
+		}
+		os.Exit(exitCode)
+	}
+
+	// Like gofmt -l, only the names of the files with findings, or of the
+	// files changed by -fix, are printed
+	if *listFlag {
+		listed := 0
+		if *fixFlag {
+			fixed, err := fixInPlace(results, nil, backup)
+			if err != nil {
+				log.Printf("Error applying fixes: %v", err)
+				exitCode = exitFailure
+			}
+			for _, file := range fixed.files {
+				fmt.Println(reportPath(file.filename))
+			}
+			listed = len(fixed.files)
+		} else {
+			listed = listFiles(os.Stdout, results)
+		}
+		// The list itself stays file names only
+		noteLimit(os.Stderr)
+		printSummary()
+		os.Exit(exitStatus(exitCode == exitFailure, exitCode == exitFindings || listed > 0 && failingFindings(all), *exitZeroFlag))
+	}
+
+	// Like gofmt -d, the fixes are printed instead of applied, and the exit
+	// code tells whether there are any
+	if *diffFlag {
+		fixed, err := computeFixes(results)
+		if err != nil {
+			log.Printf("Error computing fixes: %v", err)
+			exitCode = exitFailure
+		}
+		for _, file := range fixed.files {
+			name := filepath.ToSlash(relativePath(file.filename))
+			fmt.Print(unifiedDiff("a/"+name, "b/"+name, file.src, file.fixed))
+		}
+		printSummary()
+		os.Exit(exitStatus(exitCode == exitFailure, len(fixed.files) > 0 && failingFindings(all), *exitZeroFlag))
+	}
+
+	// The renames of -fix are listed instead of applied
+	if *dryRunFlag {
+		fixed, err := computeFixes(results)
+		if err != nil {
+			log.Printf("Error computing fixes: %v", err)
+			exitCode = exitFailure
+		}
+		writeDryRun(os.Stdout, fixed.renames, ruleNames(config))
+		if left := len(all) - fixed.fixed; left > 0 {
+			fmt.Fprintf(messages, "%s findings need a manual fix\n", formatCount(left))
+		}
+		printSummary()
+		os.Exit(exitCode)
+	}
+
+	report := createReport()
+	switch *formatFlag {
+	case "files":
+		writeFileNames(report, all, *print0Flag)
+	case "json":
+		if err := writeJSON(report, all); err != nil {
+			fatal(err)
+		}
+	case "sarif":
+		// Like -dry-run, only the fixes -fix would apply are included
+		fixed, err := computeFixes(results)
+		if err != nil {
+			log.Printf("Error computing fixes: %v", err)
+			exitCode = exitFailure
+		}
+		if err := writeSARIF(report, all, fixesByFinding(fixed), config); err != nil {
+			fatal(err)
+		}
+	case "checkstyle":
+		if err := writeCheckstyle(report, results); err != nil {
+			fatal(err)
+		}
+	case "github-actions":
+		if err := writeGitHubActions(report, all); err != nil {
+			fatal(err)
+		}
+	case "rdjson":
+		// Like -dry-run, only the fixes -fix would apply are suggested
+		fixed, err := computeFixes(results)
+		if err != nil {
+			log.Printf("Error computing fixes: %v", err)
+			exitCode = exitFailure
+		}
+		if err := writeRDJSON(report, all, fixesByFinding(fixed)); err != nil {
+			fatal(err)
+		}
+	case "csv":
+		if err := writeCSV(report, all, results, config); err != nil {
+			fatal(err)
+		}
+	case "html":
+		if err := writeHTML(report, results, config, time.Now()); err != nil {
+			fatal(err)
+		}
+	case "edits":
+		// Like -dry-run, the fixes are computed and left unapplied
+		fixed, err := computeFixes(results)
+		if err != nil {
+			log.Printf("Error computing fixes: %v", err)
+			exitCode = exitFailure
+		}
+		if err := writeEdits(report, fixed); err != nil {
+			fatal(err)
+		}
+		if left := len(all) - fixed.fixed; left > 0 {
+			fmt.Fprintf(messages, "%s findings need a manual fix\n", formatCount(left))
+		}
+	default:
+		shown := all
+		if *sampleViolationsFlag > 0 && *sampleViolationsFlag < len(all) {
+			seed := *sampleSeedFlag
+			if seed == 0 {
+				seed = time.Now().UnixNano()
+			}
+			shown = sampleViolations(all, *sampleViolationsFlag, seed)
+			fmt.Printf("showing %s of %s violations (use --sample-seed=%d to reproduce this sample)\n",
+				formatCount(len(shown)), formatCount(len(all)), seed)
+		}
+		// The prompts of -interactive show the findings one at a time
+		if *interactiveFlag {
+			shown = nil
+		}
+		text := newTextWriter(report, useColor(*colorFlag, report))
+		for _, v := range shown {
+			text.diagnostic(v)
+		}
+	}
+	if err := closeReport(report); err != nil {
+		fatal(err)
+	}
+	violations := len(all)
+	noteLimit(messages)
+	// Reported findings fail the run from -fail-severity up; those -fix
+	// fixes do not
+	failing := failingFindings(all)
+
+	if *fixFlag {
+		var confirm *confirmer
+		var fixed fixResult
+		if *interactiveFlag {
+			ignored, err := readIgnoredRenames(*interactiveIgnoreFlag)
+			if err != nil {
+				fatalUsage(err)
+			}
+			confirm = newConfirmer(os.Stdin, os.Stdout, ruleNames(config), ignored)
+			fixed, err = fixInPlace(results, confirm.confirm, backup)
+		} else {
+			fixed, err = fixInPlace(results, nil, backup)
+		}
+		if err != nil {
+			log.Printf("Error applying fixes: %v", err)
+			exitCode = exitFailure
+		}
+		if confirm != nil && *interactiveIgnoreFlag != "" {
+			if err := appendIgnoredRenames(*interactiveIgnoreFlag, confirm.declined); err != nil {
+				log.Printf("Error recording declined renames: %v", err)
+				exitCode = exitFailure
+			}
+		}
+		fmt.Fprintf(messages, "fixed %s identifiers in %s files\n", formatCount(fixed.fixed), formatCount(len(fixed.files)))
+		// Files are renamed once their content is fixed; -interactive only
+		// asks about identifiers
+		var renamed []renamedFile
+		if config.CheckFilenames && !*interactiveFlag {
+			renamed, err = renameFiles(results, config)
+			for _, file := range renamed {
+				fmt.Fprintf(messages, "renamed %s to %s\n", reportPath(file.from), reportPath(file.to))
+			}
+			if err != nil {
+				log.Printf("Error renaming files: %v", err)
+				exitCode = exitFailure
+			}
+		}
+		left := violations - fixed.fixed - len(renamed)
+		if left > 0 {
+			fmt.Fprintf(messages, "%s findings need a manual fix\n", formatCount(left))
+		}
+		failing = failing && left > 0
+	}
+	exitCode = exitStatus(exitCode == exitFailure, exitCode == exitFindings || failing, *exitZeroFlag)
+
+	if *onlyFlag != "" || *skipMappingFlag != "" {
+		fmt.Fprintf(messages, "note: mapping filter active (-only/-skip-mapping), mappings checked: %s\n",
+			formatCount(len(config.MappingPairs())))
+	}
+
+	if *trendFileFlag != "" {
+		if err := recordTrend(messages, *trendFileFlag, results, patternNames(config), *trendKeepLastFlag); err != nil {
+			log.Printf("Error recording trend: %v", err)
+			exitCode = exitFailure
 		}
 	}
 
+	printSummary()
+
+	// Smoke test mode: a config that catches nothing is treated as broken,
+	// unless the run failed, which says more
+	if *errorOnNoViolationsFlag && violations == 0 && exitCode != exitFailure {
+		fmt.Fprintln(os.Stderr, "Error: no violations found (-error-on-no-violations)")
+		os.Exit(exitUsage)
+	}
+
 	if exitCode != 0 {
 		os.Exit(exitCode)
 	}
 }
 
-func loadConfiguration() (gonamefix.Config, error) {
+// createReport returns the file the findings of -format are written to: the
+// -output file, created or truncated, or stdout.
+func createReport() *os.File {
+	if *outputFlag == "" {
+		return os.Stdout
+	}
+	f, err := os.Create(*outputFlag)
+	if err != nil {
+		fatal(err)
+	}
+	return f
+}
+
+// closeReport closes report unless it is stdout.
+func closeReport(report *os.File) error {
+	if report == os.Stdout {
+		return nil
+	}
+	return report.Close()
+}
+
+// collectFiles expands the command line arguments into Go files. Directories
+// are scanned recursively unless -no-recursive is given, and always when
+// written as dir/...; directories matching excludeDirs are not entered. Glob
+// patterns, such as internal/**/handler*.go, are expanded first. Arguments
+// that are not paths are package patterns, which the go command resolves;
+// the error reports patterns that fail to load or match no packages, and
+// relative or absolute paths that do not exist. Files are returned once, even
+// if several arguments match them.
+func collectFiles(args, excludeDirs []string) ([]string, error) {
+	var expanded []string
+	for _, arg := range args {
+		if !isGlobPattern(arg) {
+			expanded = append(expanded, arg)
+			continue
+		}
+		matches, err := expandGlob(arg)
+		if err != nil {
+			log.Printf("Error expanding pattern %s: %v", arg, err)
+			continue
+		}
+		if len(matches) == 0 {
+			log.Printf("warning: pattern %s matched no files", arg)
+		}
+		expanded = append(expanded, matches...)
+	}
+
+	var files, patterns []string
+	for _, arg := range expanded {
+		if isPackagePattern(arg) {
+			// A missing directory is not a package of the go command
+			if isPathLike(arg) {
+				return nil, fmt.Errorf("%s: no such file or directory", arg)
+			}
+			patterns = append(patterns, arg)
+			continue
+		}
+		recursive := *recursiveFlag && !*noRecursiveFlag
+		if dir, ok := strings.CutSuffix(arg, "/..."); ok {
+			arg, recursive = dir, true
+			if arg == "" {
+				arg = "/"
+			}
+		}
+
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			dirFiles, err := scanDir(arg, recursive, excludeDirs)
+			if err != nil {
+				log.Printf("Error scanning directory %s: %v", arg, err)
+				continue
+			}
+			files = append(files, dirFiles...)
+		} else {
+			files = append(files, arg)
+		}
+	}
+	if len(patterns) > 0 {
+		packageFiles, err := loadPackageFiles(patterns, *tagsFlag, *allFilesFlag || *allBuildConfigsFlag)
+		if err != nil {
+			return nil, fmt.Errorf("loading packages %s: %w", strings.Join(patterns, " "), err)
+		}
+		if len(packageFiles) == 0 {
+			return nil, fmt.Errorf("packages %s matched no Go files", strings.Join(patterns, " "))
+		}
+		files = append(files, packageFiles...)
+	}
+
+	// A glob can match a directory and files in it
+	seen := make(map[string]bool)
+	unique := files[:0]
+	for _, filename := range files {
+		if key := filepath.Clean(filename); !seen[key] {
+			seen[key] = true
+			unique = append(unique, filename)
+		}
+	}
+	return unique, nil
+}
+
+// scanDir returns the Go files in dir. Recursive scans of a go.work
+// workspace root cover every module in its use directives, and skip the
+// directories matching excludeDirs.
+func scanDir(dir string, recursive bool, excludeDirs []string) ([]string, error) {
+	if !recursive {
+		return findGoFilesInDir(dir)
+	}
+
+	modules, isWorkspace, err := workspaceModules(dir)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkspace {
+		return findWorkspaceFiles(dir, modules, moduleFilter(), *includeVendorFlag, excludeDirs)
+	}
+	return findGoFiles(dir, *includeSubmodulesFlag, *includeVendorFlag, excludeDirs)
+}
+
+// moduleFilter returns the modules selected with -modules.
+func moduleFilter() []string {
+	if *modulesFlag == "" {
+		return nil
+	}
+	return strings.Split(*modulesFlag, ",")
+}
+
+// loadConfiguration returns the configuration of the flags and of -config,
+// and of moduleConfigFile, the configuration file of a workspace module,
+// unless it is empty. The file of the module applies over -config, and the
+// flags given on the command line over both.
+func loadConfiguration(moduleConfigFile string) (gonamefix.Config, error) {
 	config := gonamefix.Config{
-		ExcludeFiles:  strings.Split(*excludeFilesFlag, ","),
-		ExcludeDirs:   strings.Split(*excludeDirsFlag, ","),
-		CaseSensitive: *caseSensitiveFlag,
-	}
-
-	// Parse check flag
-	if *checkFlag != "" {
-		pairs := strings.Split(*checkFlag, ",")
-		for _, pair := range pairs {
-			parts := strings.Split(pair, ":")
-			if len(parts) == 2 {
-				config.Check = append(config.Check, []string{strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])})
-			} else {
-				return config, fmt.Errorf("invalid mapping format: %s (expected 'old:new')", pair)
+		ExcludeFiles:     strings.Split(*excludeFilesFlag, ","),
+		ExcludeDirs:      strings.Split(*excludeDirsFlag, ","),
+		SkipCgo:          *skipCgoFlag,
+		IncludeVendor:    *includeVendorFlag,
+		IncludeTests:     *includeTestsFlag,
+		IncludeGenerated: *includeGeneratedFlag,
+		DocsBaseURL:      *docsBaseURLFlag,
+		CheckURL:         *checkURLFlag,
+		CaseSensitive:    *caseSensitiveFlag,
+		Initialisms:      *initialismsFlag,
+		MaxLength:        *maxLengthFlag,
+		Hungarian:        *hungarianFlag,
+
+		ReceiverConsistency: *receiverConsistencyFlag,
+		NoSnakeCase:         *noSnakeCaseFlag,
+		InterfaceNaming:     *interfaceNamingFlag,
+		GetterNaming:        *getterNamingFlag,
+		ErrorNaming:         *errorNamingFlag,
+		BoolNaming:          *boolNamingFlag,
+		NoStutter:           *noStutterFlag,
+		StutterUnexported:   *stutterUnexportedFlag,
+		NoAllCaps:           *noAllCapsFlag,
+		AllCapsVars:         *allCapsVarsFlag,
+		SynonymConsistency:  *synonymConsistencyFlag,
+
+		FallbackToTokenizer: *fallbackTokenizerFlag,
+		TraceMappings:       *traceMappingsFlag,
+		CheckLoopVars:       *checkLoopVarsFlag,
+		MaxRelated:          *maxRelatedFlag,
+		CheckComments:       *checkCommentsFlag,
+		CheckFilenames:      *checkFilenamesFlag,
+
+		ReplaceInTestTableNames: *testTableNamesFlag,
+	}
+
+	if *excludeFilesRegexFlag != "" {
+		config.ExcludeFilesRegex = strings.Split(*excludeFilesRegexFlag, ",")
+	}
+
+	if *excludeConstraintsFlag != "" {
+		config.ExcludeBuildConstraints = strings.Split(*excludeConstraintsFlag, ",")
+	}
+
+	if *allowedLongNamesFlag != "" {
+		for _, name := range strings.Split(*allowedLongNamesFlag, ",") {
+			config.AllowedLongNames = append(config.AllowedLongNames, strings.TrimSpace(name))
+		}
+	}
+
+	if *excludeNamesFlag != "" {
+		for _, name := range strings.Split(*excludeNamesFlag, ",") {
+			config.ExcludeNames = append(config.ExcludeNames, strings.TrimSpace(name))
+		}
+	}
+
+	if *onlyKindsFlag != "" {
+		for _, kind := range strings.Split(*onlyKindsFlag, ",") {
+			config.OnlyKinds = append(config.OnlyKinds, strings.TrimSpace(kind))
+		}
+	}
+
+	if *boolNamingKindsFlag != "" {
+		for _, kind := range strings.Split(*boolNamingKindsFlag, ",") {
+			config.BoolNamingKinds = append(config.BoolNamingKinds, strings.TrimSpace(kind))
+		}
+	}
+
+	if *priorityPatternsFlag != "" {
+		for _, original := range strings.Split(*priorityPatternsFlag, ",") {
+			config.PriorityPatterns = append(config.PriorityPatterns, strings.TrimSpace(original))
+		}
+	}
+
+	if *checkStringsFlag != "" {
+		for _, spec := range strings.Split(*checkStringsFlag, ",") {
+			call, arg, hasArg := strings.Cut(strings.TrimSpace(spec), ":")
+			rule := gonamefix.StringCall{Call: call}
+			if hasArg {
+				index, err := strconv.Atoi(arg)
+				if err != nil || index < 0 {
+					return config, fmt.Errorf("invalid -check-strings entry: %s (expected 'call:arg')", spec)
+				}
+				rule.Arg = index
+			}
+			config.CheckStrings = append(config.CheckStrings, rule)
+		}
+	}
+
+	check, err := parseCheck(checkFlag)
+	if err != nil {
+		return config, err
+	}
+	config.Check = append(config.Check, check...)
+
+	// Values of the configuration files apply unless a flag is given
+	var configFiles []string
+	for _, path := range []string{*configFileFlag, moduleConfigFile} {
+		if path != "" {
+			configFiles = append(configFiles, path)
+		}
+	}
+	if len(configFiles) > 0 {
+		flagConfig := config
+		for _, path := range configFiles {
+			if err := readConfigFile(path, &config); err != nil {
+				return config, err
 			}
 		}
+		set := make(map[string]bool)
+		flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		overrideSetFlags(&config, flagConfig, set)
+	}
+
+	// Case sensitivity may come from the configuration file
+	if err := checkDuplicates(check, config.CaseSensitive); err != nil {
+		return config, err
+	}
+
+	if *mappingFileFlag != "" {
+		pairs, err := readMappingFile(*mappingFileFlag)
+		if err != nil {
+			return config, err
+		}
+		config.Check = mergeMappingFile(config.Check, pairs)
+	}
+
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if config.CheckURL != "" {
+		fetcher, err := newRemoteFetcher(*refreshFlag, *requireRemoteFlag)
+		if err != nil {
+			return config, err
+		}
+		if err := loadRemoteMappings(&config, fetcher, config.CheckURL); err != nil {
+			return config, err
+		}
 	}
 
+	if *onlyFlag != "" || *skipMappingFlag != "" {
+		filtered, err := config.FilterMappings(splitList(*onlyFlag), splitList(*skipMappingFlag))
+		if err != nil {
+			return config, fmt.Errorf("invalid mapping filter: %w", err)
+		}
+		config = filtered
+	}
 	return config, nil
 }
 
-func analyzeFile(analyzer *analysis.Analyzer, filename string) error {
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// explainExclusion prints the rule that excludes path, or states that it would be analyzed.
+func explainExclusion(path string, config gonamefix.Config) {
+	if exclusion, excluded := gonamefix.MatchExclusion(path, config); excluded {
+		fmt.Printf("%s: %s\n", path, exclusion)
+		return
+	}
+	fmt.Printf("%s: not excluded, would be analyzed\n", path)
+}
+
+// fileResult holds the diagnostics reported for a single file.
+type fileResult struct {
+	filename    string
+	fset        *token.FileSet
+	diagnostics []analysis.Diagnostic
+	// src is the content analyzed, which the positions refer to, kept for
+	// reports that quote the flagged lines
+	src []byte
+
+	// constraint names the build constraint that excludes the file on the
+	// host; it is only set for files analyzed because of -all-files
+	constraint string
+}
+
+// analyzeFile runs the analyzer on a single file, printing and returning the reported diagnostics.
+func analyzeFile(analyzer *analysis.Analyzer, config gonamefix.Config, filename string) (fileResult, error) {
 	fset := token.NewFileSet()
+	result := fileResult{filename: filename, fset: fset}
+
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return result, err
+	}
+	result.src = src
 
 	// Parse the file
-	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
 	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
+		if config.FallbackToTokenizer {
+			return analyzeTokens(config, filename, src, err)
+		}
+		return result, fmt.Errorf("parse error: %w", err)
 	}
 
 	// Create a pass for the analyzer
@@ -137,33 +1155,58 @@ func analyzeFile(analyzer *analysis.Analyzer, filename string) error {
 		Fset:     fset,
 		Files:    []*ast.File{file},
 		Report: func(d analysis.Diagnostic) {
-			pos := fset.Position(d.Pos)
-			fmt.Printf("%s:%d:%d: %s\n", pos.Filename, pos.Line, pos.Column, d.Message)
+			result.diagnostics = append(result.diagnostics, d)
 		},
 		ResultOf: make(map[*analysis.Analyzer]interface{}),
 	}
 
 	// Need to handle required analyzers
 	for _, req := range analyzer.Requires {
-		result, err := req.Run(pass)
+		res, err := req.Run(pass)
 		if err != nil {
-			return fmt.Errorf("required analyzer %s failed: %w", req.Name, err)
+			return result, fmt.Errorf("required analyzer %s failed: %w", req.Name, err)
 		}
-		pass.ResultOf[req] = result
+		pass.ResultOf[req] = res
 	}
 
-	// Run the analyzer
+	// Run the analyzer; findings are printed in position order, not in the
+	// order they are reported in
 	_, err = analyzer.Run(pass)
-	return err
+	gonamefix.SortDiagnostics(fset, result.diagnostics)
+	return result, err
+}
+
+// analyzeTokens reports the token based diagnostics for a file with content
+// src that failed to parse. The parse error is still returned so the run is marked as failed.
+func analyzeTokens(config gonamefix.Config, filename string, src []byte, parseErr error) (fileResult, error) {
+	fset := token.NewFileSet()
+	result := fileResult{filename: filename, fset: fset, src: src}
+	result.diagnostics = gonamefix.AnalyzeTokens(fset, filename, src, config)
+	return result, fmt.Errorf("parse error (partial results reported): %w", parseErr)
 }
 
-func findGoFiles(root string) ([]string, error) {
+func findGoFiles(root string, includeSubmodules, includeVendor bool, excludeDirs []string) ([]string, error) {
 	var files []string
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if strings.HasSuffix(path, ".go") && !strings.Contains(path, "vendor/") {
+		if info.IsDir() && path != root {
+			// A go.mod below the starting directory marks the root of a different module
+			if !includeSubmodules && isModuleRoot(path) {
+				verbosef("%s: skipped, a nested module (use -include-submodules)", path)
+				return filepath.SkipDir
+			}
+			if gonamefix.SkipVendor(path, includeVendor) {
+				verbosef("%s: skipped, a vendor directory (use -include-vendor)", path)
+				return filepath.SkipDir
+			}
+			if pattern, skip := gonamefix.MatchExcludedDir(path, excludeDirs); skip {
+				verbosef("%s: %s", path, gonamefix.Exclusion{Source: "exclude-dirs", Pattern: pattern})
+				return filepath.SkipDir
+			}
+		}
+		if isSourceFile(path) {
 			files = append(files, path)
 		}
 		return nil
@@ -171,6 +1214,18 @@ func findGoFiles(root string) ([]string, error) {
 	return files, err
 }
 
+// isSourceFile reports whether a file found in a directory is analyzed: Go
+// files, and Markdown files with -include-markdown.
+func isSourceFile(name string) bool {
+	return strings.HasSuffix(name, ".go") || *includeMarkdownFlag && isMarkdown(name)
+}
+
+// isModuleRoot reports whether dir contains a go.mod file.
+func isModuleRoot(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil && !info.IsDir()
+}
+
 func findGoFilesInDir(dir string) ([]string, error) {
 	var files []string
 	entries, err := os.ReadDir(dir)
@@ -179,7 +1234,7 @@ func findGoFilesInDir(dir string) ([]string, error) {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+		if !entry.IsDir() && isSourceFile(entry.Name()) {
 			files = append(files, filepath.Join(dir, entry.Name()))
 		}
 	}
@@ -191,37 +1246,572 @@ func showHelp() {
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  gonamefix [flags] <files or directories>")
+	fmt.Println("  gonamefix coverage [flags] <files or directories>")
+	fmt.Println("  gonamefix top [flags] <files or directories>")
+	fmt.Println("  gonamefix lsp [flags]")
+	fmt.Println("  gonamefix trend-summary [flags] [trend file]")
+	fmt.Println("  gonamefix [flags] [-stdin-filename name] - < file.go")
+	fmt.Println("  gonamefix -fix -stdin [flags] < file.go")
+	fmt.Println()
+	fmt.Println("  Directories are scanned recursively, skipping those matching -exclude-dirs;")
+	fmt.Println("  with -no-recursive only a directory written as dir/... is. At the root of a go.work")
+	fmt.Println("  workspace every module in its use directives is scanned; the files of a")
+	fmt.Println("  module with a .gonamefix.yml are analyzed with it, read over -config.")
+	fmt.Println("  Arguments that are not files or directories, such as example.com/mod/... or")
+	fmt.Println("  std, are package patterns: the go command resolves their files, tests")
+	fmt.Println("  included, with -tags.")
+	fmt.Println("  Quoted glob patterns are expanded relative to the working directory, with **")
+	fmt.Println("  matching any number of directories; paths that exist are never patterns.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  coverage")
+	fmt.Println("        Print how many identifiers each -check mapping matches and warn about")
+	fmt.Println("        mappings that match none")
+	fmt.Println()
+	fmt.Println("  top")
+	fmt.Println("        Rank the findings instead of listing them: the 20 identifiers and files")
+	fmt.Println("        with the most findings and every rule by hit count, with their share of")
+	fmt.Println("        the total. -format json prints the ranking as JSON")
+	fmt.Println()
+	fmt.Println("  lsp")
+	fmt.Println("        Run a language server on stdin/stdout that offers suggested fixes as")
+	fmt.Println("        quick fix code actions")
+	fmt.Println()
+	fmt.Println("  trend-summary")
+	fmt.Println("        Print the runs recorded in the trend file, the argument or -trend-file,")
+	fmt.Println("        as a table of violation counts over time")
 	fmt.Println()
 	fmt.Println("Flags:")
+	fmt.Println("  -config string")
+	fmt.Println("        YAML configuration file with the settings of the gonamefix linter, as under")
+	fmt.Println("        linters-settings.gonamefix in .golangci.yml. Flags given on the command line")
+	fmt.Println("        take precedence over its values; unknown keys are an error")
+	fmt.Println("        Example: -config .gonamefix.yml")
+	fmt.Println()
 	fmt.Println("  -check string")
-	fmt.Println("        Name mappings in format 'old1:new1,old2:new2'")
+	fmt.Println("        Name mappings in format 'old1:new1,old2:new2'. The flag can be repeated,")
+	fmt.Println("        each time with one or more mappings; a word mapped twice is an error")
 	fmt.Println("        Example: -check 'request:req,response:res,configuration:config'")
+	fmt.Println("        Example: -check request:req -check response:res")
+	fmt.Println()
+	fmt.Println("  -mapping-file string")
+	fmt.Println("        Text file of mappings, one old:new pair per line; blank lines and # comments")
+	fmt.Println("        are ignored. A word also mapped by -check or the configuration file takes")
+	fmt.Println("        that mapping. Malformed lines are reported with their line number")
+	fmt.Println("        Example: -mapping-file naming.txt")
+	fmt.Println()
+	fmt.Println("  -check-url string")
+	fmt.Println("        https URL of a shared mapping file with check and mappings sections, as in a")
+	fmt.Println("        configuration file. Its mappings apply beneath the local ones, which win for")
+	fmt.Println("        the same word. The file is cached and revalidated by its ETag; when it cannot")
+	fmt.Println("        be downloaded the cached copy is used with a warning")
+	fmt.Println("        Example: -check-url 'https://example.com/naming.yml'")
+	fmt.Println()
+	fmt.Println("  -refresh")
+	fmt.Println("        Download the -check-url file even if the cached copy is current (default false)")
+	fmt.Println()
+	fmt.Println("  -require-remote")
+	fmt.Println("        Fail when the -check-url file cannot be downloaded instead of using the")
+	fmt.Println("        cached copy (default false)")
+	fmt.Println()
+	fmt.Println("  -only string")
+	fmt.Println("        Check only the mappings with these comma-separated originals or rule IDs.")
+	fmt.Println("        A name that matches no mapping is an error")
+	fmt.Println("        Example: -only 'request,gonamefix/password-pwd'")
+	fmt.Println()
+	fmt.Println("  -skip-mapping string")
+	fmt.Println("        Do not check the mappings with these comma-separated originals or rule IDs")
+	fmt.Println("        Example: -skip-mapping 'user'")
+	fmt.Println()
+	fmt.Println("  -docs-base-url string")
+	fmt.Println("        Documentation link appended to findings of the mappings. {id} is replaced by")
+	fmt.Println("        the rule ID, gonamefix/<original>-<replacement>, and {original} and")
+	fmt.Println("        {replacement} by the words of the mapping")
+	fmt.Println("        Example: -docs-base-url 'https://wiki.example.com/naming#{id}'")
 	fmt.Println()
 	fmt.Println("  -exclude-files string")
 	fmt.Println("        File patterns to exclude (default \"*.pb.go,*_test.go\")")
 	fmt.Println()
+	fmt.Println("  -exclude-files-regex string")
+	fmt.Println("        Regular expressions of files to exclude, matched against the whole path with")
+	fmt.Println("        slashes, in addition to -exclude-files. Absolute paths are made relative to")
+	fmt.Println("        the root of their module first. Expressions containing commas must be")
+	fmt.Println("        set in the configuration file. Invalid expressions are reported")
+	fmt.Println("        Example: -exclude-files-regex '(^|/)api/.*(_gen|\\.generated)\\.go$'")
+	fmt.Println()
 	fmt.Println("  -exclude-dirs string")
-	fmt.Println("        Directory patterns to exclude (default \"vendor,node_modules,.git\")")
+	fmt.Println("        Directory patterns to exclude (default \"node_modules,.git\")")
+	fmt.Println()
+	fmt.Println("  -include-markdown")
+	fmt.Println("        Also analyze .md files found in directories or given as arguments: their")
+	fmt.Println("        fenced go and golang code blocks are checked, with findings at their lines")
+	fmt.Println("        in the Markdown file. Snippets without a package clause are wrapped in one.")
+	fmt.Println("        Blocks that do not parse are skipped, with a note under -verbose; no fixes")
+	fmt.Println("        are offered. .md files given as arguments are always analyzed (default false)")
+	fmt.Println()
+	fmt.Println("  -include-vendor")
+	fmt.Println("        Analyze code in vendor directories, e.g. patches to vendored packages.")
+	fmt.Println("        Vendored code is skipped otherwise (default false)")
+	fmt.Println()
+	fmt.Println("  -include-tests")
+	fmt.Println("        Analyze _test.go files: the *_test.go pattern is dropped from -exclude-files,")
+	fmt.Println("        whether it is the default or given. The other patterns still apply, also")
+	fmt.Println("        those matching test files such as mock_*_test.go (default false)")
+	fmt.Println()
+	fmt.Println("  -include-generated")
+	fmt.Println("        Analyze generated files, such as mocks, stringer or protoc output. Files")
+	fmt.Println("        with a \"// Code generated ... DO NOT EDIT.\" comment before the package")
+	fmt.Println("        clause are skipped otherwise, whatever their name (default false)")
+	fmt.Println()
+	fmt.Println("  -exclude-build-constraints string")
+	fmt.Println("        Skip files whose //go:build line references one of these tags (default \"ignore\")")
+	fmt.Println("        Example: -exclude-build-constraints 'ignore,tools'")
+	fmt.Println()
+	fmt.Println("  -tags string")
+	fmt.Println("        Build tags to satisfy when evaluating //go:build lines, like go build -tags.")
+	fmt.Println("        Files that do not build on the host with these tags are skipped")
+	fmt.Println("        Example: -tags 'integration,tools'")
+	fmt.Println()
+	fmt.Println("  -all-files")
+	fmt.Println("        Analyze every .go file regardless of build constraints and GOOS/GOARCH")
+	fmt.Println("        file name suffixes. Findings in files that would not build on the host")
+	fmt.Println("        are labeled with the excluding constraint (default false)")
+	fmt.Println()
+	fmt.Println("  -all-build-configs")
+	fmt.Println("        Analyze the files of every build: of any combination of the build tags")
+	fmt.Println("        their //go:build lines mention, on any GOOS and GOARCH. Unlike -all-files,")
+	fmt.Println("        files whose constraints can never hold, such as linux && !linux, are")
+	fmt.Println("        skipped. Findings are labeled like with -all-files (default false)")
+	fmt.Println()
+	fmt.Println("  -since string")
+	fmt.Println("        Analyze only the files of the arguments changed between this git ref and")
+	fmt.Println("        HEAD, as listed by git diff --name-only ref...HEAD, e.g. on pull requests.")
+	fmt.Println("        Deleted files are ignored; fails outside a git repository")
+	fmt.Println("        Example: -since origin/main")
+	fmt.Println()
+	fmt.Println("  -staged")
+	fmt.Println("        Analyze the Go files staged in git, as they will be committed rather than")
+	fmt.Println("        as they are in the working tree, and fail on findings. Takes no files;")
+	fmt.Println("        passes with a message outside a git repository or with nothing staged")
+	fmt.Println("        Example: gonamefix -staged (in .git/hooks/pre-commit)")
+	fmt.Println()
+	fmt.Println("  -skip-cgo")
+	fmt.Println("        Skip files that import \"C\". Otherwise they are checked, except for //export")
+	fmt.Println("        functions and names selected from C such as C.struct_request (default false)")
 	fmt.Println()
 	fmt.Println("  -case-sensitive")
 	fmt.Println("        Case sensitive matching (default false)")
 	fmt.Println()
+	fmt.Println("  -allowed-long-names string")
+	fmt.Println("        Comma-separated identifiers that are never flagged, in addition to")
+	fmt.Println("        the built-in list of standard library names")
+	fmt.Println("        Example: -allowed-long-names 'ErrTimeout,MaxHeaderSize'")
+	fmt.Println()
+	fmt.Println("  -exclude-names string")
+	fmt.Println("        Comma-separated identifiers the mappings never flag or fix, matched exactly")
+	fmt.Println("        or as globs. Other identifiers using the same mappings are still flagged")
+	fmt.Println("        Example: -exclude-names 'userService,RequestID,*Service'")
+	fmt.Println()
+	fmt.Println("  -priority-patterns string")
+	fmt.Println("        Comma-separated originals whose mappings are tried before all others")
+	fmt.Println("        Example: -priority-patterns 'request,response'")
+	fmt.Println()
+	fmt.Println("  -initialisms")
+	fmt.Println("        Flag initialisms written in mixed case, e.g. userId -> userID (default false)")
+	fmt.Println()
+	fmt.Println("  -hungarian")
+	fmt.Println("        Flag Hungarian notation such as strUserName or bIsValid and suggest")
+	fmt.Println("        the name without the type prefix (default false)")
+	fmt.Println()
+	fmt.Println("  -no-snake-case")
+	fmt.Println("        Flag snake_case identifiers such as user_name and suggest camelCase (default false)")
+	fmt.Println()
+	fmt.Println("  -interface-naming")
+	fmt.Println("        Flag single-method interfaces that are not named after their method")
+	fmt.Println("        plus -er, e.g. Read -> Reader (default false)")
+	fmt.Println()
+	fmt.Println("  -getter-naming")
+	fmt.Println("        Flag getters named GetXxx and suggest Xxx, unless the type already has")
+	fmt.Println("        a field or method named Xxx (default false)")
+	fmt.Println()
+	fmt.Println("  -error-naming")
+	fmt.Println("        Flag package level error variables not named ErrXxx/errXxx and local ones")
+	fmt.Println("        not named err or errXxx (default false)")
+	fmt.Println()
+	fmt.Println("  -bool-naming")
+	fmt.Println("        Flag bool variables, fields and functions whose names do not read as")
+	fmt.Println("        predicates, e.g. valid -> isValid, children -> hasChildren (default false)")
+	fmt.Println()
+	fmt.Println("  -bool-naming-kinds string")
+	fmt.Println("        Comma-separated kinds checked by -bool-naming: var, field, func.")
+	fmt.Println("        Example: -bool-naming-kinds 'var,func' exempts struct option fields")
+	fmt.Println()
+	fmt.Println("  -no-stutter")
+	fmt.Println("        Flag exported top-level names that repeat the package name, e.g.")
+	fmt.Println("        config.ConfigLoader -> config.Loader (default false)")
+	fmt.Println()
+	fmt.Println("  -stutter-unexported")
+	fmt.Println("        Also check unexported top-level names with -no-stutter (default false)")
+	fmt.Println()
+	fmt.Println("  -no-all-caps")
+	fmt.Println("        Flag constants such as MAX_RETRY_COUNT and suggest MaxRetryCount, keeping")
+	fmt.Println("        initialisms upper case (HTTP_TIMEOUT -> HTTPTimeout) (default false)")
+	fmt.Println()
+	fmt.Println("  -all-caps-vars")
+	fmt.Println("        Also check variables with -no-all-caps (default false)")
+	fmt.Println()
+	fmt.Println("  -synonym-consistency")
+	fmt.Println("        Group -check mappings by replacement and report identifiers using a synonym,")
+	fmt.Println("        e.g. conf for config, in packages that also use the canonical form (default false)")
+	fmt.Println()
+	fmt.Println("  -receiver-consistency")
+	fmt.Println("        Flag methods whose receiver name differs from the name used by most")
+	fmt.Println("        methods of the same type (default false)")
+	fmt.Println()
+	fmt.Println("  -max-length int")
+	fmt.Println("        Flag identifiers longer than this many characters; known abbreviations")
+	fmt.Println("        from -check are used to suggest a shorter name (default 0, disabled)")
+	fmt.Println()
 	fmt.Println("  -recursive")
-	fmt.Println("        Recursively scan directories (default false)")
+	fmt.Println("        Recursively scan directories (default true)")
+	fmt.Println()
+	fmt.Println("  -no-recursive")
+	fmt.Println("        Only scan the files directly in the directories given, as before directories")
+	fmt.Println("        were scanned recursively by default. Scripts that passed -recursive can drop")
+	fmt.Println("        it; scripts relying on the old default need -no-recursive (default false)")
+	fmt.Println()
+	fmt.Println("  -include-submodules")
+	fmt.Println("        Descend into directories containing their own go.mod when scanning recursively (default false)")
+	fmt.Println()
+	fmt.Println("  -modules string")
+	fmt.Println("        When scanning a go.work workspace root recursively, only scan these modules,")
+	fmt.Println("        given by module path, last path element or directory. Useful to shard CI")
+	fmt.Println()
+	fmt.Println("  -fallback-to-tokenizer")
+	fmt.Println("        When a file fails to parse, check its identifier tokens instead of skipping it.")
+	fmt.Println("        Results are prefixed with [partial] and may contain false positives (default false)")
+	fmt.Println()
+	fmt.Println("  -show-related")
+	fmt.Println("        Print the other occurrences of each flagged identifier, indented under its")
+	fmt.Println("        diagnostic, to see how widely a name is used before renaming it (default false)")
+	fmt.Println()
+	fmt.Println("  -max-related int")
+	fmt.Println("        Other occurrences attached to each diagnostic (default 0, meaning 10; -1 attaches none)")
+	fmt.Println()
+	fmt.Println("  -check-strings string")
+	fmt.Println("        Comma-separated call:arg pairs whose string literal argument is checked, split")
+	fmt.Println("        at underscores. The call is Func, pkg.Func, Type.Method or *.Method; arg is")
+	fmt.Println("        the zero-based argument index (default 0)")
+	fmt.Println("        Example: -check-strings 'slog.With:0,*.WithField:0'")
+	fmt.Println()
+	fmt.Println("  -check-comments")
+	fmt.Println("        Also report words in comments that a mapping would replace, e.g. \"the request")
+	fmt.Println("        object\". No fixes are offered; directives, URLs and code in backticks are")
+	fmt.Println("        skipped (default false)")
+	fmt.Println()
+	fmt.Println("  -check-filenames")
+	fmt.Println("        Also apply the mappings to the names of the analyzed files, split at")
+	fmt.Println("        underscores and camelCase, e.g. request_handler.go -> req_handler.go. Findings")
+	fmt.Println("        are reported at the package clause; files are renamed only by -fix, never over")
+	fmt.Println("        an existing file and not with -interactive (default false)")
+	fmt.Println()
+	fmt.Println("  -check-loop-vars")
+	fmt.Println("        Also check loop variables of one or two characters declared by for-loop")
+	fmt.Println("        init statements and range clauses, like i, j, k and v (default false)")
+	fmt.Println()
+	fmt.Println("  -only-kinds string")
+	fmt.Println("        Comma-separated declaration kinds to check: func, method, type, var, const,")
+	fmt.Println("        field, param, result. Unknown kinds are an error (default all)")
+	fmt.Println("        Example: -only-kinds 'func,method' leaves struct fields and locals alone")
+	fmt.Println()
+	fmt.Println("  -replace-in-test-table-names")
+	fmt.Println("        Also check test case names such as {name: \"processRequest\"} in table-driven")
+	fmt.Println("        tests. Test files must not be excluded: -exclude-files '*.pb.go' (default false)")
+	fmt.Println()
+	fmt.Println("  -trace-mappings")
+	fmt.Println("        Log one debug line to stderr per identifier and pattern tested, with")
+	fmt.Println("        identifier, pattern_tested, matched and position. Very verbose (default false)")
+	fmt.Println()
+	fmt.Println("  -dump-identifiers")
+	fmt.Println("        Print every identifier declared in the files as a JSON object per line, with")
+	fmt.Println("        its name, kind, exported flag, position and the words the mappings are")
+	fmt.Println("        matched against, instead of checking. Needs no mappings; excluded files are")
+	fmt.Println("        left out as when checking (default false)")
+	fmt.Println("        Example: gonamefix -dump-identifiers ./... | jq -r .name")
+	fmt.Println()
+	fmt.Println("  -why-excluded string")
+	fmt.Println("        Print which exclusion rule (and its reason) applies to a path, then exit")
+	fmt.Println()
+	fmt.Println("  -error-on-no-violations")
+	fmt.Println("        Smoke test mode: exit with code 2 if the analysis finds zero violations.")
+	fmt.Println("        Use it to verify that a new configuration actually catches something.")
+	fmt.Println()
+	fmt.Println("  -sample-violations int")
+	fmt.Println("        Show only N violations picked at random across all files. The totals,")
+	fmt.Println("        trend and exit code still reflect every violation (default 0, show all)")
+	fmt.Println()
+	fmt.Println("  -sample-seed int")
+	fmt.Println("        Seed for -sample-violations, printed in the sample header so a sample")
+	fmt.Println("        can be reproduced (default 0, random)")
+	fmt.Println()
+	fmt.Println("  -baseline string")
+	fmt.Println("        Do not report the findings recorded in this JSON baseline file, so that")
+	fmt.Println("        only new findings show up. Findings are matched by file, enclosing")
+	fmt.Println("        declaration, identifier and rule, not by line, so they survive edits")
+	fmt.Println("        elsewhere in the file")
+	fmt.Println()
+	fmt.Println("  -write-baseline string")
+	fmt.Println("        Record the current findings in this JSON baseline file, then exit")
+	fmt.Println()
+	fmt.Println("  -prune-baseline")
+	fmt.Println("        With -baseline, rewrite the baseline file without the entries of analyzed")
+	fmt.Println("        files that no longer match a finding, e.g. after they were fixed")
+	fmt.Println()
+	fmt.Println("  -trend-file string")
+	fmt.Println("        Append a summary of each run to this JSON history file and print")
+	fmt.Println("        the change from the last run and from the first recorded run")
+	fmt.Println("        Example: -trend-file .gonamefix-history.json")
+	fmt.Println()
+	fmt.Println("  -trend-keep-last int")
+	fmt.Println("        Keep only the last N runs in the trend file (default 0, keep all)")
+	fmt.Println()
+	fmt.Println("  -l, -list")
+	fmt.Println("        List the files with at least one finding, relative to the working directory,")
+	fmt.Println("        once each in sorted order and nothing else, like gofmt -l and grep -l. The")
+	fmt.Println("        exit code is 1 if any file is listed. With -fix -stdin, <standard input> is")
+	fmt.Println("        listed if fixes would change it, and the fixed source is not written")
+	fmt.Println("        (default false)")
+	fmt.Println()
+	fmt.Println("  -fix")
+	fmt.Println("        Rewrite the files with the suggested fixes applied and print how many")
+	fmt.Println("        identifiers were fixed. Renames reach every reference in the package:")
+	fmt.Println("        local names and unexported package-level names are renamed, exported")
+	fmt.Println("        names, fields and methods need a manual fix. Fixes overlapping another")
+	fmt.Println("        are not applied. With -l the changed files are listed (default false)")
+	fmt.Println()
+	fmt.Println("  -interactive")
+	fmt.Println("        With -fix, show each safe rename with the lines around it and ask whether")
+	fmt.Println("        to apply it: y applies it, n skips it, a applies it and the other renames")
+	fmt.Println("        of the file, q stops asking. The accepted renames are written at the end,")
+	fmt.Println("        one pass per file; renames overlapping an accepted one are not asked")
+	fmt.Println("        (default false)")
+	fmt.Println()
+	fmt.Println("  -interactive-ignore string")
+	fmt.Println("        File recording the renames declined with -interactive, by file, enclosing")
+	fmt.Println("        declaration, old and new name, so that later sessions do not ask again")
+	fmt.Println("        Example: -fix -interactive -interactive-ignore .gonamefix-ignore")
+	fmt.Println()
+	fmt.Println("  -no-format")
+	fmt.Println("        Fixed files are formatted like gofmt, since renames break the alignment of")
+	fmt.Println("        declaration blocks and composite literals; a formatting error means the")
+	fmt.Println("        fixes produced invalid Go and nothing is written. With -no-format the files")
+	fmt.Println("        are left as the fixes leave them, for another formatter (default false)")
+	fmt.Println()
+	fmt.Println("  -backup")
+	fmt.Println("        With -fix, write the content of each changed file to file.go.orig before")
+	fmt.Println("        changing it, with the same permissions. Files left unchanged get no backup.")
+	fmt.Println("        An existing backup stops the fix before any file is written (default false)")
+	fmt.Println()
+	fmt.Println("  -backup-suffix string")
+	fmt.Println("        Suffix appended to the file name of backups (default \".orig\")")
+	fmt.Println()
+	fmt.Println("  -force-backup")
+	fmt.Println("        With -backup, overwrite existing backups (default false)")
+	fmt.Println()
+	fmt.Println("  -restore-backups")
+	fmt.Println("        Move the backups of the files given, as found with -backup-suffix, back in")
+	fmt.Println("        place and print the restored files, then exit (default false)")
+	fmt.Println("        Example: -restore-backups ./...")
+	fmt.Println()
+	fmt.Println("  -dry-run")
+	fmt.Println("        List the renames -fix would apply instead of the findings, one per line")
+	fmt.Println("        sorted by file and line, e.g. handler.go:12:5 requestHandler -> reqHandler")
+	fmt.Println("        (rule request:req), followed by their count. No file is changed. Cannot")
+	fmt.Println("        be combined with -fix, -l or -diff (default false)")
+	fmt.Println()
+	fmt.Println("  -diff")
+	fmt.Println("        Print the fixes -fix would apply as a unified diff (--- a/file.go,")
+	fmt.Println("        +++ b/file.go) instead of the findings, without changing any file, in")
+	fmt.Println("        file name order. The exit code is 1 if the diff is not empty, like")
+	fmt.Println("        gofmt -d. Cannot be combined with -fix or -l (default false)")
+	fmt.Println()
+	fmt.Println("  -stdin")
+	fmt.Println("        With -fix, read one file from stdin and write it to stdout with the fixes")
+	fmt.Println("        applied, even when nothing changed, like gofmt. Diagnostics go to stderr.")
+	fmt.Println("        Input that does not parse is copied through and the exit code is 3.")
+	fmt.Println("        The single argument - does the same. Without -fix the file is analyzed")
+	fmt.Println("        and its findings reported like those of a file argument (default false)")
+	fmt.Println()
+	fmt.Println("  -stdin-filename string")
+	fmt.Println("        The name of the file read from stdin, used in positions and matched by")
+	fmt.Println("        -exclude-files and the other file exclusions. Nothing is read from disk,")
+	fmt.Println("        so unsaved editor buffers can be checked (default \"<standard input>\")")
+	fmt.Println()
+	fmt.Println("  -jobs int")
+	fmt.Println("        Number of files analyzed in parallel. The output is the same, in the same")
+	fmt.Println("        order, whatever the number (default the number of CPUs)")
+	fmt.Println()
+	fmt.Println("  -max-issues int")
+	fmt.Println("        Report only the first N findings, in file order, followed by a line")
+	fmt.Println("        \"…and more (limit reached)\", and exit 1 if there were more. Files stop")
+	fmt.Println("        being analyzed once N findings are found (default 0, report all)")
+	fmt.Println()
+	fmt.Println("  -summary")
+	fmt.Println("        After the run, print on stderr the number of files scanned and skipped by")
+	fmt.Println("        exclusion and the findings per mapping, e.g. request→req: 41 (default false)")
+	fmt.Println()
+	fmt.Println("  -watch")
+	fmt.Println("        Keep running after the first analysis: the files of the arguments are")
+	fmt.Println("        checked for changes twice a second and collected again every 5 seconds")
+	fmt.Println("        to find new ones; changed and new files are analyzed again and the")
+	fmt.Println("        findings of all files reprinted, replacing the previous ones on a")
+	fmt.Println("        terminal. Excluded files never trigger a run. Ctrl-C exits with the")
+	fmt.Println("        exit code of the last results (default false)")
+	fmt.Println()
+	fmt.Println("  -progress")
+	fmt.Println("        Report how many files have been analyzed on stderr: a status line with")
+	fmt.Println("        the current file on a terminal, a line every 10 seconds otherwise. Off")
+	fmt.Println("        with -format json, sarif and checkstyle (default false)")
+	fmt.Println()
+	fmt.Println("  -verbose")
+	fmt.Println("        Log on stderr what became of every file: the directories skipped, the")
+	fmt.Println("        files not built, the exclusion pattern matching a file, a parse error,")
+	fmt.Println("        or \"parsed, 3 findings\" (default false)")
+	fmt.Println()
+	fmt.Println("  -severity string")
+	fmt.Println("        Severity of the findings: error, warning or info. Text output labels")
+	fmt.Println("        warnings and infos (warning: ...), JSON has a severity field, SARIF a")
+	fmt.Println("        level (info is note) and checkstyle a severity attribute (default \"error\")")
+	fmt.Println()
+	fmt.Println("  -fail-severity string")
+	fmt.Println("        Exit 1 when a finding has at least this severity, in every mode; -l and")
+	fmt.Println("        -diff then fail only for such findings. The default fails on any finding")
+	fmt.Println("        Example: -severity warning -fail-severity error reports without failing")
+	fmt.Println("        (default \"info\")")
+	fmt.Println()
+	fmt.Println("  -exit-zero")
+	fmt.Println("        Report findings without failing, for report-only CI stages. Exit codes:")
+	fmt.Println("          0  clean: no failure, and no findings failing the run")
+	fmt.Println("          1  findings: any finding reported that meets -fail-severity, in every")
+	fmt.Println("             format, the files listed by -l, a non-empty -diff, or -max-issues")
+	fmt.Println("             reached. With -fix only the findings left for a manual fix count,")
+	fmt.Println("             and -fix -stdin formats without failing. Never with -exit-zero")
+	fmt.Println("          2  usage: an unknown or invalid flag, an invalid configuration,")
+	fmt.Println("             mapping file or baseline, no files or mappings given, or")
+	fmt.Println("             -error-on-no-violations finding nothing")
+	fmt.Println("          3  failure: a file could not be read, parsed or fixed, or an output")
+	fmt.Println("             could not be written; it wins over findings (default false)")
+	fmt.Println()
+	fmt.Println("  -relative-paths[=cwd|module]")
+	fmt.Println("        Print the file paths of findings, in every format, relative to the working")
+	fmt.Println("        directory (cwd, also the flag alone) or to the root of the module it is in,")
+	fmt.Println("        the nearest directory with a go.mod (module). Paths outside of it are")
+	fmt.Println("        printed as given. SARIF and file lists are relative to the working")
+	fmt.Println("        directory without the flag")
+	fmt.Println("        Example: -relative-paths=module -format json")
+	fmt.Println()
+	fmt.Println("  -format string")
+	fmt.Println("        Output format, with the same exit code in every format:")
+	fmt.Println("          text        one line per finding")
+	fmt.Println("          json        a JSON array of the findings with their position and span,")
+	fmt.Println("                      the flagged identifier, the suggestion and the rule ID")
+	fmt.Println("          sarif       a SARIF 2.1.0 log for code scanning, with a rule per mapping")
+	fmt.Println("                      and the suggested renames as fixes")
+	fmt.Println("          checkstyle  a checkstyle XML document with a file element for every")
+	fmt.Println("                      analyzed file, empty if it has no findings")
+	fmt.Println("          files       the paths of the files with findings, once each, sorted and")
+	fmt.Println("                      relative to the working directory")
+	fmt.Println("          edits       a JSON array of the fixes -fix would apply, without applying")
+	fmt.Println("                      them: per finding, every edit with the byte offsets and")
+	fmt.Println("                      line and column of the replaced range and the new text")
+	fmt.Println("          github-actions")
+	fmt.Println("                      workflow commands that annotate the findings on pull")
+	fmt.Println("                      requests; the default when GITHUB_ACTIONS is true, where")
+	fmt.Println("                      -format text prints the usual lines instead")
+	fmt.Println("          rdjson      a reviewdog DiagnosticResult, with the renames as suggestions")
+	fmt.Println("                      for reviewdog -f=rdjson")
+	fmt.Println("          csv         a header row and a row per finding, sorted by position: file,")
+	fmt.Println("                      line, column, identifier, suggestion, mapping and kind")
+	fmt.Println("          html        a self-contained page with the findings per mapping and per")
+	fmt.Println("                      file, quoting the flagged lines, to sort and filter offline")
+	fmt.Println("        Other output than the findings goes to stderr in every format but text.")
+	fmt.Println("        The top subcommand takes text or json (default \"text\")")
+	fmt.Println()
+	fmt.Println("  -stats string")
+	fmt.Println("        Write a JSON object to this file with, for every configured mapping, the")
+	fmt.Println("        number of findings, the number of files with findings and up to 5 example")
+	fmt.Println("        locations. Mappings that never fire are listed with a count of 0")
+	fmt.Println("        Example: -stats mapping-stats.json")
+	fmt.Println()
+	fmt.Println("  -output string")
+	fmt.Println("        Write the findings of -format to this file instead of stdout; other output")
+	fmt.Println("        still goes to stdout and stderr. Not for -l, -diff or -dry-run")
+	fmt.Println("        Example: -format csv -output naming.csv")
+	fmt.Println()
+	fmt.Println("  -color string")
+	fmt.Println("        Color the findings of the text format: the position, the flagged name and")
+	fmt.Println("        the suggested one. auto colors on a terminal unless NO_COLOR is set, always")
+	fmt.Println("        and never force it. Other formats are never colored (default \"auto\")")
+	fmt.Println("        Example: gonamefix -format files -print0 ./... | xargs -0 codemod")
+	fmt.Println()
+	fmt.Println("  -files-from string")
+	fmt.Println("        Read the files to analyze from a file, or from standard input if -, in")
+	fmt.Println("        addition to the arguments: one path per line, or NUL-separated as written")
+	fmt.Println("        by git ls-files -z and find -print0. The paths are excluded and analyzed")
+	fmt.Println("        like arguments; an empty list reports that no files were found and exits 0")
+	fmt.Println("        Example: git ls-files -z '*.go' | gonamefix -files-from -")
+	fmt.Println()
+	fmt.Println("  -z")
+	fmt.Println("        Split the list of -files-from at NUL bytes; a list containing a NUL is")
+	fmt.Println("        split there without it (default false)")
+	fmt.Println()
+	fmt.Println("  -print0")
+	fmt.Println("        Terminate the file names of -format files with NUL instead of newline, for")
+	fmt.Println("        xargs -0 and paths with spaces (default false)")
 	fmt.Println()
 	fmt.Println("  -help")
 	fmt.Println("        Show this help message")
 	fmt.Println()
+	fmt.Println("  -version")
+	fmt.Println("        Print the module version, the VCS revision of source builds and the Go")
+	fmt.Println("        version of the build, as a JSON object with -format json. Builds from a")
+	fmt.Println("        working tree report the version devel. SARIF reports carry the same")
+	fmt.Println("        version in their tool driver (default false)")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  # Check single file")
 	fmt.Println("  gonamefix -check 'request:req,response:res' file.go")
 	fmt.Println()
-	fmt.Println("  # Check directory (non-recursive)")
-	fmt.Println("  gonamefix -check 'request:req,response:res' ./cmd")
-	fmt.Println()
 	fmt.Println("  # Check directory recursively")
-	fmt.Println("  gonamefix -check 'request:req,response:res' -recursive ./")
+	fmt.Println("  gonamefix -check 'request:req,response:res' ./")
+	fmt.Println()
+	fmt.Println("  # Check directory (non-recursive)")
+	fmt.Println("  gonamefix -check 'request:req,response:res' -no-recursive ./cmd")
 	fmt.Println()
 	fmt.Println("  # Check multiple files")
 	fmt.Println("  gonamefix -check 'request:req,response:res' file1.go file2.go")
+	fmt.Println()
+	fmt.Println("  # Check the files matching a glob pattern")
+	fmt.Println("  gonamefix -check 'request:req,response:res' 'internal/**/handler*.go'")
+	fmt.Println()
+	fmt.Println("  # Find mappings that never match")
+	fmt.Println("  gonamefix coverage -check 'request:req,temporary:temp' ./...")
+	fmt.Println()
+	fmt.Println("  # Rank the most common findings to plan a cleanup")
+	fmt.Println("  gonamefix top -check 'request:req,response:res' ./...")
+	fmt.Println()
+	fmt.Println("  # List the files that need attention in CI")
+	fmt.Println("  gonamefix -check 'request:req' -l ./...")
+	fmt.Println()
+	fmt.Println("  # Review the renames before applying them")
+	fmt.Println("  gonamefix -check 'request:req' -diff ./...")
+	fmt.Println()
+	fmt.Println("  # Fix an editor buffer on save")
+	fmt.Println("  gonamefix -check 'request:req' -fix -stdin < file.go")
+	fmt.Println()
+	fmt.Println("  # Check an unsaved editor buffer")
+	fmt.Println("  gonamefix -check 'request:req' -stdin-filename pkg/handler.go - < buffer")
+	fmt.Println()
+	fmt.Println("  # Smoke test: verify the config catches a known violation")
+	fmt.Println("  gonamefix -check 'request:req' -error-on-no-violations testdata/intentionally_wrong.go")
 }
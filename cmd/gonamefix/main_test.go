@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestFindGoFilesSkipsNestedModules(t *testing.T) {
+	root := filepath.Join("testdata", "nested")
+
+	tests := []struct {
+		name              string
+		includeSubmodules bool
+		expected          []string
+	}{
+		{
+			name:              "default skips nested modules",
+			includeSubmodules: false,
+			expected: []string{
+				"main.go",
+				"pkg/pkg.go",
+			},
+		},
+		{
+			name:              "include submodules",
+			includeSubmodules: true,
+			expected: []string{
+				"examples/demo/demo.go",
+				"main.go",
+				"pkg/pkg.go",
+				"tools/tools.go",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := findGoFiles(root, tt.includeSubmodules, false, nil)
+			if err != nil {
+				t.Fatalf("findGoFiles(%q) returned error: %v", root, err)
+			}
+
+			var got []string
+			for _, file := range files {
+				rel, err := filepath.Rel(root, file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			sort.Strings(got)
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("findGoFiles(%q) = %v, want %v", root, got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("findGoFiles(%q) = %v, want %v", root, got, tt.expected)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestFindGoFilesStartingAtNestedModule(t *testing.T) {
+	// Starting inside a nested module analyzes that module even though its go.mod is present
+	root := filepath.Join("testdata", "nested", "tools")
+
+	files, err := findGoFiles(root, false, false, nil)
+	if err != nil {
+		t.Fatalf("findGoFiles(%q) returned error: %v", root, err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "tools.go" {
+		t.Errorf("findGoFiles(%q) = %v, want [tools.go]", root, files)
+	}
+}
+
+func TestScanDirRecursion(t *testing.T) {
+	root := filepath.Join("testdata", "nested")
+
+	tests := []struct {
+		name        string
+		recursive   bool
+		excludeDirs []string
+		expected    []string
+	}{
+		{
+			name:      "recursive by default",
+			recursive: true,
+			expected:  []string{"main.go", "pkg/pkg.go"},
+		},
+		{
+			name:     "no-recursive",
+			expected: []string{"main.go"},
+		},
+		{
+			name:        "exclude-dirs",
+			recursive:   true,
+			excludeDirs: []string{"node_modules", "pkg"},
+			expected:    []string{"main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := scanDir(root, tt.recursive, tt.excludeDirs)
+			if err != nil {
+				t.Fatalf("scanDir(%q) returned error: %v", root, err)
+			}
+			var got []string
+			for _, file := range files {
+				rel, err := filepath.Rel(root, file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			sort.Strings(got)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("scanDir(%q) = %v, want %v", root, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVendoredCode(t *testing.T) {
+	root := filepath.Join("testdata", "vendored")
+	vendored := filepath.Join(root, "vendor", "example.com", "lib", "lib.go")
+
+	for _, includeVendor := range []bool{false, true} {
+		t.Run(fmt.Sprintf("include-vendor=%t", includeVendor), func(t *testing.T) {
+			files, err := findGoFiles(root, false, includeVendor, nil)
+			if err != nil {
+				t.Fatalf("findGoFiles(%q) returned error: %v", root, err)
+			}
+			if found := slices.Contains(files, vendored); found != includeVendor {
+				t.Errorf("findGoFiles(%q) = %v, vendored file found = %t", root, files, found)
+			}
+
+			config := gonamefix.Config{
+				Check:         [][]string{{"request", "req"}},
+				ExcludeDirs:   []string{"vendor"},
+				IncludeVendor: includeVendor,
+			}
+			result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, vendored)
+			if err != nil {
+				t.Fatalf("analyzeFile(%q) returned error: %v", vendored, err)
+			}
+			want := 0
+			if includeVendor {
+				want = 1
+			}
+			if count := len(result.diagnostics); count != want {
+				t.Errorf("analyzeFile(%q) = %d violations, want %d", vendored, count, want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFileCountsViolations(t *testing.T) {
+	config := gonamefix.Config{
+		Check: [][]string{{"request", "req"}},
+	}
+	analyzer := gonamefix.NewAnalyzer(config)
+
+	tests := []struct {
+		filename string
+		expected int
+	}{
+		{filepath.Join("testdata", "nested", "main.go"), 1},
+		{filepath.Join("testdata", "nested", "pkg", "pkg.go"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			result, err := analyzeFile(analyzer, config, tt.filename)
+			if err != nil {
+				t.Fatalf("analyzeFile(%q) returned error: %v", tt.filename, err)
+			}
+			if count := len(result.diagnostics); count != tt.expected {
+				t.Errorf("analyzeFile(%q) = %d violations, want %d", tt.filename, count, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFileFallbackToTokenizer(t *testing.T) {
+	filename := filepath.Join("testdata", "broken.go")
+	config := gonamefix.Config{
+		Check: [][]string{{"request", "req"}},
+	}
+
+	// Without the fallback a parse error yields no diagnostics
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err == nil || len(result.diagnostics) != 0 {
+		t.Fatalf("analyzeFile(%q) = %d, %v; want 0 and a parse error", filename, len(result.diagnostics), err)
+	}
+
+	config.FallbackToTokenizer = true
+	result, err = analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err == nil {
+		t.Errorf("analyzeFile(%q) with fallback should still report the parse error", filename)
+	}
+	if len(result.diagnostics) != 2 {
+		t.Errorf("analyzeFile(%q) with fallback = %d violations, want 2", filename, len(result.diagnostics))
+	}
+}
+
+func TestAnalyzeFileSkipsExcludedBuildConstraints(t *testing.T) {
+	filename := filepath.Join("testdata", "ignored.go")
+	config := gonamefix.Config{
+		Check:                   [][]string{{"request", "req"}},
+		ExcludeBuildConstraints: []string{"ignore"},
+	}
+
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatalf("analyzeFile(%q) returned error: %v", filename, err)
+	}
+	if len(result.diagnostics) != 0 {
+		t.Errorf("analyzeFile(%q) = %d violations, want the file to be skipped", filename, len(result.diagnostics))
+	}
+
+	// Without the constraint list the file is analyzed
+	config.ExcludeBuildConstraints = nil
+	result, err = analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatalf("analyzeFile(%q) returned error: %v", filename, err)
+	}
+	if len(result.diagnostics) != 1 {
+		t.Errorf("analyzeFile(%q) = %d violations, want 1", filename, len(result.diagnostics))
+	}
+}
+
+func TestRunCoverageWarnsAboutUnusedPatterns(t *testing.T) {
+	config := gonamefix.Config{
+		Check: [][]string{{"request", "req"}, {"temporary", "temp"}},
+	}
+
+	var out, warnings strings.Builder
+	files := []string{filepath.Join("..", "..", "testdata", "src", "coverage", "coverage.go")}
+	if err := runCoverage(&out, &warnings, files, config); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(strings.Join(strings.Fields(out.String()), " "), "request→req 4") {
+		t.Errorf("coverage table missing request count:\n%s", out.String())
+	}
+	expected := "warning: pattern 'temporary→temp' matched 0 identifiers — consider removing\n"
+	if warnings.String() != expected {
+		t.Errorf("warnings = %q, want %q", warnings.String(), expected)
+	}
+}
+
+func TestAnalyzeFileDeterministic(t *testing.T) {
+	// Several mappings hit the same identifiers, and several rules as well
+	src := "package p\n\nvar requestUserConfig, userRequest string\n\nfunc handleRequest(userConfig, request string) (configRequest string) {\n\treturn userConfig + request\n}\n"
+	filename := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := gonamefix.Config{
+		Check:     [][]string{{"request", "req"}, {"user", "usr"}, {"config", "cfg"}, {"handle", "hdl"}},
+		MaxLength: 12,
+	}
+
+	var first string
+	for i := 0; i < 50; i++ {
+		// A new analyzer compiles its patterns again
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		text := newTextWriter(&out, false)
+		for _, v := range collectViolations([]fileResult{result}) {
+			text.diagnostic(v)
+		}
+		if i == 0 {
+			first = out.String()
+			if strings.Count(first, "\n") < 5 {
+				t.Fatalf("too few findings to compare orders:\n%s", first)
+			}
+			continue
+		}
+		if out.String() != first {
+			t.Fatalf("run %d printed\n%s\nwant\n%s", i+1, out.String(), first)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readMappingFile reads a plain-text mapping file: one old:new pair per
+// line, with blank lines and # comments ignored. Malformed lines and words
+// mapped twice are reported with their line number.
+func readMappingFile(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading mapping file: %w", err)
+	}
+	defer f.Close()
+
+	var pairs [][]string
+	lines := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		original, replacement, ok := strings.Cut(line, ":")
+		original, replacement = strings.TrimSpace(original), strings.TrimSpace(replacement)
+		if !ok || original == "" || strings.Contains(replacement, ":") {
+			return nil, fmt.Errorf("%s:%d: invalid mapping %q (expected 'old:new')", path, n, line)
+		}
+		key := strings.ToLower(original)
+		if first, ok := lines[key]; ok {
+			return nil, fmt.Errorf("%s:%d: %s is already mapped on line %d", path, n, original, first)
+		}
+		lines[key] = n
+		pairs = append(pairs, []string{original, replacement})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading mapping file: %w", err)
+	}
+	return pairs, nil
+}
+
+// mergeMappingFile adds the pairs of a mapping file to check, the mappings
+// of -check or the configuration file. A mapping of check wins over a pair
+// of the file with the same original.
+func mergeMappingFile(check, pairs [][]string) [][]string {
+	defined := make(map[string]bool)
+	for _, pair := range check {
+		if len(pair) > 0 {
+			defined[strings.ToLower(pair[0])] = true
+		}
+	}
+	var merged [][]string
+	for _, pair := range pairs {
+		if !defined[strings.ToLower(pair[0])] {
+			merged = append(merged, pair)
+		}
+	}
+	return append(merged, check...)
+}
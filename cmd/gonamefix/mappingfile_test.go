@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadMappingFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:    "pairs",
+			content: "# naming dictionary\n\nrequest:req\n  response : res  # trailing comment\nconfiguration:config\n",
+			want:    [][]string{{"request", "req"}, {"response", "res"}, {"configuration", "config"}},
+		},
+		{
+			name:    "missing colon",
+			content: "request:req\n\nresponse res\n",
+			wantErr: `:3: invalid mapping "response res"`,
+		},
+		{
+			name:    "empty original",
+			content: ":req\n",
+			wantErr: `:1: invalid mapping ":req"`,
+		},
+		{
+			name:    "two colons",
+			content: "request:req:r\n",
+			wantErr: `:1: invalid mapping "request:req:r"`,
+		},
+		{
+			name:    "mapped twice",
+			content: "request:req\nRequest:rq\n",
+			wantErr: ":2: Request is already mapped on line 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "naming.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readMappingFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("readMappingFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readMappingFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeMappingFile(t *testing.T) {
+	check := [][]string{{"Request", "rq"}}
+	pairs := [][]string{{"request", "req"}, {"response", "res"}}
+	want := [][]string{{"response", "res"}, {"Request", "rq"}}
+	if got := mergeMappingFile(check, pairs); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeMappingFile() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// codeBlock is a fenced Go code block of a Markdown file.
+type codeBlock struct {
+	// line is the line of the first line of code
+	line int
+	// indent is the indentation of the fence, removed from every line of code
+	indent int
+	src    []byte
+}
+
+// isMarkdown reports whether filename is a Markdown file.
+func isMarkdown(filename string) bool {
+	return strings.HasSuffix(filename, ".md")
+}
+
+// splitMarkdown separates the Markdown files from the Go files.
+func splitMarkdown(files []string) (goFiles, markdownFiles []string) {
+	for _, filename := range files {
+		if isMarkdown(filename) {
+			markdownFiles = append(markdownFiles, filename)
+		} else {
+			goFiles = append(goFiles, filename)
+		}
+	}
+	return goFiles, markdownFiles
+}
+
+// markdownCodeBlocks returns the fenced code blocks of src whose info string
+// starts with go or golang. Unterminated blocks end with the document.
+func markdownCodeBlocks(src []byte) []codeBlock {
+	var blocks []codeBlock
+	var block *codeBlock
+	// fence is the fence of the open block, in any language, and "" outside of blocks
+	fence := ""
+	fenceIndent := 0
+	for i, line := range strings.SplitAfter(string(src), "\n") {
+		text := strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimLeft(text, " ")
+		indent := len(text) - len(trimmed)
+
+		if fence != "" {
+			if indent < 4 && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" ") == "" {
+				if block != nil {
+					blocks = append(blocks, *block)
+				}
+				block, fence = nil, ""
+				continue
+			}
+			if block != nil {
+				// Lines of code lose the indentation of the fence, as far as they have it
+				block.src = append(block.src, line[min(fenceIndent, indent):]...)
+			}
+			continue
+		}
+
+		if indent > 3 {
+			continue
+		}
+		fence, fenceIndent = openingFence(trimmed), indent
+		if fence == "" {
+			continue
+		}
+		info := strings.Fields(trimmed[len(fence):])
+		if len(info) > 0 && (info[0] == "go" || info[0] == "golang") {
+			block = &codeBlock{line: i + 2, indent: indent}
+		}
+	}
+	if block != nil {
+		blocks = append(blocks, *block)
+	}
+	return blocks
+}
+
+// openingFence returns the fence text starts with, three or more backticks
+// or tildes, or "".
+func openingFence(text string) string {
+	if !strings.HasPrefix(text, "```") && !strings.HasPrefix(text, "~~~") {
+		return ""
+	}
+	n := 0
+	for n < len(text) && text[n] == text[0] {
+		n++
+	}
+	// The info string of a backtick fence may not contain backticks
+	if text[0] == '`' && strings.Contains(text[n:], "`") {
+		return ""
+	}
+	return text[:n]
+}
+
+// parseCodeBlock parses the code of a block as a file. A snippet without a
+// package clause gets a synthetic one, and a snippet of statements is also
+// wrapped in a function. It returns the number of lines added in front of
+// the code.
+func parseCodeBlock(fset *token.FileSet, filename string, src []byte) (*ast.File, int, error) {
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err == nil {
+		return file, 0, nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(src), []byte("package ")) {
+		return nil, 0, err
+	}
+
+	wrapped := append([]byte("package snippet\n"), src...)
+	if file, err := parser.ParseFile(fset, filename, wrapped, parser.ParseComments); err == nil {
+		return file, 1, nil
+	}
+	wrapped = append([]byte("package snippet\nfunc _() {\n"), src...)
+	wrapped = append(wrapped, "\n}\n"...)
+	if file, err := parser.ParseFile(fset, filename, wrapped, parser.ParseComments); err == nil {
+		return file, 2, nil
+	}
+	return nil, 0, err
+}
+
+// analyzeMarkdown analyzes the Go code blocks of a Markdown file. The
+// positions of the diagnostics refer to the Markdown file, and they carry no
+// fixes. Blocks that do not parse are skipped with a note to notes.
+func analyzeMarkdown(config gonamefix.Config, filename string, notes io.Writer) (fileResult, error) {
+	fset := token.NewFileSet()
+	result := fileResult{filename: filename, fset: fset}
+
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return result, err
+	}
+	result.src = src
+	markdown := fset.AddFile(filename, -1, len(src))
+	markdown.SetLinesForContent(src)
+
+	for _, block := range markdownCodeBlocks(src) {
+		blockFset := token.NewFileSet()
+		file, added, err := parseCodeBlock(blockFset, filename, block.src)
+		if err != nil {
+			fmt.Fprintf(notes, "note: skipping Go code block at %s:%d: %v\n", filename, block.line, err)
+			continue
+		}
+
+		diagnostics, err := gonamefix.AnalyzeFile(blockFset, file, config)
+		if err != nil {
+			return result, err
+		}
+
+		toMarkdown := func(pos token.Pos) token.Pos {
+			if !pos.IsValid() {
+				return token.NoPos
+			}
+			p := blockFset.Position(pos)
+			line := block.line + p.Line - 1 - added
+			if p.Line <= added || line > markdown.LineCount() {
+				return token.NoPos
+			}
+			offset := markdown.Offset(markdown.LineStart(line)) + block.indent + p.Column - 1
+			if offset > markdown.Size() {
+				return token.NoPos
+			}
+			return markdown.Pos(offset)
+		}
+		for _, d := range diagnostics {
+			mapped := analysis.Diagnostic{
+				Pos:      toMarkdown(d.Pos),
+				End:      toMarkdown(d.End),
+				Category: d.Category,
+				Message:  d.Message,
+				URL:      d.URL,
+			}
+			for _, related := range d.Related {
+				mapped.Related = append(mapped.Related, analysis.RelatedInformation{
+					Pos:     toMarkdown(related.Pos),
+					End:     toMarkdown(related.End),
+					Message: related.Message,
+				})
+			}
+			if mapped.Pos.IsValid() {
+				result.diagnostics = append(result.diagnostics, mapped)
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestAnalyzeMarkdown(t *testing.T) {
+	filename := filepath.Join("testdata", "markdown", "README.md")
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}, {"password", "pwd"}}}
+
+	var notes bytes.Buffer
+	result, err := analyzeMarkdown(config, filename, &notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range result.diagnostics {
+		pos := result.fset.Position(d.Pos)
+		if len(d.SuggestedFixes) > 0 {
+			t.Errorf("%s: diagnostic offers fixes", pos)
+		}
+		got = append(got, fmt.Sprintf("%d:%d: %s", pos.Line, pos.Column, d.Message))
+	}
+	want := []string{
+		"8:5: suggest replacing 'request' with 'req'",
+		"14:13: suggest replacing 'response' with 'res'",
+		"20:3: suggest replacing 'password' with 'pwd'",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("analyzeMarkdown() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if !strings.Contains(notes.String(), "skipping Go code block at "+filename+":25") {
+		t.Errorf("no note about the block that does not parse, got %q", notes.String())
+	}
+}
+
+func TestMarkdownCodeBlocks(t *testing.T) {
+	src := "```go\nvar a int\n````\n\n````go title=\"x\"\n```\nvar b int\n````\n\n```go\nvar c int\n"
+	blocks := markdownCodeBlocks([]byte(src))
+
+	var got []string
+	for _, block := range blocks {
+		got = append(got, fmt.Sprintf("%d:%q", block.line, block.src))
+	}
+	want := []string{`2:"var a int\n"`, `6:"` + "```" + `\nvar b int\n"`, `11:"var c int\n"`}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("markdownCodeBlocks() = %s, want %s", got, want)
+	}
+}
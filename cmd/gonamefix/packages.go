@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// isPackagePattern reports whether arg is a package pattern for the go
+// command, such as example.com/mod/... or std, rather than the path of a
+// file or directory. Paths, also of files that do not exist, keep being
+// read directly.
+func isPackagePattern(arg string) bool {
+	if strings.HasSuffix(arg, ".go") || isMarkdown(arg) {
+		return false
+	}
+	dir, _ := strings.CutSuffix(arg, "/...")
+	if dir == "" {
+		return false
+	}
+	_, err := os.Stat(dir)
+	return err != nil
+}
+
+// isPathLike reports whether arg is written as a relative or absolute path,
+// which the go command resolves as a directory rather than an import path.
+func isPathLike(arg string) bool {
+	return arg == "." || arg == ".." || strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../") ||
+		filepath.IsAbs(arg)
+}
+
+// loadPackageFiles returns the Go files of the packages matching patterns,
+// and of their tests, as the go command resolves them with the build tags
+// in tags. Files excluded by build constraints are added with allFiles, for
+// selectBuildFiles to label. Packages that fail to load are reported in the
+// error, together with the files of the others.
+func loadPackageFiles(patterns []string, tags string, allFiles bool) ([]string, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles, Tests: true}
+	if tags != "" {
+		cfg.BuildFlags = []string{"-tags=" + tags}
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	var errs []error
+	for _, pkg := range pkgs {
+		// The generated main package of a test binary has no files of its own
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		for _, e := range pkg.Errors {
+			errs = append(errs, e)
+		}
+		names := pkg.GoFiles
+		if allFiles {
+			names = append(names[:len(names):len(names)], pkg.IgnoredFiles...)
+		}
+		// The package and its test variant share their files
+		for _, name := range names {
+			if !seen[name] && strings.HasSuffix(name, ".go") {
+				seen[name] = true
+				files = append(files, relativePath(name))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIsPackagePattern(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want bool
+	}{
+		{"github.com/xbpk3t/gonamefix/...", true},
+		{"std", true},
+		{"./...", false},
+		{"testdata/nested", false},
+		{"testdata/nested/...", false},
+		{"testdata/nested/main.go", false},
+		// Paths that do not exist are reported when they are read
+		{"missing.go", false},
+		{"README.md", false},
+	}
+	for _, tt := range tests {
+		if got := isPackagePattern(tt.arg); got != tt.want {
+			t.Errorf("isPackagePattern(%q) = %t, want %t", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestLoadPackageFiles(t *testing.T) {
+	files, err := loadPackageFiles([]string{"github.com/xbpk3t/gonamefix/lsp"}, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Test files come from the test variant, once
+	lsp := filepath.Join("..", "..", "lsp")
+	want := []string{filepath.Join(lsp, "protocol.go"), filepath.Join(lsp, "server.go"), filepath.Join(lsp, "server_test.go")}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("loadPackageFiles() = %q, want %q", files, want)
+	}
+
+	if _, err := loadPackageFiles([]string{"github.com/xbpk3t/gonamefix/missing"}, "", false); err == nil {
+		t.Error("loadPackageFiles() of a missing package succeeded, want an error")
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pathBase is the value of -relative-paths: "" to print paths as given, cwd
+// or module. The flag alone means cwd.
+type pathBase string
+
+func (b *pathBase) String() string { return string(*b) }
+
+func (b *pathBase) Set(value string) error {
+	switch value {
+	case "true", "cwd":
+		*b = "cwd"
+	case "false":
+		*b = ""
+	case "module":
+		*b = "module"
+	default:
+		return fmt.Errorf("expected cwd or module, got %q", value)
+	}
+	return nil
+}
+
+// IsBoolFlag lets -relative-paths be given without a value.
+func (b *pathBase) IsBoolFlag() bool { return true }
+
+// pathBaseDir is the absolute directory paths are printed relative to, set
+// from -relative-paths by resolvePathBase, or "" to print them as given.
+var pathBaseDir string
+
+// resolvePathBase sets pathBaseDir for base: the working directory, or the
+// root of the module containing it, the nearest directory with a go.mod.
+func resolvePathBase(base pathBase) error {
+	if base == "" {
+		pathBaseDir = ""
+		return nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("-relative-paths: %w", err)
+	}
+	if base == "cwd" {
+		pathBaseDir = wd
+		return nil
+	}
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		if isModuleRoot(dir) {
+			pathBaseDir = dir
+			return nil
+		}
+		if filepath.Dir(dir) == dir {
+			return fmt.Errorf("-relative-paths=module: no go.mod in %s or its parents", wd)
+		}
+	}
+}
+
+// displayPath returns filename as findings print it: relative to the base of
+// -relative-paths when it lies below it, and as given otherwise.
+func displayPath(filename string) string {
+	if pathBaseDir == "" {
+		return filename
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filename
+	}
+	if rel, ok := relativeTo(pathBaseDir, abs, filepath.Separator); ok {
+		return rel
+	}
+	return filename
+}
+
+// reportPath is displayPath for the outputs that print paths relative to the
+// working directory without -relative-paths, such as SARIF and file lists.
+func reportPath(filename string) string {
+	if pathBaseDir == "" {
+		return relativePath(filename)
+	}
+	return displayPath(filename)
+}
+
+// relativeTo returns filename relative to base, both absolute and cleaned
+// paths using the separator sep, if filename lies below base. Paths using
+// backslashes are Windows paths and compared without regard to case.
+func relativeTo(base, filename string, sep byte) (string, bool) {
+	prefix := strings.TrimSuffix(base, string(sep)) + string(sep)
+	if len(filename) <= len(prefix) {
+		return "", false
+	}
+	head := filename[:len(prefix)]
+	if head != prefix && !(sep == '\\' && strings.EqualFold(head, prefix)) {
+		return "", false
+	}
+	return filename[len(prefix):], true
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRelativeTo(t *testing.T) {
+	tests := []struct {
+		base, filename string
+		sep            byte
+		want           string
+		ok             bool
+	}{
+		{"/repo", "/repo/pkg/a.go", '/', "pkg/a.go", true},
+		{"/repo/", "/repo/a.go", '/', "a.go", true},
+		{"/", "/repo/a.go", '/', "repo/a.go", true},
+		{"/repo", "/repository/a.go", '/', "", false},
+		{"/repo", "/other/a.go", '/', "", false},
+		{"/Repo", "/repo/a.go", '/', "", false},
+		{`C:\repo`, `C:\repo\pkg\a.go`, '\\', `pkg\a.go`, true},
+		{`C:\`, `C:\repo\a.go`, '\\', `repo\a.go`, true},
+		// Windows paths are compared without regard to case
+		{`C:\Repo`, `c:\repo\pkg\a.go`, '\\', `pkg\a.go`, true},
+		{`C:\repo`, `C:\repository\a.go`, '\\', "", false},
+		{`C:\repo`, `D:\repo\a.go`, '\\', "", false},
+	}
+	for _, tt := range tests {
+		got, ok := relativeTo(tt.base, tt.filename, tt.sep)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("relativeTo(%q, %q) = %q, %t, want %q, %t", tt.base, tt.filename, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPathBaseSet(t *testing.T) {
+	for value, want := range map[string]pathBase{"true": "cwd", "cwd": "cwd", "module": "module", "false": ""} {
+		var base pathBase
+		if err := base.Set(value); err != nil || base != want {
+			t.Errorf("Set(%q) = %q, %v, want %q", value, base, err, want)
+		}
+	}
+	var base pathBase
+	if err := base.Set("repo"); err == nil {
+		t.Error("Set(\"repo\") succeeded")
+	}
+}
+
+func TestDisplayPath(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "cmd", "tool")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	t.Cleanup(func() { pathBaseDir = "" })
+
+	inside := filepath.Join("..", "..", "pkg", "a.go")
+	outside := filepath.Join(filepath.Dir(root), "other", "b.go")
+	local := filepath.Join(sub, "main.go")
+	// Without a base, file lists and SARIF are relative to the working directory
+	tests := []struct {
+		base                      pathBase
+		inside, local             string
+		insideReport, localReport string
+	}{
+		{"", inside, local, inside, "main.go"},
+		{"cwd", inside, "main.go", inside, "main.go"},
+		{"module", filepath.Join("pkg", "a.go"), filepath.Join("cmd", "tool", "main.go"), filepath.Join("pkg", "a.go"), filepath.Join("cmd", "tool", "main.go")},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.base), func(t *testing.T) {
+			if err := resolvePathBase(tt.base); err != nil {
+				t.Fatal(err)
+			}
+			if got := displayPath(inside); got != tt.inside {
+				t.Errorf("displayPath(%q) = %q, want %q", inside, got, tt.inside)
+			}
+			if got := displayPath(local); got != tt.local {
+				t.Errorf("displayPath(%q) = %q, want %q", local, got, tt.local)
+			}
+			if got := displayPath(outside); got != outside {
+				t.Errorf("displayPath(%q) = %q, want it untouched", outside, got)
+			}
+			if got := reportPath(inside); got != tt.insideReport {
+				t.Errorf("reportPath(%q) = %q, want %q", inside, got, tt.insideReport)
+			}
+			if got := reportPath(local); got != tt.localReport {
+				t.Errorf("reportPath(%q) = %q, want %q", local, got, tt.localReport)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// plannedRename is the rename of an identifier by a fix.
+type plannedRename struct {
+	filename     string
+	line, column int
+	old, new     string
+	// rule is the category of the finding
+	rule string
+}
+
+// plannedRenames returns the renames of the flagged identifiers of
+// diagnostics, whose fixes apply to srcs: the text at each diagnostic and
+// the replacement its fix edits in. References renamed along are not listed.
+func plannedRenames(fset *token.FileSet, srcs map[*token.File][]byte, diagnostics []analysis.Diagnostic) []plannedRename {
+	var renames []plannedRename
+	for _, d := range diagnostics {
+		file := fset.File(d.Pos)
+		src, ok := srcs[file]
+		if !ok || !d.End.IsValid() || len(d.SuggestedFixes) == 0 {
+			continue
+		}
+		for _, edit := range d.SuggestedFixes[0].TextEdits {
+			if edit.Pos != d.Pos {
+				continue
+			}
+			pos := fset.Position(d.Pos)
+			renames = append(renames, plannedRename{
+				filename: pos.Filename,
+				line:     pos.Line,
+				column:   pos.Column,
+				old:      string(src[file.Offset(d.Pos):file.Offset(d.End)]),
+				new:      string(edit.NewText),
+				rule:     d.Category,
+			})
+			break
+		}
+	}
+	return renames
+}
+
+// sortRenames sorts renames by file, line and column, and drops the
+// duplicates of a rename at the same position.
+func sortRenames(renames []plannedRename) []plannedRename {
+	sort.SliceStable(renames, func(i, j int) bool {
+		a, b := renames[i], renames[j]
+		if a.filename != b.filename {
+			return a.filename < b.filename
+		}
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return a.column < b.column
+	})
+	var unique []plannedRename
+	for i, r := range renames {
+		if i == 0 || r != renames[i-1] {
+			unique = append(unique, r)
+		}
+	}
+	return unique
+}
+
+// ruleNames returns the names of the rules of config for -dry-run, such as
+// request:req for the rule gonamefix/request-req, by rule ID.
+func ruleNames(config gonamefix.Config) map[string]string {
+	names := make(map[string]string)
+	for _, rule := range config.MappingRules() {
+		names[rule.ID] = rule.Original + ":" + rule.Replacement
+	}
+	return names
+}
+
+// writeDryRun writes renames to out, one per line, followed by their count.
+// Rules without a name in names are printed by their ID.
+func writeDryRun(out io.Writer, renames []plannedRename, names map[string]string) {
+	files := 0
+	for i, r := range renames {
+		if i == 0 || r.filename != renames[i-1].filename {
+			files++
+		}
+		rule := r.rule
+		if name, ok := names[rule]; ok {
+			rule = name
+		}
+		fmt.Fprintf(out, "%s:%d:%d %s -> %s (rule %s)\n", reportPath(r.filename), r.line, r.column, r.old, r.new, rule)
+	}
+	fmt.Fprintf(out, "%s renames planned in %s files\n", formatCount(len(renames)), formatCount(files))
+}
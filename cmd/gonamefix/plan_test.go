@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.go": "package p\n\nfunc use(requestHandler func()) {\n\tresponse := 1\n\t_ = response\n}\n",
+		"a.go": "package p\n\nvar request string\n\n// Exported names need a manual fix\nvar Request string\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}}}
+	var results []fileResult
+	for _, name := range []string{"b.go", "a.go"} {
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	fixed, err := computeFixes(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	writeDryRun(&out, fixed.renames, ruleNames(config))
+	a, b := relativePath(filepath.Join(dir, "a.go")), relativePath(filepath.Join(dir, "b.go"))
+	want := a + ":3:5 request -> req (rule request:req)\n" +
+		b + ":3:10 requestHandler -> reqHandler (rule request:req)\n" +
+		b + ":4:2 response -> res (rule response:res)\n" +
+		"3 renames planned in 2 files\n"
+	if out.String() != want {
+		t.Errorf("writeDryRun() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestSortRenames(t *testing.T) {
+	renames := []plannedRename{
+		{filename: "b.go", line: 1, column: 1, old: "request", new: "req"},
+		{filename: "a.go", line: 2, column: 1, old: "request", new: "req"},
+		{filename: "a.go", line: 1, column: 5, old: "request", new: "req"},
+		{filename: "a.go", line: 2, column: 1, old: "request", new: "req"},
+	}
+	want := []plannedRename{renames[2], renames[1], renames[0]}
+	got := sortRenames(renames)
+	if len(got) != len(want) {
+		t.Fatalf("sortRenames() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sortRenames()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// progressRedraw bounds how often the status line of a terminal is redrawn
+	progressRedraw = 100 * time.Millisecond
+	// progressInterval is the time between the progress lines of a log
+	progressInterval = 10 * time.Second
+)
+
+// progress reports how many of the files of a run have been analyzed. On a
+// terminal it keeps a single status line up to date, otherwise it prints a
+// line now and then. A nil progress reports nothing. It is safe for
+// concurrent use.
+type progress struct {
+	mu    sync.Mutex
+	out   io.Writer
+	tty   bool
+	total int
+	done  int
+	// last is when the progress was last printed
+	last time.Time
+	now  func() time.Time
+}
+
+// newProgress returns the progress of analyzing total files, printed to out.
+func newProgress(out *os.File, total int) *progress {
+	return &progress{out: out, tty: isTerminal(out), total: total, now: time.Now}
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// fileDone records that filename has been analyzed.
+func (p *progress) fileDone(filename string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	now := p.now()
+	switch {
+	case p.tty && now.Sub(p.last) >= progressRedraw:
+		// \r and the erase-line sequence overwrite the previous status
+		fmt.Fprintf(p.out, "\r\033[Kanalyzed %d/%d files: %s", p.done, p.total, relativePath(filename))
+	case !p.tty && now.Sub(p.last) >= progressInterval:
+		fmt.Fprintf(p.out, "progress: analyzed %d/%d files\n", p.done, p.total)
+	default:
+		return
+	}
+	p.last = now
+}
+
+// finish ends the report, clearing the status line of a terminal so that
+// nothing printed afterwards runs into it.
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tty {
+		fmt.Fprint(p.out, "\r\033[K")
+		return
+	}
+	fmt.Fprintf(p.out, "progress: analyzed %d/%d files\n", p.done, p.total)
+}
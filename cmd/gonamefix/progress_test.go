@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	clock := time.Unix(0, 0)
+	advance := func(d time.Duration) { clock = clock.Add(d) }
+
+	t.Run("log", func(t *testing.T) {
+		var out bytes.Buffer
+		p := &progress{out: &out, total: 3, now: func() time.Time { return clock }}
+		advance(progressInterval)
+		p.fileDone("a.go")
+		p.fileDone("b.go")
+		advance(progressInterval)
+		p.fileDone("c.go")
+		p.finish()
+		want := "progress: analyzed 1/3 files\nprogress: analyzed 3/3 files\nprogress: analyzed 3/3 files\n"
+		if out.String() != want {
+			t.Errorf("progress = %q, want %q", out.String(), want)
+		}
+	})
+
+	t.Run("terminal", func(t *testing.T) {
+		var out bytes.Buffer
+		p := &progress{out: &out, tty: true, total: 2, now: func() time.Time { return clock }}
+		advance(progressRedraw)
+		p.fileDone("a.go")
+		// Too soon to redraw
+		p.fileDone("b.go")
+		p.finish()
+		want := "\r\033[Kanalyzed 1/2 files: a.go\r\033[K"
+		if out.String() != want {
+			t.Errorf("progress = %q, want %q", out.String(), want)
+		}
+	})
+
+	var none *progress
+	none.fileDone("a.go")
+	none.finish()
+}
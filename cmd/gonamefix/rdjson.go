@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// The Reviewdog Diagnostic Format objects written by -format rdjson, reduced
+// to the fields gonamefix fills in. Lines and columns start at 1; columns
+// count bytes, as token.Position does, and ranges end before their end
+// position.
+type (
+	rdjsonResult struct {
+		Source      rdjsonSource       `json:"source"`
+		Diagnostics []rdjsonDiagnostic `json:"diagnostics"`
+	}
+	rdjsonSource struct {
+		Name string `json:"name"`
+		URL  string `json:"url,omitempty"`
+	}
+	rdjsonDiagnostic struct {
+		Message     string             `json:"message"`
+		Location    rdjsonLocation     `json:"location"`
+		Severity    string             `json:"severity"`
+		Code        *rdjsonCode        `json:"code,omitempty"`
+		Suggestions []rdjsonSuggestion `json:"suggestions,omitempty"`
+	}
+	rdjsonLocation struct {
+		Path  string      `json:"path"`
+		Range rdjsonRange `json:"range"`
+	}
+	rdjsonRange struct {
+		Start rdjsonPosition  `json:"start"`
+		End   *rdjsonPosition `json:"end,omitempty"`
+	}
+	rdjsonPosition struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	}
+	rdjsonCode struct {
+		Value string `json:"value"`
+		URL   string `json:"url,omitempty"`
+	}
+	rdjsonSuggestion struct {
+		Range rdjsonRange `json:"range"`
+		Text  string      `json:"text"`
+	}
+)
+
+// newRDJSONResult describes violations as a reviewdog DiagnosticResult.
+// The edits of the safe fix of a finding in fixes are its suggestions, which
+// reviewdog posts as suggested changes. Suggestions have no path of their
+// own, so a fix renaming references in other files is not suggested.
+func newRDJSONResult(violations []violation, fixes map[findingKey]appliedFix) rdjsonResult {
+	result := rdjsonResult{
+		Source:      rdjsonSource{Name: "gonamefix", URL: toolURI},
+		Diagnostics: make([]rdjsonDiagnostic, 0, len(violations)),
+	}
+	for _, v := range violations {
+		d := v.diagnostic
+		filename := v.fset.Position(d.Pos).Filename
+		diagnostic := rdjsonDiagnostic{
+			Message:  d.Message,
+			Location: rdjsonLocation{Path: filepath.ToSlash(reportPath(filename)), Range: rdjsonSpan(v.fset, d.Pos, d.End)},
+			Severity: strings.ToUpper(v.severity),
+		}
+		if d.Category != "" {
+			diagnostic.Code = &rdjsonCode{Value: d.Category, URL: d.URL}
+		}
+		if fix, ok := fixes[findingKeyOf(v.fset, d)]; ok {
+			diagnostic.Suggestions = rdjsonSuggestions(fix)
+		}
+		result.Diagnostics = append(result.Diagnostics, diagnostic)
+	}
+	return result
+}
+
+// rdjsonSuggestions returns the edits of fix as suggestions, or none if it
+// edits another file than the one of its finding.
+func rdjsonSuggestions(fix appliedFix) []rdjsonSuggestion {
+	filename := fix.fset.Position(fix.diagnostic.Pos).Filename
+	var suggestions []rdjsonSuggestion
+	for _, edit := range fix.diagnostic.SuggestedFixes[0].TextEdits {
+		if fix.fset.Position(edit.Pos).Filename != filename {
+			return nil
+		}
+		suggestions = append(suggestions, rdjsonSuggestion{
+			Range: rdjsonSpan(fix.fset, edit.Pos, edit.End),
+			Text:  string(edit.NewText),
+		})
+	}
+	return suggestions
+}
+
+// rdjsonSpan returns the range from pos to end, or of pos alone without end.
+func rdjsonSpan(fset *token.FileSet, pos, end token.Pos) rdjsonRange {
+	start := fset.Position(pos)
+	span := rdjsonRange{Start: rdjsonPosition{Line: start.Line, Column: start.Column}}
+	if end.IsValid() {
+		stop := fset.Position(end)
+		span.End = &rdjsonPosition{Line: stop.Line, Column: stop.Column}
+	}
+	return span
+}
+
+// writeRDJSON writes violations to out as a reviewdog DiagnosticResult, with
+// the safe fixes in fixes as suggestions.
+func writeRDJSON(out io.Writer, violations []violation, fixes map[findingKey]appliedFix) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newRDJSONResult(violations, fixes))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestWriteRDJSON(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	filename := filepath.Join("testdata", "rdjson", "rename.go")
+	result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	fixed, err := computeFixes([]fileResult{result})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeRDJSON(&got, collectViolations([]fileResult{result}), fixesByFinding(fixed)); err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "rdjson", "rename.rdjson")
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("rdjson result differs from %s:\n%s", golden, got.String())
+	}
+	validateRDJSON(t, want)
+}
+
+// validateRDJSON checks data against the constraints of the Reviewdog
+// Diagnostic Format schema for a DiagnosticResult: the required fields, the
+// severity values and the positions starting at line and column 1, with
+// ranges ending after they start.
+func validateRDJSON(t *testing.T, data []byte) {
+	t.Helper()
+	type position struct {
+		Line   *int `json:"line"`
+		Column *int `json:"column"`
+	}
+	type span struct {
+		Start *position `json:"start"`
+		End   *position `json:"end"`
+	}
+	var result struct {
+		Source *struct {
+			Name string `json:"name"`
+		} `json:"source"`
+		Diagnostics []struct {
+			Message  *string `json:"message"`
+			Location *struct {
+				Path  string `json:"path"`
+				Range *span  `json:"range"`
+			} `json:"location"`
+			Severity    string `json:"severity"`
+			Suggestions []struct {
+				Range *span   `json:"range"`
+				Text  *string `json:"text"`
+			} `json:"suggestions"`
+		} `json:"diagnostics"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&result); err != nil {
+		t.Fatalf("invalid rdjson: %v", err)
+	}
+
+	checkSpan := func(what string, s *span) {
+		if s == nil || s.Start == nil || s.Start.Line == nil {
+			t.Errorf("%s has no start line", what)
+			return
+		}
+		start := *s.Start
+		if *start.Line < 1 || start.Column != nil && *start.Column < 1 {
+			t.Errorf("%s starts at %d:%v, want line and column from 1", what, *start.Line, start.Column)
+		}
+		if s.End != nil && (s.End.Line == nil || s.End.Column == nil || start.Column == nil ||
+			*s.End.Line < *start.Line || *s.End.Line == *start.Line && *s.End.Column <= *start.Column) {
+			t.Errorf("%s ends before it starts", what)
+		}
+	}
+	if result.Source == nil || result.Source.Name == "" {
+		t.Error("result has no source name")
+	}
+	if len(result.Diagnostics) == 0 {
+		t.Fatal("result has no diagnostics")
+	}
+	for _, d := range result.Diagnostics {
+		if d.Message == nil || d.Location == nil || d.Location.Path == "" {
+			t.Errorf("diagnostic %+v lacks a message or a location", d)
+			continue
+		}
+		if !slices.Contains([]string{"", "UNKNOWN_SEVERITY", "ERROR", "WARNING", "INFO"}, d.Severity) {
+			t.Errorf("diagnostic has severity %q", d.Severity)
+		}
+		checkSpan("diagnostic "+*d.Message, d.Location.Range)
+		for _, s := range d.Suggestions {
+			if s.Text == nil {
+				t.Errorf("suggestion of %s has no text", *d.Message)
+			}
+			checkSpan("suggestion of "+*d.Message, s.Range)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xbpk3t/gonamefix"
+	"github.com/xbpk3t/gonamefix/internal/yaml"
+)
+
+// remoteTimeout bounds the download of a shared mapping file.
+const remoteTimeout = 30 * time.Second
+
+// remoteMappings is a shared mapping file: the mapping sections of a
+// configuration file.
+type remoteMappings struct {
+	Check    [][]string          `mapstructure:"check"`
+	Mappings []gonamefix.Mapping `mapstructure:"mappings"`
+}
+
+// cachedResponse is a downloaded mapping file as stored in the cache.
+type cachedResponse struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag,omitempty"`
+	Body []byte `json:"body"`
+}
+
+// remoteFetcher downloads mapping files, revalidating cached copies by their
+// ETag and falling back to them when the server cannot be reached.
+type remoteFetcher struct {
+	client   *http.Client
+	cacheDir string
+
+	// refresh downloads the file even if the cached copy is current
+	refresh bool
+	// requireRemote fails instead of falling back to the cache
+	requireRemote bool
+	// warn receives the warning printed when falling back to the cache
+	warn io.Writer
+}
+
+// newRemoteFetcher returns a fetcher caching in the user cache directory.
+func newRemoteFetcher(refresh, requireRemote bool) (*remoteFetcher, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("no cache directory for -check-url: %w", err)
+	}
+	return &remoteFetcher{
+		client:        &http.Client{Timeout: remoteTimeout},
+		cacheDir:      filepath.Join(dir, "gonamefix", "remote"),
+		refresh:       refresh,
+		requireRemote: requireRemote,
+		warn:          os.Stderr,
+	}, nil
+}
+
+// fetch returns the body of the file at rawURL, which must be an https URL.
+func (f *remoteFetcher) fetch(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid check-url %q: only https URLs are accepted", rawURL)
+	}
+
+	cached, cacheErr := f.readCache(rawURL)
+	body, err := f.download(rawURL, cached)
+	if err == nil {
+		return body, nil
+	}
+	if f.requireRemote || cacheErr != nil {
+		return nil, fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	fmt.Fprintf(f.warn, "warning: fetching %s: %v; using the cached copy\n", rawURL, err)
+	return cached.Body, nil
+}
+
+// download requests rawURL, conditionally on the ETag of cached unless
+// refreshing, and caches a new response.
+func (f *remoteFetcher) download(rawURL string, cached *cachedResponse) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && cached.ETag != "" && !f.refresh {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && cached != nil:
+		return cached.Body, nil
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.writeCache(cachedResponse{URL: rawURL, ETag: res.Header.Get("ETag"), Body: body}); err != nil {
+		fmt.Fprintf(f.warn, "warning: caching %s: %v\n", rawURL, err)
+	}
+	return body, nil
+}
+
+// cachePath returns the cache file of rawURL.
+func (f *remoteFetcher) cachePath(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(f.cacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (f *remoteFetcher) readCache(rawURL string) (*cachedResponse, error) {
+	data, err := os.ReadFile(f.cachePath(rawURL))
+	if err != nil {
+		return nil, err
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	if cached.URL != rawURL {
+		return nil, errors.New("cache entry is for a different URL")
+	}
+	return &cached, nil
+}
+
+func (f *remoteFetcher) writeCache(cached cachedResponse) error {
+	if err := os.MkdirAll(f.cacheDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	// Write through a temporary file so that concurrent runs never read a partial entry
+	tmp, err := os.CreateTemp(f.cacheDir, "download-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.cachePath(cached.URL))
+}
+
+// parseRemoteMappings decodes and validates a shared mapping file.
+func parseRemoteMappings(data []byte) (remoteMappings, error) {
+	var remote remoteMappings
+	if err := yaml.Unmarshal(data, &remote); err != nil {
+		return remote, err
+	}
+	fragment := gonamefix.Config{Check: remote.Check, Mappings: remote.Mappings}
+	if err := fragment.Validate(); err != nil {
+		return remote, err
+	}
+	return remote, nil
+}
+
+// mergeRemoteMappings adds the remote mappings to config beneath its own: a
+// remote mapping of a word the configuration already maps is dropped.
+func mergeRemoteMappings(config *gonamefix.Config, remote remoteMappings) {
+	local := make(map[string]bool)
+	for _, pair := range config.MappingPairs() {
+		local[strings.ToLower(pair[0])] = true
+	}
+
+	var check [][]string
+	for _, pair := range remote.Check {
+		if !local[strings.ToLower(pair[0])] {
+			check = append(check, pair)
+		}
+	}
+	var mappings []gonamefix.Mapping
+	for _, mapping := range remote.Mappings {
+		if !local[strings.ToLower(mapping.Original)] {
+			mappings = append(mappings, mapping)
+		}
+	}
+
+	config.Check = append(check, config.Check...)
+	config.Mappings = append(mappings, config.Mappings...)
+}
+
+// loadRemoteMappings fetches the mapping file at rawURL and merges it into config.
+func loadRemoteMappings(config *gonamefix.Config, fetcher *remoteFetcher, rawURL string) error {
+	data, err := fetcher.fetch(rawURL)
+	if err != nil {
+		return err
+	}
+	remote, err := parseRemoteMappings(data)
+	if err != nil {
+		return fmt.Errorf("invalid mapping file %s: %w", rawURL, err)
+	}
+	mergeRemoteMappings(config, remote)
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+const sharedMappings = `# shared naming rules
+check:
+  - [request, req]
+  - [configuration, cfg]
+mappings:
+  - original: response
+    replacement: res
+    kinds: [var, param]
+`
+
+func newTestFetcher(server *httptest.Server, cacheDir string) *remoteFetcher {
+	return &remoteFetcher{client: server.Client(), cacheDir: cacheDir, warn: &bytes.Buffer{}}
+}
+
+func TestRemoteFetchCaching(t *testing.T) {
+	var requests, downloads int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Write([]byte(sharedMappings))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	fetcher := newTestFetcher(server, cacheDir)
+	for i := 0; i < 2; i++ {
+		body, err := fetcher.fetch(server.URL + "/naming.yml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != sharedMappings {
+			t.Fatalf("fetch() = %q, want the served file", body)
+		}
+	}
+	if requests != 2 || downloads != 1 {
+		t.Errorf("got %d requests and %d downloads, want the second request revalidated by ETag", requests, downloads)
+	}
+
+	fetcher.refresh = true
+	if _, err := fetcher.fetch(server.URL + "/naming.yml"); err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 2 {
+		t.Errorf("-refresh did not download the file again")
+	}
+
+	// Once the server is gone the cached copy is used, with a warning
+	server.Close()
+	fetcher.refresh = false
+	body, err := fetcher.fetch(server.URL + "/naming.yml")
+	if err != nil || string(body) != sharedMappings {
+		t.Errorf("fetch() without network = %q, %v; want the cached copy", body, err)
+	}
+	if warning := fetcher.warn.(*bytes.Buffer).String(); !strings.Contains(warning, "using the cached copy") {
+		t.Errorf("no warning about the cached copy, got %q", warning)
+	}
+
+	fetcher.requireRemote = true
+	if _, err := fetcher.fetch(server.URL + "/naming.yml"); err == nil {
+		t.Error("fetch() with requireRemote succeeded without network")
+	}
+	if _, err := fetcher.fetch(server.URL + "/other.yml"); err == nil {
+		t.Error("fetch() succeeded without network or cache")
+	}
+}
+
+func TestRemoteFetchHTTPSOnly(t *testing.T) {
+	fetcher := &remoteFetcher{cacheDir: t.TempDir()}
+	for _, rawURL := range []string{"http://example.com/naming.yml", "file:///etc/naming.yml", "naming.yml"} {
+		if _, err := fetcher.fetch(rawURL); err == nil || !strings.Contains(err.Error(), "only https") {
+			t.Errorf("fetch(%q) = %v, want an https-only error", rawURL, err)
+		}
+	}
+}
+
+func TestMergeRemoteMappings(t *testing.T) {
+	remote, err := parseRemoteMappings([]byte(sharedMappings))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := gonamefix.Config{Check: [][]string{{"Configuration", "config"}}}
+	mergeRemoteMappings(&config, remote)
+
+	want := [][]string{{"request", "req"}, {"Configuration", "config"}, {"response", "res"}}
+	if got := config.MappingPairs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MappingPairs() = %v, want %v", got, want)
+	}
+	if kinds := config.Mappings[0].Kinds; !reflect.DeepEqual(kinds, []string{"var", "param"}) {
+		t.Errorf("remote mapping kinds = %v", kinds)
+	}
+}
+
+func TestParseRemoteMappingsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"mappings:\n  - original: request\n    kinds: [package]\n": "unknown kind",
+		"check:\n  - [request]\n":                                  "",
+		"exclude-files: ['*.go']\n":                                `unknown key "exclude-files"`,
+	}
+	for src, want := range tests {
+		_, err := parseRemoteMappings([]byte(src))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseRemoteMappings(%q) = %v, want an error containing %q", src, err, want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// renameIndex indexes the identifiers of files, one or all files of a
+// package, to extend suggested renames to references. Without type
+// information the suggested fixes rename the declaration only.
+type renameIndex struct {
+	files []*ast.File
+	// complete is set when files are all files of their package, so renames
+	// of package-level names reach every reference
+	complete bool
+
+	idents map[token.Pos]*ast.Ident
+	refs   map[*ast.Object][]*ast.Ident
+	fields map[*ast.Ident]bool
+	// keys holds the keys of composite literal elements, which name struct
+	// fields as well as variables of map and slice literals
+	keys  map[*ast.Ident]bool
+	funcs []ast.Node
+	// names holds the name of every identifier in files
+	names map[string]bool
+	// unresolved holds by name the identifiers that do not resolve within
+	// their file: references to package-level names of other files of the
+	// package and to predeclared names
+	unresolved map[string][]*ast.Ident
+}
+
+func newRenameIndex(files []*ast.File, complete bool) *renameIndex {
+	x := &renameIndex{
+		files:      files,
+		complete:   complete,
+		idents:     make(map[token.Pos]*ast.Ident),
+		refs:       make(map[*ast.Object][]*ast.Ident),
+		fields:     make(map[*ast.Ident]bool),
+		keys:       make(map[*ast.Ident]bool),
+		names:      make(map[string]bool),
+		unresolved: make(map[string][]*ast.Ident),
+	}
+	for _, file := range files {
+		for _, ident := range file.Unresolved {
+			x.unresolved[ident.Name] = append(x.unresolved[ident.Name], ident)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.Ident:
+				x.idents[n.Pos()] = n
+				x.names[n.Name] = true
+				//nolint:staticcheck // ast.Object is the only resolution available without type information
+				if n.Obj != nil {
+					x.refs[n.Obj] = append(x.refs[n.Obj], n)
+				}
+			case *ast.StructType:
+				for _, field := range n.Fields.List {
+					for _, name := range field.Names {
+						x.fields[name] = true
+					}
+				}
+			case *ast.CompositeLit:
+				for _, elt := range n.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						if key, ok := kv.Key.(*ast.Ident); ok {
+							x.keys[key] = true
+						}
+					}
+				}
+			case *ast.FuncDecl, *ast.FuncLit:
+				x.funcs = append(x.funcs, n)
+			}
+			return true
+		})
+	}
+	return x
+}
+
+// enclosing returns the innermost function containing pos.
+func (x *renameIndex) enclosing(pos token.Pos) ast.Node {
+	var inner ast.Node
+	for _, fn := range x.funcs {
+		if fn.Pos() <= pos && pos < fn.End() && (inner == nil || fn.Pos() >= inner.Pos()) {
+			inner = fn
+		}
+	}
+	return inner
+}
+
+// fileOf returns the file containing pos.
+func (x *renameIndex) fileOf(pos token.Pos) *ast.File {
+	for _, file := range x.files {
+		if file.FileStart <= pos && pos <= file.FileEnd {
+			return file
+		}
+	}
+	return nil
+}
+
+// rename returns the edits renaming every reference to the object edit
+// renames, or false if that is not safe.
+func (x *renameIndex) rename(edit analysis.TextEdit) ([]analysis.TextEdit, bool) {
+	ident := x.idents[edit.Pos]
+	if ident == nil || edit.End != ident.End() {
+		return nil, false
+	}
+	//nolint:staticcheck // ast.Object is the only resolution available without type information
+	obj := ident.Obj
+	file := x.fileOf(ident.Pos())
+	if obj == nil || file == nil {
+		return nil, false
+	}
+	if file.Scope.Lookup(obj.Name) == obj {
+		return x.renamePackageLevel(obj, edit.NewText)
+	}
+	if obj.Kind != ast.Var && obj.Kind != ast.Con {
+		return nil, false
+	}
+	decl, ok := obj.Decl.(ast.Node)
+	if !ok {
+		return nil, false
+	}
+	fn := x.enclosing(decl.Pos())
+	if fn == nil {
+		return nil, false
+	}
+
+	newName := string(edit.NewText)
+	for pos, other := range x.idents {
+		if fn.Pos() <= pos && pos < fn.End() && other.Name == newName {
+			return nil, false
+		}
+	}
+	return x.referenceEdits(x.refs[obj], edit.NewText)
+}
+
+// renamePackageLevel renames a package-level name, which is only safe when
+// all files of the package are known and the name is not exported. The new
+// name may not be used anywhere in the package, so that no reference is
+// captured by another declaration.
+func (x *renameIndex) renamePackageLevel(obj *ast.Object, newText []byte) ([]analysis.TextEdit, bool) {
+	if !x.complete || ast.IsExported(obj.Name) || x.names[string(newText)] {
+		return nil, false
+	}
+	if obj.Kind != ast.Var && obj.Kind != ast.Con && obj.Kind != ast.Typ && obj.Kind != ast.Fun {
+		return nil, false
+	}
+	refs := append(append([]*ast.Ident(nil), x.refs[obj]...), x.unresolved[obj.Name]...)
+	return x.referenceEdits(refs, newText)
+}
+
+// referenceEdits returns the edits renaming refs, failing for struct fields,
+// whose references cannot be found without type information. It also fails
+// for composite literal keys: the parser binds the key of T{request: request}
+// to the variable, but without types it cannot tell a field name from a map
+// key.
+func (x *renameIndex) referenceEdits(refs []*ast.Ident, newText []byte) ([]analysis.TextEdit, bool) {
+	edits := make([]analysis.TextEdit, 0, len(refs))
+	for _, ref := range refs {
+		if x.fields[ref] || x.keys[ref] {
+			return nil, false
+		}
+		edits = append(edits, analysis.TextEdit{Pos: ref.Pos(), End: ref.End(), NewText: newText})
+	}
+	return edits, true
+}
+
+// safeFixes returns diagnostics with only the fixes that are safe to apply
+// to the files of index: renames of local variables and constants,
+// parameters and results, and, when index holds a complete package, of
+// unexported package-level names, extended to every reference. Renames of
+// exported names, fields and methods could break other packages or files and
+// are dropped, as are renames to a name already in use.
+func safeFixes(index *renameIndex, diagnostics []analysis.Diagnostic) []analysis.Diagnostic {
+	safe := make([]analysis.Diagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		var fixes []analysis.SuggestedFix
+		if len(d.SuggestedFixes) > 0 {
+			fix := d.SuggestedFixes[0]
+			var edits []analysis.TextEdit
+			ok := true
+			for _, edit := range fix.TextEdits {
+				renames, renameOK := index.rename(edit)
+				if !renameOK {
+					ok = false
+					break
+				}
+				edits = append(edits, renames...)
+			}
+			if ok && len(edits) > 0 {
+				fixes = []analysis.SuggestedFix{{Message: fix.Message, TextEdits: dedupEdits(edits)}}
+			}
+		}
+		d.SuggestedFixes = fixes
+		safe = append(safe, d)
+	}
+	return safe
+}
+
+// dedupEdits drops repeated edits of the same position, which arise when a
+// fix already covered some references.
+func dedupEdits(edits []analysis.TextEdit) []analysis.TextEdit {
+	seen := make(map[token.Pos]bool, len(edits))
+	unique := edits[:0]
+	for _, edit := range edits {
+		if !seen[edit.Pos] {
+			seen[edit.Pos] = true
+			unique = append(unique, edit)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// renamedFile is a file renamed by -fix with -check-filenames.
+type renamedFile struct {
+	from, to string
+}
+
+// renameFiles renames the files of results that have a file name finding to
+// the name the mappings of config suggest. It runs after the fixes of their
+// content were written. A file is never renamed over an existing one; those
+// are reported in the error after the others are renamed.
+func renameFiles(results []fileResult, config gonamefix.Config) ([]renamedFile, error) {
+	var renamed []renamedFile
+	var errs []error
+	for _, result := range results {
+		if !hasFileNameFinding(result) {
+			continue
+		}
+		to, ok := gonamefix.SuggestFileName(result.filename, config)
+		if !ok {
+			continue
+		}
+		if _, err := os.Lstat(to); err == nil {
+			errs = append(errs, fmt.Errorf("not renaming %s to %s, which exists", result.filename, to))
+			continue
+		}
+		if err := os.Rename(result.filename, to); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		renamed = append(renamed, renamedFile{from: result.filename, to: to})
+	}
+	return renamed, errors.Join(errs...)
+}
+
+// hasFileNameFinding reports whether result has a finding of
+// -check-filenames.
+func hasFileNameFinding(result fileResult) bool {
+	for _, d := range result.diagnostics {
+		if gonamefix.IsFileNameFinding(d) {
+			return true
+		}
+	}
+	return false
+}
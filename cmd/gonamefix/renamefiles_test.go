@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestRenameFiles(t *testing.T) {
+	dir := t.TempDir()
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}, CheckFilenames: true}
+	write := func(name string) string {
+		t.Helper()
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte("package p\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	var results []fileResult
+	for _, name := range []string{"request_handler.go", "request_taken.go", "server.go"} {
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, write(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	// Without -check-filenames findings, a file keeps its name
+	write("req_taken.go")
+	noFinding := fileResult{filename: write("request_plain.go")}
+
+	renamed, err := renameFiles(append(results, noFinding), config)
+	if err == nil || !strings.Contains(err.Error(), "req_taken.go, which exists") {
+		t.Errorf("renameFiles() error = %v, want req_taken.go reported as existing", err)
+	}
+	if len(renamed) != 1 || renamed[0].to != filepath.Join(dir, "req_handler.go") {
+		t.Fatalf("renameFiles() = %v, want request_handler.go renamed", renamed)
+	}
+	for name, want := range map[string]bool{
+		"req_handler.go": true, "request_handler.go": false,
+		"request_taken.go": true, "server.go": true, "request_plain.go": true,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("%s exists: %t, want %t", name, err == nil, want)
+		}
+	}
+}
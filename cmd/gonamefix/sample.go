@@ -0,0 +1,65 @@
+package main
+
+import (
+	"go/token"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// violation is a diagnostic together with the file set it was reported for.
+type violation struct {
+	fset       *token.FileSet
+	diagnostic analysis.Diagnostic
+	constraint string
+	// severity is error, warning or info
+	severity string
+}
+
+// collectViolations flattens the diagnostics of all results in report order.
+func collectViolations(results []fileResult) []violation {
+	var violations []violation
+	for _, result := range results {
+		for _, d := range result.diagnostics {
+			violations = append(violations, violation{
+				fset:       result.fset,
+				diagnostic: d,
+				constraint: result.constraint,
+				severity:   severityOf(d),
+			})
+		}
+	}
+	return violations
+}
+
+// sampleViolations returns n violations chosen uniformly at random across all
+// files, in their original order. The same seed always picks the same sample.
+// All violations are returned when there are no more than n.
+func sampleViolations(violations []violation, n int, seed int64) []violation {
+	if n <= 0 || n >= len(violations) {
+		return violations
+	}
+
+	picked := rand.New(rand.NewSource(seed)).Perm(len(violations))[:n]
+	sort.Ints(picked)
+
+	sample := make([]violation, 0, n)
+	for _, i := range picked {
+		sample = append(sample, violations[i])
+	}
+	return sample
+}
+
+// formatCount formats n with thousands separators, e.g. 10000 -> "10,000".
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestSampleViolations(t *testing.T) {
+	var results []fileResult
+	for f := 0; f < 10; f++ {
+		result := fileResult{fset: token.NewFileSet()}
+		for i := 0; i < 100; i++ {
+			result.diagnostics = append(result.diagnostics, analysis.Diagnostic{Pos: token.Pos(f*1000 + i)})
+		}
+		results = append(results, result)
+	}
+	all := collectViolations(results)
+
+	sample := sampleViolations(all, 50, 42)
+	if len(sample) != 50 {
+		t.Fatalf("got %d violations, want 50", len(sample))
+	}
+
+	// The sample must not just be the first violations of the first file
+	files := make(map[token.Pos]bool)
+	for i, v := range sample {
+		files[v.diagnostic.Pos/1000] = true
+		if i > 0 && v.diagnostic.Pos <= sample[i-1].diagnostic.Pos {
+			t.Fatalf("sample is not in report order: %v before %v", sample[i-1].diagnostic.Pos, v.diagnostic.Pos)
+		}
+	}
+	if len(files) < 2 {
+		t.Errorf("sample drawn from %d file(s), want violations from across files", len(files))
+	}
+
+	again := sampleViolations(all, 50, 42)
+	for i := range sample {
+		if sample[i].diagnostic.Pos != again[i].diagnostic.Pos {
+			t.Fatalf("same seed produced different samples")
+		}
+	}
+
+	if got := sampleViolations(all, 5000, 42); len(got) != len(all) {
+		t.Errorf("sampling more than the total returned %d violations, want %d", len(got), len(all))
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{
+		0:       "0",
+		999:     "999",
+		1000:    "1,000",
+		10000:   "10,000",
+		1234567: "1,234,567",
+	}
+	for n, expected := range tests {
+		if got := formatCount(n); got != expected {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, expected)
+		}
+	}
+}
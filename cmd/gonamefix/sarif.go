@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolURI      = "https://github.com/xbpk3t/gonamefix"
+)
+
+// The SARIF 2.1.0 objects written by -format sarif, reduced to the
+// properties gonamefix fills in.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		Version        string      `json:"version"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+		HelpURI          string       `json:"helpUri,omitempty"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+		Fixes     []sarifFix      `json:"fixes,omitempty"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine"`
+		EndColumn   int `json:"endColumn"`
+	}
+	sarifFix struct {
+		Description     sarifMessage          `json:"description"`
+		ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+	}
+	sarifArtifactChange struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Replacements     []sarifReplacement    `json:"replacements"`
+	}
+	sarifReplacement struct {
+		DeletedRegion   sarifRegion  `json:"deletedRegion"`
+		InsertedContent sarifMessage `json:"insertedContent"`
+	}
+)
+
+// newSARIFLog describes violations as a SARIF log with a single run. Every
+// mapping of config is a rule, and so is every other category of the
+// findings, such as max-length. The safe fixes in fixes are the fixes of
+// their findings; findings without one have none.
+func newSARIFLog(violations []violation, fixes map[findingKey]appliedFix, config gonamefix.Config, toolVersion string) sarifLog {
+	var rules []sarifRule
+	ruleIndex := make(map[string]int)
+	addRule := func(rule sarifRule) {
+		if _, ok := ruleIndex[rule.ID]; !ok {
+			ruleIndex[rule.ID] = len(rules)
+			rules = append(rules, rule)
+		}
+	}
+	for _, mapping := range config.MappingRules() {
+		addRule(sarifRule{
+			ID:               mapping.ID,
+			ShortDescription: sarifMessage{Text: fmt.Sprintf("Use '%s' instead of '%s' in names", mapping.Replacement, mapping.Original)},
+			HelpURI:          mapping.URL,
+		})
+	}
+
+	results := make([]sarifResult, 0, len(violations))
+	for _, v := range violations {
+		ruleID := v.diagnostic.Category
+		if ruleID == "" {
+			ruleID = "gonamefix"
+		}
+		addRule(sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: "gonamefix " + ruleID + " findings"}})
+
+		result := sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndex[ruleID],
+			Level:     sarifLevel(v.severity),
+			Message:   sarifMessage{Text: v.diagnostic.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact(v.fset, v.diagnostic.Pos),
+				Region:           sarifSpan(v.fset, v.diagnostic.Pos, v.diagnostic.End),
+			}}},
+		}
+		if fix, ok := fixes[findingKeyOf(v.fset, v.diagnostic)]; ok {
+			result.Fixes = []sarifFix{sarifFixOf(fix)}
+		}
+		results = append(results, result)
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gonamefix",
+				Version:        toolVersion,
+				InformationURI: toolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// sarifFixOf describes fix, the declaration and references it renames, with
+// one artifact change per file it edits.
+func sarifFixOf(fix appliedFix) sarifFix {
+	suggested := fix.diagnostic.SuggestedFixes[0]
+	result := sarifFix{Description: sarifMessage{Text: suggested.Message}}
+	changes := make(map[string]int)
+	for _, edit := range suggested.TextEdits {
+		artifact := sarifArtifact(fix.fset, edit.Pos)
+		i, ok := changes[artifact.URI]
+		if !ok {
+			i = len(result.ArtifactChanges)
+			changes[artifact.URI] = i
+			result.ArtifactChanges = append(result.ArtifactChanges, sarifArtifactChange{ArtifactLocation: artifact})
+		}
+		result.ArtifactChanges[i].Replacements = append(result.ArtifactChanges[i].Replacements, sarifReplacement{
+			DeletedRegion:   sarifSpan(fix.fset, edit.Pos, edit.End),
+			InsertedContent: sarifMessage{Text: string(edit.NewText)},
+		})
+	}
+	return result
+}
+
+// sarifArtifact returns the location of the file of pos, relative to the
+// working directory or the base of -relative-paths, with forward slashes.
+func sarifArtifact(fset *token.FileSet, pos token.Pos) sarifArtifactLocation {
+	return sarifArtifactLocation{URI: filepath.ToSlash(reportPath(fset.Position(pos).Filename))}
+}
+
+// sarifSpan returns the region from pos to end, or of pos alone without end.
+func sarifSpan(fset *token.FileSet, pos, end token.Pos) sarifRegion {
+	start := fset.Position(pos)
+	stop := start
+	if end.IsValid() {
+		stop = fset.Position(end)
+	}
+	return sarifRegion{StartLine: start.Line, StartColumn: start.Column, EndLine: stop.Line, EndColumn: stop.Column}
+}
+
+// writeSARIF writes violations to out as a SARIF 2.1.0 log, with the safe
+// fixes in fixes.
+func writeSARIF(out io.Writer, violations []violation, fixes map[findingKey]appliedFix, config gonamefix.Config) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newSARIFLog(violations, fixes, config, version()))
+}
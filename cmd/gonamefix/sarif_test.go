@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}}}
+	var results []fileResult
+	// The fixes of rename.go rename references, or are left out
+	for _, name := range []string{"nested/main.go", "rdjson/rename.go"} {
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filepath.Join("testdata", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	fixed, err := computeFixes(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.MarshalIndent(newSARIFLog(collectViolations(results), fixesByFinding(fixed), config, "v1.2.3"), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "sarif", "nested.sarif")
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(append(got, '\n'), want) {
+		t.Errorf("SARIF log differs from %s:\n%s", golden, got)
+	}
+}
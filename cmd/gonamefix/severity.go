@@ -0,0 +1,48 @@
+package main
+
+import (
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Severities of findings, from the least to the most severe.
+const (
+	severityInfo    = "info"
+	severityWarning = "warning"
+	severityError   = "error"
+)
+
+// severities are the values of -severity and -fail-severity, ordered by rank.
+var severities = []string{severityInfo, severityWarning, severityError}
+
+// severityOf returns the severity of finding d: the -severity default, which
+// every finding has.
+func severityOf(d analysis.Diagnostic) string {
+	return *severityFlag
+}
+
+// meetsSeverity reports whether severity is at least as severe as threshold.
+func meetsSeverity(severity, threshold string) bool {
+	return slices.Index(severities, severity) >= slices.Index(severities, threshold)
+}
+
+// failingFindings reports whether a run with violations fails for them:
+// when one of them meets -fail-severity, which is info, any finding, by
+// default.
+func failingFindings(violations []violation) bool {
+	for _, v := range violations {
+		if meetsSeverity(v.severity, *failSeverityFlag) {
+			return true
+		}
+	}
+	return false
+}
+
+// sarifLevel returns the SARIF level of severity.
+func sarifLevel(severity string) string {
+	if severity == severityInfo {
+		return "note"
+	}
+	return severity
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestMeetsSeverity(t *testing.T) {
+	tests := []struct {
+		severity, threshold string
+		want                bool
+	}{
+		{severityError, severityError, true},
+		{severityError, severityInfo, true},
+		{severityWarning, severityError, false},
+		{severityWarning, severityWarning, true},
+		{severityInfo, severityWarning, false},
+	}
+	for _, tt := range tests {
+		if got := meetsSeverity(tt.severity, tt.threshold); got != tt.want {
+			t.Errorf("meetsSeverity(%q, %q) = %t, want %t", tt.severity, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestFailingFindings(t *testing.T) {
+	warnings := []violation{{severity: severityInfo}, {severity: severityWarning}}
+
+	saved := *failSeverityFlag
+	t.Cleanup(func() { *failSeverityFlag = saved })
+
+	// By default any finding fails the run
+	*failSeverityFlag = severityInfo
+	if !failingFindings(warnings) || failingFindings(nil) {
+		t.Error("failingFindings() with -fail-severity info does not follow the number of findings")
+	}
+	*failSeverityFlag = severityError
+	if failingFindings(warnings) {
+		t.Error("failingFindings() fails on warnings with -fail-severity error")
+	}
+	*failSeverityFlag = severityWarning
+	if !failingFindings(warnings) {
+		t.Error("failingFindings() passes a warning with -fail-severity warning")
+	}
+}
+
+func TestTextWriterSeverity(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("a.go", -1, 10)
+	d := analysis.Diagnostic{Pos: file.Pos(4), Message: "suggest replacing 'request' with 'req'"}
+
+	tests := []struct {
+		severity string
+		color    bool
+		want     string
+	}{
+		{severityError, false, "a.go:1:5: suggest replacing 'request' with 'req'\n"},
+		{severityWarning, false, "a.go:1:5: warning: suggest replacing 'request' with 'req'\n"},
+		{severityInfo, false, "a.go:1:5: info: suggest replacing 'request' with 'req'\n"},
+		{severityWarning, true, "\033[1ma.go:1:5:\033[0m \033[33mwarning:\033[0m suggest replacing '\033[31mrequest\033[0m' with '\033[32mreq\033[0m'\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		newTextWriter(&out, tt.color).diagnostic(violation{fset: fset, diagnostic: d, severity: tt.severity})
+		if out.String() != tt.want {
+			t.Errorf("diagnostic with severity %s = %q, want %q", tt.severity, out.String(), tt.want)
+		}
+	}
+}
+
+func TestSARIFLevel(t *testing.T) {
+	for severity, want := range map[string]string{severityError: "error", severityWarning: "warning", severityInfo: "note"} {
+		if got := sarifLevel(severity); got != want {
+			t.Errorf("sarifLevel(%q) = %q, want %q", severity, got, want)
+		}
+	}
+}
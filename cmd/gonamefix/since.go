@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// changedFiles returns the absolute paths of the files changed between ref
+// and HEAD, as git diff ref...HEAD lists them, in the repository containing
+// dir. Deleted files are left out.
+func changedFiles(dir, ref string) (map[string]bool, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("-since requires a git repository: %w", err)
+	}
+	root = strings.TrimSpace(root)
+
+	out, err := git(dir, "diff", "--name-only", "--diff-filter=d", "-z", ref+"...HEAD", "--")
+	if err != nil {
+		return nil, fmt.Errorf("-since %s: %w", ref, err)
+	}
+	changed := make(map[string]bool)
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			changed[canonicalPath(filepath.Join(root, filepath.FromSlash(name)))] = true
+		}
+	}
+	return changed, nil
+}
+
+// git runs git with args in dir and returns its output. Its error carries
+// what git printed on stderr.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && stderr.Len() > 0 {
+		return "", errors.New(strings.TrimSpace(stderr.String()))
+	}
+	return string(out), err
+}
+
+// canonicalPath returns the absolute path of filename with symbolic links
+// resolved, so that paths given in different ways compare equal.
+func canonicalPath(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = resolved
+	}
+	return filename
+}
+
+// onlyChanged returns the files of files in changed.
+func onlyChanged(files []string, changed map[string]bool) []string {
+	var kept []string
+	for _, filename := range files {
+		if changed[canonicalPath(filename)] {
+			kept = append(kept, filename)
+		}
+	}
+	return kept
+}
+
+// sinceFilter restricts files and markdownFiles to those changed since ref,
+// in the repository of the working directory.
+func sinceFilter(ref string, files, markdownFiles []string) ([]string, []string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, err
+	}
+	changed, err := changedFiles(dir, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	return onlyChanged(files, changed), onlyChanged(markdownFiles, changed), nil
+}
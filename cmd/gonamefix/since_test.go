@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	write := func(name, src string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	write("a.go", "package p\n")
+	write("b.go", "package p\n")
+	write("gone.go", "package p\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "base")
+	run("checkout", "-q", "-b", "feature")
+	write("b.go", "package p\n\nvar request int\n")
+	write("sub/c.go", "package sub\n")
+	if err := os.Remove(filepath.Join(dir, "gone.go")); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "-A")
+	run("commit", "-q", "-m", "change")
+
+	changed, err := changedFiles(filepath.Join(dir, "sub"), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{filepath.Join(dir, "a.go"), filepath.Join(dir, "b.go"), filepath.Join(dir, "sub", "c.go")}
+	want := []string{filepath.Join(dir, "b.go"), filepath.Join(dir, "sub", "c.go")}
+	if got := onlyChanged(files, changed); !slices.Equal(got, want) {
+		t.Errorf("onlyChanged() = %q, want %q", got, want)
+	}
+	if len(changed) != 2 {
+		t.Errorf("changedFiles() = %v, want only b.go and sub/c.go", changed)
+	}
+
+	if _, err := changedFiles(dir, "no-such-ref"); err == nil {
+		t.Error("changedFiles() of an unknown ref succeeded")
+	}
+}
+
+func TestChangedFilesOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+	_, err := changedFiles(t.TempDir(), "main")
+	if err == nil || !strings.Contains(err.Error(), "requires a git repository") {
+		t.Errorf("changedFiles() outside a repository = %v, want a git repository error", err)
+	}
+}
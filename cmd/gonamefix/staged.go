@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// errNoRepository is returned by stagedFiles outside a git repository.
+var errNoRepository = errors.New("not in a git repository")
+
+// stagedFiles returns the root of the git repository containing dir and the
+// slash-separated paths, relative to it, of the Go files added, copied,
+// modified or renamed in its index. Files in skipped directories are left
+// out.
+func stagedFiles(dir string, config gonamefix.Config) (string, []string, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, errNoRepository
+	}
+	root = strings.TrimSpace(root)
+
+	out, err := git(root, "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z", "--", "*.go")
+	if err != nil {
+		return "", nil, fmt.Errorf("-staged: %w", err)
+	}
+	var names []string
+	for _, name := range strings.Split(out, "\x00") {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		parent := filepath.Join(root, filepath.Dir(filepath.FromSlash(name)))
+		if gonamefix.SkipVendor(parent, config.IncludeVendor) || gonamefix.SkipExcludedDir(parent, config.ExcludeDirs) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return root, names, nil
+}
+
+// analyzeStaged analyzes the staged content of the file name in the
+// repository at root, as git show :name prints it, so that a partially
+// staged file is judged on what will be committed. Findings name the file
+// relative to the working directory.
+func analyzeStaged(root, name string, config gonamefix.Config) (fileResult, error) {
+	filename := relativePath(filepath.Join(root, filepath.FromSlash(name)))
+	src, err := git(root, "show", ":"+name)
+	if err != nil {
+		return fileResult{filename: filename, fset: token.NewFileSet()}, err
+	}
+	return analyzeSource(filename, []byte(src), config)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	write := func(name, src string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+
+	run("init", "-q")
+	write("gone.go", "package p\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "base")
+
+	_, names, err := stagedFiles(dir, config)
+	if err != nil || len(names) != 0 {
+		t.Fatalf("stagedFiles() with nothing staged = %q, %v, want none", names, err)
+	}
+
+	write("a.go", "package p\n\nvar request int\n")
+	write("sub/b.go", "package sub\n")
+	write("vendor/v/v.go", "package v\n")
+	write("notes.txt", "request\n")
+	write("unstaged.go", "package p\n")
+	if err := os.Remove(filepath.Join(dir, "gone.go")); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "a.go", "sub/b.go", "vendor/v/v.go", "notes.txt", "gone.go")
+	// The fix in the working tree is not staged and must not hide the finding
+	write("a.go", "package p\n\nvar req int\n")
+
+	root, names, err := stagedFiles(filepath.Join(dir, "sub"), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a.go", "sub/b.go"}; !slices.Equal(names, want) {
+		t.Errorf("stagedFiles() = %q, want %q", names, want)
+	}
+
+	result, err := analyzeStaged(root, "a.go", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.diagnostics) != 1 || !strings.Contains(result.diagnostics[0].Message, "'request'") {
+		t.Errorf("analyzeStaged() = %v, want the staged request reported", result.diagnostics)
+	}
+}
+
+func TestStagedFilesOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+	if _, _, err := stagedFiles(t.TempDir(), gonamefix.Config{}); !errors.Is(err, errNoRepository) {
+		t.Errorf("stagedFiles() outside a repository = %v, want errNoRepository", err)
+	}
+}
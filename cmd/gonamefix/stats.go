@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// statsExamples is the number of example locations -stats lists per mapping.
+const statsExamples = 5
+
+// mappingStats is how often a mapping fired, as written by -stats.
+type mappingStats struct {
+	Count int `json:"count"`
+	// Files is the number of distinct files with findings of the mapping
+	Files    int             `json:"files"`
+	Examples []statsLocation `json:"examples"`
+}
+
+// statsLocation is a finding listed as an example by -stats.
+type statsLocation struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// newMappingStats counts the findings of every mapping of config in
+// violations, keyed by the mapping written as in -check, e.g. request:req.
+// Mappings without findings are included with a count of 0, so that dead
+// rules show up. Findings of other rules are left out.
+func newMappingStats(violations []violation, config gonamefix.Config) map[string]*mappingStats {
+	names := ruleNames(config)
+	stats := make(map[string]*mappingStats, len(names))
+	for _, name := range names {
+		stats[name] = &mappingStats{Examples: []statsLocation{}}
+	}
+
+	files := make(map[string]map[string]bool)
+	for _, v := range violations {
+		name, ok := names[v.diagnostic.Category]
+		if !ok {
+			continue
+		}
+		s := stats[name]
+		s.Count++
+		pos := v.fset.Position(v.diagnostic.Pos)
+		if files[name] == nil {
+			files[name] = make(map[string]bool)
+		}
+		if !files[name][pos.Filename] {
+			files[name][pos.Filename] = true
+			s.Files++
+		}
+		if len(s.Examples) < statsExamples {
+			s.Examples = append(s.Examples, statsLocation{
+				File:   filepath.ToSlash(reportPath(pos.Filename)),
+				Line:   pos.Line,
+				Column: pos.Column,
+			})
+		}
+	}
+	return stats
+}
+
+// writeStats writes the statistics of the mappings of config over violations
+// to path as a JSON object, sorted by mapping.
+func writeStats(path string, violations []violation, config gonamefix.Config) error {
+	data, err := json.MarshalIndent(newMappingStats(violations, config), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
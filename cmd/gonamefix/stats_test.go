@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestNewMappingStats(t *testing.T) {
+	dir := t.TempDir()
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"response", "res"}, {"handler", "h"}}}
+	var results []fileResult
+	for _, file := range []struct{ name, src string }{
+		{"a.go", "package p\n\nvar request, requestA, requestB, requestC, requestD, requestE int\n"},
+		{"b.go", "package p\n\nvar requestF, response int\n"},
+	} {
+		filename := filepath.Join(dir, file.name)
+		if err := os.WriteFile(filename, []byte(file.src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		result, err := analyzeFile(gonamefix.NewAnalyzer(config), config, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+
+	stats := newMappingStats(collectViolations(results), config)
+	if len(stats) != 3 {
+		t.Fatalf("newMappingStats() = %v, want the 3 mappings", stats)
+	}
+	request := stats["request:req"]
+	if request.Count != 7 || request.Files != 2 || len(request.Examples) != statsExamples {
+		t.Errorf("request:req = %+v, want 7 findings in 2 files and %d examples", request, statsExamples)
+	}
+	if example := request.Examples[0]; !strings.HasSuffix(example.File, "a.go") || example.Line != 3 || example.Column != 5 {
+		t.Errorf("first example of request:req = %+v, want a.go:3:5", example)
+	}
+	if response := stats["response:res"]; response.Count != 1 || response.Files != 1 {
+		t.Errorf("response:res = %+v, want 1 finding in 1 file", response)
+	}
+	// Mappings that never fire are listed too
+	if handler := stats["handler:h"]; handler.Count != 0 || handler.Examples == nil {
+		t.Errorf("handler:h = %+v, want a count of 0 and no examples", handler)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// stdinFilename names standard input in diagnostics, as gofmt does.
+const stdinFilename = "<standard input>"
+
+// fixStdin runs gonamefix as a formatter: it reads a single Go file named
+// filename from in, writes it to out with the safe fixes applied, even when nothing changed,
+// and prints the diagnostics to errOut. Input that does not parse is copied
+// to out unchanged and the parse error returned, so an editor keeps its
+// buffer. The result reports whether fixes changed the input.
+func fixStdin(in io.Reader, out, errOut io.Writer, filename string, config gonamefix.Config) (bool, error) {
+	src, err := io.ReadAll(in)
+	if err != nil {
+		return false, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		if _, writeErr := out.Write(src); writeErr != nil {
+			return false, writeErr
+		}
+		return false, err
+	}
+
+	diagnostics, err := gonamefix.AnalyzeFile(fset, file, config)
+	if err != nil {
+		if _, writeErr := out.Write(src); writeErr != nil {
+			return false, writeErr
+		}
+		return false, err
+	}
+
+	for _, d := range diagnostics {
+		pos := fset.Position(d.Pos)
+		fmt.Fprintf(errOut, "%s:%d:%d: %s\n", pos.Filename, pos.Line, pos.Column, d.Message)
+	}
+
+	fixed, count := applyFixes(fset, src, safeFixes(newRenameIndex([]*ast.File{file}, false), diagnostics))
+	if count > 0 {
+		formatted, err := formatFixed(filename, fixed)
+		if err != nil {
+			if _, writeErr := out.Write(src); writeErr != nil {
+				return false, writeErr
+			}
+			return false, err
+		}
+		fixed = formatted
+	}
+	_, err = out.Write(fixed)
+	return count > 0, err
+}
+
+// analyzeStdin analyzes a single Go file read from in as if it were named
+// filename, so that exclusions by file name apply. Nothing else is read.
+// Input that does not parse is tokenized instead with
+// -fallback-to-tokenizer, like a file would be.
+func analyzeStdin(in io.Reader, filename string, config gonamefix.Config) (fileResult, error) {
+	src, err := io.ReadAll(in)
+	if err != nil {
+		return fileResult{filename: filename, fset: token.NewFileSet()}, err
+	}
+	return analyzeSource(filename, src, config)
+}
+
+// analyzeSource analyzes src, the content of a Go file held in memory, as if
+// it were the file filename.
+func analyzeSource(filename string, src []byte, config gonamefix.Config) (fileResult, error) {
+	fset := token.NewFileSet()
+	result := fileResult{filename: filename, fset: fset, src: src}
+
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		if config.FallbackToTokenizer {
+			result.diagnostics = gonamefix.AnalyzeTokens(fset, filename, src, config)
+			return result, fmt.Errorf("parse error (partial results reported): %w", err)
+		}
+		return result, fmt.Errorf("parse error: %w", err)
+	}
+
+	result.diagnostics, err = gonamefix.AnalyzeFile(fset, file, config)
+	return result, err
+}
+
+// stdinName returns the name of the file read from standard input: the
+// -stdin-filename flag, or <standard input>.
+func stdinName() string {
+	if *stdinFilenameFlag != "" {
+		return *stdinFilenameFlag
+	}
+	return stdinFilename
+}
+
+// isStdinMode reports whether the command line asks to read standard input,
+// with -stdin or with the single argument "-".
+func isStdinMode(args []string) bool {
+	return *stdinFlag || len(args) == 1 && args[0] == "-"
+}
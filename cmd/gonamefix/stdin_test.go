@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestFixStdin(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+
+	tests := []struct {
+		name    string
+		src     string
+		want    string
+		stderr  string
+		changed bool
+		wantErr bool
+	}{
+		{
+			name:    "fixes applied",
+			src:     "package p\n\nfunc handle(request string) string {\n\treturn request\n}\n",
+			want:    "package p\n\nfunc handle(req string) string {\n\treturn req\n}\n",
+			changed: true,
+			stderr:  "<standard input>:3:13: suggest replacing 'request' with 'req'",
+		},
+		{
+			name:   "package-level names left alone",
+			src:    "package p\n\nvar request string\n",
+			want:   "package p\n\nvar request string\n",
+			stderr: "<standard input>:3:5: suggest replacing 'request' with 'req'",
+		},
+		{
+			name:   "fields left alone",
+			src:    "package p\n\ntype T struct{ request string }\n",
+			want:   "package p\n\ntype T struct{ request string }\n",
+			stderr: "suggest replacing 'request' with 'req'",
+		},
+		{
+			name:   "rename to a name in use left alone",
+			src:    "package p\n\nfunc handle(request, req string) string {\n\treturn request + req\n}\n",
+			want:   "package p\n\nfunc handle(request, req string) string {\n\treturn request + req\n}\n",
+			stderr: "suggest replacing 'request' with 'req'",
+		},
+		{
+			name: "unchanged source written",
+			src:  "package p\n\nvar req string\n",
+			want: "package p\n\nvar req string\n",
+		},
+		{
+			name:    "parse error copies input",
+			src:     "package p\n\nvar request = \n",
+			want:    "package p\n\nvar request = \n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			changed, err := fixStdin(strings.NewReader(tt.src), &out, &errOut, stdinFilename, config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fixStdin() error = %v, want error %t", err, tt.wantErr)
+			}
+			if changed != tt.changed {
+				t.Errorf("fixStdin() changed = %t, want %t", changed, tt.changed)
+			}
+			if out.String() != tt.want {
+				t.Errorf("fixStdin() wrote %q, want %q", out.String(), tt.want)
+			}
+			if !strings.Contains(errOut.String(), tt.stderr) || tt.stderr == "" && errOut.Len() != 0 {
+				t.Errorf("fixStdin() diagnostics = %q, want %q", errOut.String(), tt.stderr)
+			}
+		})
+	}
+}
+
+func TestAnalyzeStdin(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}, ExcludeFiles: []string{"*_test.go"}}
+	src := "package p\n\nfunc handle(request string) {}\n"
+
+	tests := []struct {
+		name     string
+		filename string
+		want     []string
+	}{
+		{
+			name:     "virtual file name in positions",
+			filename: "pkg/handler.go",
+			want:     []string{"pkg/handler.go:3:13: suggest replacing 'request' with 'req'"},
+		},
+		{
+			name:     "exclusions match the virtual file name",
+			filename: "pkg/handler_test.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := analyzeStdin(strings.NewReader(src), tt.filename, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, d := range result.diagnostics {
+				pos := result.fset.Position(d.Pos)
+				got = append(got, fmt.Sprintf("%s:%d:%d: %s", pos.Filename, pos.Line, pos.Column, d.Message))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("analyzeStdin() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := analyzeStdin(strings.NewReader("package p\n\nvar request =\n"), "broken.go", config); err == nil {
+		t.Error("analyzeStdin() of invalid source succeeded, want a parse error")
+	}
+}
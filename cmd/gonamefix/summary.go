@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// runSummary counts what a run analyzed and found, for -summary.
+type runSummary struct {
+	// scanned is the number of files analyzed, excluded the number of files
+	// skipped by the exclusion patterns and constrained the number skipped
+	// by build constraints
+	scanned, excluded, constrained int
+	findings                       int
+	// rules are the findings by mapping or rule, most first
+	rules []topEntry
+}
+
+// patternNames returns the names of the mappings of config, such as
+// request→req for the rule gonamefix/request-req, by rule ID.
+func patternNames(config gonamefix.Config) map[string]string {
+	names := make(map[string]string)
+	for _, rule := range config.MappingRules() {
+		names[rule.ID] = gonamefix.MappingCategory(rule.Original, rule.Replacement)
+	}
+	return names
+}
+
+// newRunSummary summarizes results. Mappings are named like "request→req",
+// other rules by their category.
+func newRunSummary(results []fileResult, constrained int, config gonamefix.Config) runSummary {
+	names := patternNames(config)
+
+	summary := runSummary{constrained: constrained}
+	counts := make(map[string]int)
+	for _, result := range results {
+		// Excluded files are read, but the analyzer skips them
+		if _, excluded := gonamefix.MatchExclusion(result.filename, config); excluded {
+			summary.excluded++
+			continue
+		}
+		summary.scanned++
+		for _, d := range result.diagnostics {
+			name, ok := names[d.Category]
+			switch {
+			case ok:
+			case d.Category != "":
+				name = d.Category
+			default:
+				name = "other"
+			}
+			counts[name]++
+			summary.findings++
+		}
+	}
+	summary.rules = rank(counts, summary.findings, 0)
+	return summary
+}
+
+// writeSummary prints summary as a paragraph.
+func writeSummary(out io.Writer, summary runSummary) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "summary: %s files scanned, %s skipped by exclusion", formatCount(summary.scanned), formatCount(summary.excluded))
+	if summary.constrained > 0 {
+		fmt.Fprintf(&b, ", %s skipped by build constraints", formatCount(summary.constrained))
+	}
+	fmt.Fprintf(&b, "; %s findings", formatCount(summary.findings))
+	for i, rule := range summary.rules {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s: %s", rule.Name, formatCount(rule.Count))
+	}
+	fmt.Fprintln(out, b.String())
+}
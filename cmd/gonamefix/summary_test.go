@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestRunSummary(t *testing.T) {
+	config := gonamefix.Config{
+		Check:        [][]string{{"request", "req"}, {"response", "res"}},
+		ExcludeFiles: []string{"*.pb.go"},
+	}
+	ids := make(map[string]string)
+	for _, rule := range config.MappingRules() {
+		ids[rule.Original] = rule.ID
+	}
+	diagnostics := func(categories ...string) []analysis.Diagnostic {
+		var ds []analysis.Diagnostic
+		for _, category := range categories {
+			ds = append(ds, analysis.Diagnostic{Category: category})
+		}
+		return ds
+	}
+	fset := token.NewFileSet()
+	results := []fileResult{
+		{filename: "a.go", fset: fset, diagnostics: diagnostics(ids["response"], ids["request"], ids["request"])},
+		{filename: "b.go", fset: fset, diagnostics: diagnostics(ids["request"], "gonamefix/max-length", "")},
+		{filename: "api.pb.go", fset: fset},
+	}
+
+	var out bytes.Buffer
+	writeSummary(&out, newRunSummary(results, 1, config))
+	want := "summary: 2 files scanned, 1 skipped by exclusion, 1 skipped by build constraints; " +
+		"6 findings: request→req: 3, gonamefix/max-length: 1, other: 1, response→res: 1\n"
+	if out.String() != want {
+		t.Errorf("writeSummary() = %q, want %q", out.String(), want)
+	}
+}
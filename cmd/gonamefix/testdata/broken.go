@@ -0,0 +1,6 @@
+package broken
+
+var request string
+
+func handleRequest( {
+}
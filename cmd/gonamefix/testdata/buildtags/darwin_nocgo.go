@@ -0,0 +1,5 @@
+//go:build (darwin || freebsd) && !cgo
+
+package buildtags
+
+var request string
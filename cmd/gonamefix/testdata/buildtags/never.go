@@ -0,0 +1,5 @@
+//go:build linux && !linux
+
+package buildtags
+
+var request string
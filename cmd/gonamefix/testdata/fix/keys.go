@@ -0,0 +1,20 @@
+package keys
+
+type T struct{ request string }
+
+// The key names the field, not the parameter, so neither is renamed
+func build(request string) T {
+	return T{request: request}
+}
+
+var requestCount int
+
+type counter struct{ requestCount int }
+
+func count() counter {
+	return counter{requestCount: requestCount}
+}
+
+func echo(response string) string {
+	return response
+}
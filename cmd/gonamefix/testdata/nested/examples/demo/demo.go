@@ -0,0 +1,3 @@
+package demo
+
+var request string
@@ -0,0 +1,3 @@
+module example.com/nested/examples
+
+go 1.24
@@ -0,0 +1,5 @@
+package main
+
+var request string
+
+func main() {}
@@ -0,0 +1,3 @@
+package tools
+
+var request string
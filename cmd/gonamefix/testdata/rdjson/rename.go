@@ -0,0 +1,11 @@
+package rename
+
+// Columns count bytes: requestBody starts at column 12, after the two bytes of é
+var café, requestBody string
+
+// Exported names have no safe fix, and no suggestion
+type RequestHandler struct{}
+
+func handle(requestID string) string {
+	return requestID
+}
@@ -0,0 +1,3 @@
+module example.com/ws/legacy
+
+go 1.24
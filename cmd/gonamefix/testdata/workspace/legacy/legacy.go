@@ -0,0 +1,3 @@
+package legacy
+
+var request string
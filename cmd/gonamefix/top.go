@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+// topLimit is the number of identifiers and files listed by the top subcommand.
+const topLimit = 20
+
+// topEntry is one ranked row of the top subcommand.
+type topEntry struct {
+	Name    string  `json:"name"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+// topReport ranks the findings of a run by identifier, file and rule.
+type topReport struct {
+	Total       int        `json:"total"`
+	Identifiers []topEntry `json:"identifiers"`
+	Files       []topEntry `json:"files"`
+	Rules       []topEntry `json:"rules"`
+}
+
+// newTopReport aggregates violations into the top identifiers and files and
+// the hit count of every rule. The identifier of a finding is the source text
+// it spans.
+func newTopReport(violations []violation) topReport {
+	identifiers := make(map[string]int)
+	files := make(map[string]int)
+	rules := make(map[string]int)
+	sources := make(map[string][]byte)
+
+	for _, v := range violations {
+		pos := v.fset.Position(v.diagnostic.Pos)
+		files[displayPath(pos.Filename)]++
+		if v.diagnostic.Category != "" {
+			rules[v.diagnostic.Category]++
+		}
+		if name := findingText(v, sources); name != "" {
+			identifiers[name]++
+		}
+	}
+
+	total := len(violations)
+	return topReport{
+		Total:       total,
+		Identifiers: rank(identifiers, total, topLimit),
+		Files:       rank(files, total, topLimit),
+		Rules:       rank(rules, total, 0),
+	}
+}
+
+// findingText returns the source text of the finding v, reading each file
+// once into sources.
+func findingText(v violation, sources map[string][]byte) string {
+	start, end := v.fset.Position(v.diagnostic.Pos), v.fset.Position(v.diagnostic.End)
+	if !v.diagnostic.End.IsValid() || end.Filename != start.Filename {
+		return ""
+	}
+	src, ok := sources[start.Filename]
+	if !ok {
+		src, _ = os.ReadFile(start.Filename)
+		sources[start.Filename] = src
+	}
+	if start.Offset < 0 || end.Offset > len(src) || start.Offset >= end.Offset {
+		return ""
+	}
+	return string(src[start.Offset:end.Offset])
+}
+
+// rank sorts counts by count, most first, and keeps the first limit entries
+// (all with limit 0). Percentages are of total.
+func rank(counts map[string]int, total, limit int) []topEntry {
+	entries := make([]topEntry, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, topEntry{Name: name, Count: count, Percent: 100 * float64(count) / float64(total)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// writeTop prints report as tables, or as JSON with format "json".
+func writeTop(out io.Writer, report topReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	sections := []struct {
+		title   string
+		entries []topEntry
+	}{
+		{"IDENTIFIER", report.Identifiers},
+		{"FILE", report.Files},
+		{"RULE", report.Rules},
+	}
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\tFINDINGS\tSHARE\n", section.title)
+		for _, entry := range section.entries {
+			fmt.Fprintf(w, "%s\t%d\t%.1f%%\n", entry.Name, entry.Count, entry.Percent)
+		}
+	}
+	fmt.Fprintf(w, "\n%s findings in total\n", formatCount(report.Total))
+	return w.Flush()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestTopReport(t *testing.T) {
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}, {"temporary", "temp"}}}
+	analyzer := gonamefix.NewAnalyzer(config)
+
+	var results []fileResult
+	for _, name := range []string{"main.go", filepath.Join("pkg", "pkg.go")} {
+		result, err := analyzeFile(analyzer, config, filepath.Join("testdata", "nested", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+
+	report := newTopReport(collectViolations(results))
+	if report.Total == 0 || len(report.Identifiers) == 0 || len(report.Files) == 0 || len(report.Rules) == 0 {
+		t.Fatalf("newTopReport() = %+v, want findings in every ranking", report)
+	}
+
+	percent := 0.0
+	for _, rule := range report.Rules {
+		percent += rule.Percent
+	}
+	if percent < 99.9 || percent > 100.1 {
+		t.Errorf("rule shares add up to %.1f%%, want 100%%", percent)
+	}
+
+	var out bytes.Buffer
+	if err := writeTop(&out, report, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded topReport
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded.Total != report.Total {
+		t.Errorf("writeTop(json) = %s, want the report as JSON (err %v)", out.String(), err)
+	}
+
+	out.Reset()
+	if err := writeTop(&out, report, "text"); err != nil {
+		t.Fatal(err)
+	}
+	for _, header := range []string{"IDENTIFIER", "FILE", "RULE"} {
+		if !strings.Contains(out.String(), header) {
+			t.Errorf("writeTop(text) = %q, want a %s table", out.String(), header)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	entries := rank(map[string]int{"a": 1, "b": 3, "c": 1}, 5, 2)
+	if len(entries) != 2 || entries[0].Name != "b" || entries[1].Name != "a" {
+		t.Fatalf("rank() = %+v, want b then a", entries)
+	}
+	if entries[0].Percent != 60 {
+		t.Errorf("rank() share of b = %.1f, want 60", entries[0].Percent)
+	}
+}
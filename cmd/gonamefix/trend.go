@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// trendEntry is the summary of a single run stored in the trend file.
+// ViolationsPerPattern is keyed by the name of the mapping, e.g. request→req,
+// or by the category of other rules.
+type trendEntry struct {
+	Timestamp            time.Time      `json:"timestamp"`
+	TotalViolations      int            `json:"total_violations"`
+	FilesChecked         int            `json:"files_checked"`
+	ViolationsPerPattern map[string]int `json:"violations_per_pattern"`
+}
+
+func newTrendEntry(results []fileResult, names map[string]string, now time.Time) trendEntry {
+	entry := trendEntry{
+		Timestamp:            now.UTC(),
+		FilesChecked:         len(results),
+		ViolationsPerPattern: make(map[string]int),
+	}
+	for _, result := range results {
+		for _, d := range result.diagnostics {
+			entry.TotalViolations++
+			if name, ok := names[d.Category]; ok {
+				entry.ViolationsPerPattern[name]++
+			} else if d.Category != "" {
+				entry.ViolationsPerPattern[d.Category]++
+			}
+		}
+	}
+	return entry
+}
+
+func readTrendFile(path string) ([]trendEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var history []trendEntry
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("invalid trend file %s: %w", path, err)
+	}
+	return history, nil
+}
+
+// recordTrend appends the summary of this run to the trend file, prints the
+// trend line to out and trims the history to the last keepLast entries.
+// names maps the rule IDs of mappings to their names; runs recorded by rule
+// ID are migrated to the names, so that the history compares by pattern.
+func recordTrend(out io.Writer, path string, results []fileResult, names map[string]string, keepLast int) error {
+	history, err := readTrendFile(path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range history {
+		for id, count := range entry.ViolationsPerPattern {
+			if name, ok := names[id]; ok {
+				delete(entry.ViolationsPerPattern, id)
+				entry.ViolationsPerPattern[name] += count
+			}
+		}
+	}
+
+	entry := newTrendEntry(results, names, time.Now())
+	fmt.Fprintln(out, trendLine(history, entry))
+
+	history = append(history, entry)
+	if keepLast > 0 && len(history) > keepLast {
+		history = history[len(history)-keepLast:]
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// trendLine describes entry relative to the previous run and the first recorded run (the baseline).
+func trendLine(history []trendEntry, entry trendEntry) string {
+	line := fmt.Sprintf("violations: %d", entry.TotalViolations)
+	if len(history) == 0 {
+		return line + " (first recorded run)"
+	}
+
+	last := history[len(history)-1]
+	baseline := history[0]
+	return fmt.Sprintf("%s (%s from last run, %s from baseline)", line,
+		trendDelta(entry.TotalViolations-last.TotalViolations),
+		trendDelta(entry.TotalViolations-baseline.TotalViolations))
+}
+
+func trendDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("↑%d", delta)
+	case delta < 0:
+		return fmt.Sprintf("↓%d", -delta)
+	default:
+		return "±0"
+	}
+}
+
+// printTrendSummary prints every run recorded in the trend file as a table.
+func printTrendSummary(path string) error {
+	history, err := readTrendFile(path)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		fmt.Printf("No runs recorded in %s\n", path)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIMESTAMP\tVIOLATIONS\tFILES\tCHANGE")
+	for i, entry := range history {
+		change := "-"
+		if i > 0 {
+			change = trendDelta(entry.TotalViolations - history[i-1].TotalViolations)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", entry.Timestamp.Format(time.RFC3339), entry.TotalViolations, entry.FilesChecked, change)
+	}
+	return w.Flush()
+}
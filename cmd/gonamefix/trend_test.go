@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestTrendLine(t *testing.T) {
+	history := []trendEntry{
+		{TotalViolations: 59},
+		{TotalViolations: 50},
+	}
+
+	tests := []struct {
+		name     string
+		history  []trendEntry
+		total    int
+		expected string
+	}{
+		{"first run", nil, 47, "violations: 47 (first recorded run)"},
+		{"decrease", history, 47, "violations: 47 (↓3 from last run, ↓12 from baseline)"},
+		{"increase", history, 55, "violations: 55 (↑5 from last run, ↓4 from baseline)"},
+		{"unchanged", history[:1], 59, "violations: 59 (±0 from last run, ±0 from baseline)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := trendLine(tt.history, trendEntry{TotalViolations: tt.total})
+			if result != tt.expected {
+				t.Errorf("trendLine() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecordTrend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	results := []fileResult{
+		{diagnostics: []analysis.Diagnostic{{Category: "gonamefix/request-req"}, {Category: "gonamefix/request-req"}}},
+		{diagnostics: []analysis.Diagnostic{{Category: "response→res"}, {Category: "getter"}}},
+		{},
+	}
+	names := map[string]string{"gonamefix/request-req": "request→req"}
+
+	// A run recorded by rule ID is migrated to the name of the mapping
+	old := `[{"timestamp": "2025-01-01T00:00:00Z", "total_violations": 5, "files_checked": 3,
+		"violations_per_pattern": {"gonamefix/request-req": 4, "request→req": 1}}]`
+	if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := recordTrend(io.Discard, path, results, names, 4); err != nil {
+			t.Fatalf("recordTrend() returned error: %v", err)
+		}
+	}
+
+	history, err := readTrendFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 4 {
+		t.Fatalf("Expected history of 4 entries, got %d", len(history))
+	}
+	if want := map[string]int{"request→req": 5}; !reflect.DeepEqual(history[0].ViolationsPerPattern, want) {
+		t.Errorf("migrated ViolationsPerPattern = %v, want %v", history[0].ViolationsPerPattern, want)
+	}
+
+	entry := history[3]
+	if entry.TotalViolations != 4 || entry.FilesChecked != 3 {
+		t.Errorf("entry = %+v, want 4 violations in 3 files", entry)
+	}
+	want := map[string]int{"request→req": 2, "response→res": 1, "getter": 1}
+	if !reflect.DeepEqual(entry.ViolationsPerPattern, want) {
+		t.Errorf("ViolationsPerPattern = %v, want %v", entry.ViolationsPerPattern, want)
+	}
+
+	// The history is trimmed to the last keepLast runs
+	if err := recordTrend(io.Discard, path, results, names, 2); err != nil {
+		t.Fatal(err)
+	}
+	if history, err = readTrendFile(path); err != nil || len(history) != 2 {
+		t.Fatalf("Expected history trimmed to 2 entries, got %d (%v)", len(history), err)
+	}
+	if time.Since(entry.Timestamp) > time.Minute {
+		t.Errorf("Timestamp = %v, want the current time", entry.Timestamp)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// verbosef logs a line about the files of the run on stderr with -verbose,
+// so that stdout keeps only the findings.
+func verbosef(format string, args ...any) {
+	if *verboseFlag {
+		fmt.Fprintf(os.Stderr, "gonamefix: "+format+"\n", args...)
+	}
+}
+
+// fileDisposition describes what became of an analyzed file: the exclusion
+// that skipped it, the error it failed with, or the number of findings.
+func fileDisposition(result fileResult, err error, config gonamefix.Config) string {
+	if exclusion, excluded := gonamefix.MatchExclusion(result.filename, config); excluded {
+		return exclusion.String()
+	}
+	if err != nil {
+		return err.Error()
+	}
+	// The analyzer skips these files without a trace, so their source is
+	// parsed again for the reason
+	file, parseErr := parser.ParseFile(token.NewFileSet(), result.filename, result.src, parser.ImportsOnly|parser.ParseComments)
+	if parseErr == nil {
+		if gonamefix.HasExcludedBuildConstraint(file, config.ExcludeBuildConstraints) {
+			return "excluded by exclude-build-constraints (//go:build " + gonamefix.BuildConstraint(file) + ")"
+		}
+		if !config.IncludeGenerated && gonamefix.IsGenerated(file) {
+			return "excluded as generated (use -include-generated)"
+		}
+		if config.SkipCgo && importsC(file.Imports) {
+			return "excluded by skip-cgo (imports \"C\")"
+		}
+	}
+	if len(result.diagnostics) == 1 {
+		return "parsed, 1 finding"
+	}
+	return "parsed, " + formatCount(len(result.diagnostics)) + " findings"
+}
+
+// importsC reports whether imports hold the cgo pseudo-package "C".
+func importsC(imports []*ast.ImportSpec) bool {
+	for _, spec := range imports {
+		if spec.Path.Value == `"C"` {
+			return true
+		}
+	}
+	return false
+}
+
+// logDispositions logs the disposition of every file of the run with
+// -verbose. The files beyond results were not analyzed because -max-issues
+// was reached.
+func logDispositions(files []string, results []fileResult, errs []error, config gonamefix.Config) {
+	if !*verboseFlag {
+		return
+	}
+	for i, filename := range files {
+		if i >= len(results) {
+			verbosef("%s: not analyzed, -max-issues reached", filename)
+			continue
+		}
+		verbosef("%s: %s", filename, fileDisposition(results[i], errs[i], config))
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestFileDisposition(t *testing.T) {
+	config := gonamefix.Config{
+		Check:                   [][]string{{"request", "req"}},
+		ExcludeFiles:            []string{"*_test.go"},
+		ExcludeBuildConstraints: []string{"ignore"},
+		SkipCgo:                 true,
+	}
+	tests := []struct {
+		name   string
+		result fileResult
+		err    error
+		want   string
+	}{
+		{
+			name:   "excluded file",
+			result: fileResult{filename: "handler_test.go"},
+			want:   "excluded by exclude-files pattern '*_test.go'",
+		},
+		{
+			name:   "parse error",
+			result: fileResult{filename: "broken.go"},
+			err:    errors.New("parse error: broken.go:5:21: expected ')'"),
+			want:   "parse error: broken.go:5:21: expected ')'",
+		},
+		{
+			name:   "excluded build constraint",
+			result: fileResult{filename: "gen.go", src: []byte("//go:build ignore\n\npackage main\n")},
+			want:   "excluded by exclude-build-constraints (//go:build ignore)",
+		},
+		{
+			name:   "generated",
+			result: fileResult{filename: "mock.go", src: []byte("// Code generated by MockGen. DO NOT EDIT.\n\npackage main\n")},
+			want:   "excluded as generated (use -include-generated)",
+		},
+		{
+			name:   "cgo",
+			result: fileResult{filename: "cgo.go", src: []byte("package main\n\nimport \"C\"\n")},
+			want:   "excluded by skip-cgo (imports \"C\")",
+		},
+		{
+			name:   "no findings",
+			result: fileResult{filename: "main.go", src: []byte("package main\n")},
+			want:   "parsed, 0 findings",
+		},
+		{
+			name: "findings",
+			result: fileResult{filename: "main.go", src: []byte("package main\n"),
+				diagnostics: []analysis.Diagnostic{{Message: "a"}, {Message: "b"}}},
+			want: "parsed, 2 findings",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fileDisposition(tt.result, tt.err, config); got != tt.want {
+				t.Errorf("fileDisposition() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
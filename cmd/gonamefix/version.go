@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+)
+
+// buildInfo identifies the build of the binary, as printed by -version.
+type buildInfo struct {
+	// Version is the module version, devel for builds from a working tree
+	Version string `json:"version"`
+	// Revision and Modified describe the VCS checkout of a source build
+	Revision  string `json:"revision,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// readBuildInfo returns the build information embedded in the binary.
+// Binaries installed with go install carry the module version, builds from
+// a working tree the VCS revision instead.
+func readBuildInfo() buildInfo {
+	build := buildInfo{Version: "devel", GoVersion: runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		build.Version = info.Main.Version
+	}
+	if info.GoVersion != "" {
+		build.GoVersion = info.GoVersion
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
+
+// version returns the module version of the binary, devel for builds from
+// a working tree.
+func version() string {
+	return readBuildInfo().Version
+}
+
+// writeVersion writes build to out, as a JSON object with -format json.
+func writeVersion(out io.Writer, build buildInfo, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(build)
+	}
+
+	revision := ""
+	if build.Revision != "" {
+		revision = fmt.Sprintf(" (revision %s", build.Revision)
+		if build.Modified {
+			revision += ", modified"
+		}
+		revision += ")"
+	}
+	_, err := fmt.Fprintf(out, "gonamefix %s%s built with %s\n", build.Version, revision, build.GoVersion)
+	return err
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		build buildInfo
+		want  string
+	}{
+		{
+			name:  "installed",
+			build: buildInfo{Version: "v1.2.3", GoVersion: "go1.22.0"},
+			want:  "gonamefix v1.2.3 built with go1.22.0\n",
+		},
+		{
+			name:  "source build",
+			build: buildInfo{Version: "devel", Revision: "abc123", Modified: true, GoVersion: "go1.22.0"},
+			want:  "gonamefix devel (revision abc123, modified) built with go1.22.0\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeVersion(&out, tt.build, "text"); err != nil || out.String() != tt.want {
+				t.Errorf("writeVersion() = %q, %v; want %q", out.String(), err, tt.want)
+			}
+
+			out.Reset()
+			if err := writeVersion(&out, tt.build, "json"); err != nil {
+				t.Fatal(err)
+			}
+			var got buildInfo
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil || got != tt.build {
+				t.Errorf("writeVersion() JSON = %q, %v; want %+v", out.String(), err, tt.build)
+			}
+		})
+	}
+}
+
+func TestReadBuildInfo(t *testing.T) {
+	build := readBuildInfo()
+	if build.Version == "" || build.Version == "(devel)" || build.GoVersion == "" {
+		t.Errorf("readBuildInfo() = %+v, want a version and a Go version", build)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// watchInterval is how often -watch looks for changed files. Changes are
+// analyzed once a look finds no further ones, so that the files saved by a
+// refactoring tool together are analyzed together.
+const watchInterval = 500 * time.Millisecond
+
+// watchRescanInterval is how often -watch collects the files again to find
+// new and removed ones. Collecting walks the tree and reads build
+// constraints, so between rescans only the known files are looked at.
+const watchRescanInterval = 5 * time.Second
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watcher re-analyzes the files of a -watch session as they change. The
+// tree is polled, so that it works on every platform and file system.
+type watcher struct {
+	config gonamefix.Config
+	// scan returns the files to analyze, and their build constraints
+	scan func() ([]string, map[string]string)
+	// analyze analyzes files, returning their results and errors by index
+	analyze func(files []string, constraints map[string]string) ([]fileResult, []error)
+	out     *textWriter
+	// clear clears the screen before every report, on a terminal
+	clear bool
+
+	// files and constraints are those of the last scan
+	files       []string
+	constraints map[string]string
+
+	stamps  map[string]fileStamp
+	results map[string]fileResult
+	failed  map[string]bool
+}
+
+// poll returns the files that were added or changed since the last poll,
+// and the ones that were removed. Excluded files are ignored. The files are
+// only scanned again with rescan; otherwise those of the last scan are
+// looked at, and the new files are found by the next rescan.
+func (w *watcher) poll(rescan bool) (changed []string, constraints map[string]string, removed []string) {
+	if rescan {
+		w.files, w.constraints = w.scan()
+	}
+	seen := make(map[string]bool)
+	for _, filename := range w.files {
+		if _, excluded := gonamefix.MatchExclusion(filename, w.config); excluded {
+			continue
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			continue
+		}
+		seen[filename] = true
+		stamp := fileStamp{modTime: info.ModTime(), size: info.Size()}
+		if old, ok := w.stamps[filename]; !ok || old != stamp {
+			w.stamps[filename] = stamp
+			changed = append(changed, filename)
+		}
+	}
+	for filename := range w.stamps {
+		if !seen[filename] {
+			delete(w.stamps, filename)
+			removed = append(removed, filename)
+		}
+	}
+	sort.Strings(removed)
+	return changed, w.constraints, removed
+}
+
+// update replaces the results of files with a new analysis, and drops the
+// results of removed.
+func (w *watcher) update(files []string, constraints map[string]string, removed []string) {
+	results, errs := w.analyze(files, constraints)
+	for i, filename := range files {
+		w.results[filename] = results[i]
+		w.failed[filename] = errs[i] != nil
+		if errs[i] != nil {
+			log.Printf("Error analyzing %s: %v", filename, errs[i])
+		}
+	}
+	for _, filename := range removed {
+		delete(w.results, filename)
+		delete(w.failed, filename)
+	}
+}
+
+// report prints the findings of every watched file, replacing the previous
+// report on a terminal, and returns how many there are.
+func (w *watcher) report(analyzed int) int {
+	if w.clear {
+		fmt.Fprint(w.out.out, "\033[H\033[2J")
+	}
+	filenames := make([]string, 0, len(w.results))
+	for filename := range w.results {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	results := make([]fileResult, len(filenames))
+	for i, filename := range filenames {
+		results[i] = w.results[filename]
+	}
+
+	all := collectViolations(results)
+	fmt.Fprintf(w.out.out, "[%s] analyzed %s files, %s findings in %s files\n", time.Now().Format(time.TimeOnly),
+		formatCount(analyzed), formatCount(len(all)), formatCount(len(w.results)))
+	for _, v := range all {
+		w.out.diagnostic(v)
+	}
+	return len(all)
+}
+
+// exitCode returns the exit code of the last analysis of every file, with
+// violations findings.
+func (w *watcher) exitCode(violations int) int {
+	for _, failed := range w.failed {
+		if failed {
+			return exitFailure
+		}
+	}
+	// Smoke test mode: a config that catches nothing is treated as broken
+	if *errorOnNoViolationsFlag && violations == 0 {
+		return exitUsage
+	}
+	if violations > 0 && !*exitZeroFlag {
+		return exitFindings
+	}
+	return 0
+}
+
+// run analyzes all files, then the changed ones until ctx is done, and
+// returns the exit code of the state of the last analysis.
+func (w *watcher) run(ctx context.Context) int {
+	w.stamps = make(map[string]fileStamp)
+	w.results = make(map[string]fileResult)
+	w.failed = make(map[string]bool)
+
+	files, constraints, _ := w.poll(true)
+	w.update(files, constraints, nil)
+	violations := w.report(len(files))
+	scanned := time.Now()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	pending := make(map[string]bool)
+	var removed []string
+	for {
+		select {
+		case <-ctx.Done():
+			return w.exitCode(violations)
+		case <-ticker.C:
+		}
+		rescan := time.Since(scanned) >= watchRescanInterval
+		if rescan {
+			scanned = time.Now()
+		}
+		changed, constraints, gone := w.poll(rescan)
+		for _, filename := range changed {
+			pending[filename] = true
+		}
+		removed = append(removed, gone...)
+		if len(changed) > 0 || len(gone) > 0 || len(pending) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		files := make([]string, 0, len(pending))
+		for filename := range pending {
+			// A file changed and then removed is only removed
+			if _, ok := w.stamps[filename]; ok {
+				files = append(files, filename)
+			}
+		}
+		sort.Strings(files)
+		w.update(files, constraints, removed)
+		violations = w.report(len(files))
+		pending = make(map[string]bool)
+		removed = nil
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, src string) string {
+		t.Helper()
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	a := write("a.go", "package p\n\nvar request int\n")
+	b := write("b.go", "package p\n")
+	generated := write("b.pb.go", "package p\n\nvar request int\n")
+
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}, ExcludeFiles: []string{"*.pb.go"}}
+	files := []string{a, b, generated}
+	scans := 0
+	w := &watcher{
+		config: config,
+		scan: func() ([]string, map[string]string) {
+			scans++
+			return files, nil
+		},
+		analyze: func(files []string, constraints map[string]string) ([]fileResult, []error) {
+			return analyzeFiles(gonamefix.NewAnalyzer(config), config, nil, files, constraints, 1, 0, nil)
+		},
+		stamps:  make(map[string]fileStamp),
+		results: make(map[string]fileResult),
+		failed:  make(map[string]bool),
+	}
+
+	changed, constraints, removed := w.poll(true)
+	if want := []string{a, b}; !slices.Equal(changed, want) || removed != nil {
+		t.Fatalf("first poll() = %q, %q, want %q and nothing removed", changed, removed, want)
+	}
+	w.update(changed, constraints, removed)
+	if n := len(w.results[a].diagnostics); n != 1 {
+		t.Errorf("a.go has %d findings, want 1", n)
+	}
+
+	// The excluded file changes without being polled
+	later := time.Now().Add(time.Second)
+	write("b.go", "package p\n\nvar request, requestCount int\n")
+	write("b.pb.go", "package p\n")
+	for _, filename := range []string{b, generated} {
+		if err := os.Chtimes(filename, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files = []string{b, generated}
+
+	// Without a rescan only the known files are looked at
+	changed, constraints, removed = w.poll(false)
+	if !slices.Equal(changed, []string{b}) || removed != nil {
+		t.Fatalf("poll() without rescan = %q, %q, want b.go changed", changed, removed)
+	}
+	if scans != 1 {
+		t.Errorf("the files were scanned %d times, want 1", scans)
+	}
+	w.update(changed, constraints, removed)
+
+	changed, constraints, removed = w.poll(true)
+	if changed != nil || !slices.Equal(removed, []string{a}) {
+		t.Fatalf("second poll() = %q, %q, want a.go removed", changed, removed)
+	}
+	w.update(changed, constraints, removed)
+	if _, ok := w.results[a]; ok {
+		t.Error("the results of the removed a.go were kept")
+	}
+	if n := len(w.results[b].diagnostics); n != 2 {
+		t.Errorf("b.go has %d findings, want 2", n)
+	}
+	if code := w.exitCode(2); code != exitFindings {
+		t.Errorf("exitCode() = %d, want %d", code, exitFindings)
+	}
+
+	if changed, _, removed := w.poll(true); changed != nil || removed != nil {
+		t.Errorf("poll() without changes = %q, %q", changed, removed)
+	}
+
+	// A known file removed between rescans is found by the next poll
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _, removed := w.poll(false); changed != nil || !slices.Equal(removed, []string{b}) {
+		t.Errorf("poll() after removing b.go = %q, %q, want b.go removed", changed, removed)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// moduleConfigName is the configuration file a module of a go.work workspace
+// may hold for its own files.
+const moduleConfigName = ".gonamefix.yml"
+
+// workspaceModules returns the module directories listed by the use
+// directives of dir/go.work, or false when dir is not a workspace root.
+func workspaceModules(dir string) ([]string, bool, error) {
+	path := filepath.Join(dir, "go.work")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	work, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	modules := make([]string, 0, len(work.Use))
+	for _, use := range work.Use {
+		modDir := use.Path
+		if !filepath.IsAbs(modDir) {
+			modDir = filepath.Join(dir, modDir)
+		}
+		modules = append(modules, filepath.Clean(modDir))
+	}
+	return modules, true, nil
+}
+
+// selectModules keeps the module directories matched by filter. An entry
+// matches a module by its module path, the last element of that path, or its
+// directory relative to the workspace root. An empty filter keeps all.
+func selectModules(root string, modules, filter []string) []string {
+	if len(filter) == 0 {
+		return modules
+	}
+
+	wanted := make(map[string]bool, len(filter))
+	for _, name := range filter {
+		wanted[strings.TrimSpace(name)] = true
+	}
+
+	var selected []string
+	for _, modDir := range modules {
+		names := []string{filepath.Base(modDir)}
+		if rel, err := filepath.Rel(root, modDir); err == nil {
+			names = append(names, filepath.ToSlash(rel))
+		}
+		if data, err := os.ReadFile(filepath.Join(modDir, "go.mod")); err == nil {
+			if modPath := modfile.ModulePath(data); modPath != "" {
+				names = append(names, modPath, modPath[strings.LastIndex(modPath, "/")+1:])
+			}
+		}
+
+		for _, name := range names {
+			if wanted[name] {
+				selected = append(selected, modDir)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// findWorkspaceFiles returns the Go files of every module of the workspace
+// rooted at root that the filter selects. Paths are relative to the working
+// directory, whichever module they come from.
+func findWorkspaceFiles(root string, modules, filter []string, includeVendor bool, excludeDirs []string) ([]string, error) {
+	var files []string
+	for _, modDir := range selectModules(root, modules, filter) {
+		modFiles, err := findGoFiles(modDir, false, includeVendor, excludeDirs)
+		if err != nil {
+			return files, err
+		}
+		for _, file := range modFiles {
+			files = append(files, relativeToWorkingDir(file))
+		}
+	}
+	return files, nil
+}
+
+// moduleConfigFiles returns the configuration files of the workspace modules
+// holding files, by module directory. A module has one when it is in the use
+// directives of the go.work file above it and holds a moduleConfigName file.
+func moduleConfigFiles(files []string) (map[string]string, error) {
+	configs := make(map[string]string)
+	modules := make(map[string]string) // module directory by directory
+	checked := make(map[string]bool)   // module directories looked at
+	for _, filename := range files {
+		dir, err := filepath.Abs(filepath.Dir(filename))
+		if err != nil {
+			return nil, err
+		}
+		modDir, ok := modules[dir]
+		if !ok {
+			modDir = findAbove(dir, "go.mod")
+			modules[dir] = modDir
+		}
+		if modDir == "" || checked[modDir] {
+			continue
+		}
+		checked[modDir] = true
+
+		path := filepath.Join(modDir, moduleConfigName)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		workDir := findAbove(modDir, "go.work")
+		if workDir == "" {
+			continue
+		}
+		used, _, err := workspaceModules(workDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, use := range used {
+			if use == modDir {
+				configs[modDir] = path
+				break
+			}
+		}
+	}
+	return configs, nil
+}
+
+// findAbove returns the nearest directory holding name, from dir up to the
+// root, or "" if there is none.
+func findAbove(dir, name string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// moduleConfig is the configuration of the files of a workspace module with
+// its own configuration file, and the analyzer it makes.
+type moduleConfig struct {
+	config   gonamefix.Config
+	analyzer *analysis.Analyzer
+}
+
+// moduleConfigs are the configurations of the workspace modules of a run, by
+// module directory.
+type moduleConfigs map[string]moduleConfig
+
+// loadModuleConfigs loads the configuration of every workspace module holding
+// files with a configuration file of its own, with load.
+func loadModuleConfigs(files []string, load func(configFile string) (gonamefix.Config, error)) (moduleConfigs, error) {
+	paths, err := moduleConfigFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	modules := make(moduleConfigs, len(paths))
+	for dir, path := range paths {
+		config, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+		modules[dir] = moduleConfig{config: config, analyzer: gonamefix.NewAnalyzer(config)}
+	}
+	return modules, nil
+}
+
+// forFile returns the analyzer and configuration of filename: those of its
+// module, or analyzer and config when its module has none.
+func (m moduleConfigs) forFile(filename string, analyzer *analysis.Analyzer, config gonamefix.Config) (*analysis.Analyzer, gonamefix.Config) {
+	if len(m) == 0 {
+		return analyzer, config
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return analyzer, config
+	}
+	if module, ok := m[findAbove(dir, "go.mod")]; ok {
+		return module.analyzer, module.config
+	}
+	return analyzer, config
+}
+
+// relativeToWorkingDir returns path relative to the working directory when it
+// is below it, and path unchanged otherwise.
+func relativeToWorkingDir(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func TestFindWorkspaceFiles(t *testing.T) {
+	root := filepath.Join("testdata", "workspace")
+
+	modules, isWorkspace, err := workspaceModules(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isWorkspace {
+		t.Fatalf("workspaceModules(%q) did not find go.work", root)
+	}
+
+	tests := []struct {
+		name     string
+		filter   []string
+		expected []string
+	}{
+		{
+			name: "all modules in use directives",
+			expected: []string{
+				"testdata/workspace/api/api.go",
+				"testdata/workspace/tools/tools.go",
+			},
+		},
+		{
+			name:     "filter by module path",
+			filter:   []string{"example.com/ws/tools"},
+			expected: []string{"testdata/workspace/tools/tools.go"},
+		},
+		{
+			name:     "filter by name",
+			filter:   []string{"api"},
+			expected: []string{"testdata/workspace/api/api.go"},
+		},
+		{
+			name:   "modules outside the workspace are never scanned",
+			filter: []string{"legacy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := findWorkspaceFiles(root, modules, tt.filter, false, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, file := range files {
+				got = append(got, filepath.ToSlash(file))
+			}
+			sort.Strings(got)
+
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("findWorkspaceFiles() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWorkspaceModulesWithoutGoWork(t *testing.T) {
+	_, isWorkspace, err := workspaceModules(filepath.Join("testdata", "nested"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isWorkspace {
+		t.Error("testdata/nested has no go.work but was treated as a workspace")
+	}
+}
+
+func TestModuleConfigs(t *testing.T) {
+	root := filepath.Join("testdata", "workspace")
+	files := []string{
+		filepath.Join(root, "api", "api.go"),
+		filepath.Join(root, "legacy", "legacy.go"),
+		filepath.Join(root, "tools", "tools.go"),
+	}
+	config := gonamefix.Config{Check: [][]string{{"request", "req"}}}
+	// The configuration file of a module applies over config
+	load := func(path string) (gonamefix.Config, error) {
+		moduleConfig := config
+		err := readConfigFile(path, &moduleConfig)
+		return moduleConfig, err
+	}
+	modules, err := loadModuleConfigs(files, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, errs := analyzeFiles(gonamefix.NewAnalyzer(config), config, modules, files, nil, 1, 0, nil)
+	// legacy is not in the use directives, so its configuration is ignored
+	want := []string{"req", "req", "rq"}
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if len(result.diagnostics) != 1 || !strings.HasSuffix(result.diagnostics[0].Message, "'"+want[i]+"'") {
+			t.Errorf("%s: findings %v, want one suggesting %s", files[i], result.diagnostics, want[i])
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// commentCategory is the diagnostic category used for words in comments.
+const commentCategory = "comment"
+
+// checkComments reports words in comments that a mapping would replace, such
+// as "the request object" when the code says req. Prose is never rewritten
+// automatically, so the diagnostics carry no fixes. Directives, URLs, code in
+// backticks and the C preamble of cgo files are skipped.
+func checkComments(pass *analysis.Pass, files []*ast.File, patterns []namePattern, caseSensitive bool, allowed map[string]bool) {
+	for _, file := range files {
+		preamble := cgoPreamble(file)
+		for _, group := range file.Comments {
+			if group == preamble {
+				continue
+			}
+			for _, comment := range group.List {
+				if isDirectiveComment(comment.Text) {
+					continue
+				}
+				for _, word := range commentWords(comment.Text) {
+					if allowed[word.text] {
+						continue
+					}
+					suggestedWord, _, ok := suggestName(word.text, patterns, caseSensitive)
+					if !ok {
+						continue
+					}
+					pos := comment.Pos() + token.Pos(word.offset)
+					pass.Report(analysis.Diagnostic{
+						Pos:      pos,
+						End:      pos + token.Pos(len(word.text)),
+						Category: commentCategory,
+						Message:  fmt.Sprintf("comment: suggest replacing '%s' with '%s'", word.text, suggestedWord),
+					})
+				}
+			}
+		}
+	}
+}
+
+// cgoPreamble returns the comment holding the C code of a cgo file, or nil.
+func cgoPreamble(file *ast.File) *ast.CommentGroup {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.ImportSpec)
+			if spec.Path.Value != `"C"` {
+				continue
+			}
+			if spec.Doc != nil {
+				return spec.Doc
+			}
+			return gen.Doc
+		}
+	}
+	return nil
+}
+
+// isDirectiveComment reports whether text is a comment read by tools rather
+// than people: //go:generate, //nolint, //export, //line and the like.
+func isDirectiveComment(text string) bool {
+	body, ok := strings.CutPrefix(text, "//")
+	if !ok {
+		return false
+	}
+	for _, prefix := range []string{"nolint", "export ", "line ", "extern ", " +build"} {
+		if strings.HasPrefix(body, prefix) {
+			return true
+		}
+	}
+	// Directives like //go:embed and //lint:ignore follow the convention
+	// //[a-z0-9]+:[a-z0-9], so //TODO: notes are still checked
+	name, rest, ok := strings.Cut(body, ":")
+	if !ok || name == "" || rest == "" || !isLowerAlnum(rest[:1]) {
+		return false
+	}
+	return isLowerAlnum(name)
+}
+
+func isLowerAlnum(s string) bool {
+	for _, r := range s {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+type commentWord struct {
+	text   string
+	offset int
+}
+
+// commentWords returns the words of a comment with their byte offsets, leaving
+// out code spans in backticks and URLs.
+func commentWords(text string) []commentWord {
+	prose := []byte(text)
+
+	// Blank out code spans, including their backticks
+	inCode := false
+	for i, c := range prose {
+		if c == '`' {
+			inCode = !inCode
+			prose[i] = ' '
+		} else if inCode {
+			prose[i] = ' '
+		}
+	}
+
+	// Blank out URLs, which are matched as whole space separated fields
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+	for start := 0; start < len(prose); {
+		for start < len(prose) && isSpace(prose[start]) {
+			start++
+		}
+		end := start
+		for end < len(prose) && !isSpace(prose[end]) {
+			end++
+		}
+		field := string(prose[start:end])
+		if strings.Contains(field, "://") || strings.HasPrefix(field, "www.") {
+			for i := start; i < end; i++ {
+				prose[i] = ' '
+			}
+		}
+		start = end
+	}
+
+	var words []commentWord
+	s := string(prose)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !unicode.IsLetter(r) {
+			i += size
+			continue
+		}
+		end := i
+		for end < len(s) {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			if !isIdentRune(r) {
+				break
+			}
+			end += size
+		}
+		words = append(words, commentWord{text: s[i:end], offset: i})
+		i = end
+	}
+	return words
+}
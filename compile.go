@@ -0,0 +1,45 @@
+package gonamefix
+
+import "errors"
+
+// compiledConfig holds the matchers compiled from a Config: the exclusion
+// patterns and the name patterns, prioritized. The analyzer compiles them
+// once when it is created instead of for every package or file.
+type compiledConfig struct {
+	exclusions *exclusionMatcher
+	patterns   []namePattern
+	// names holds the identifiers the mappings never flag
+	names *nameExclusions
+	// kinds holds the declaration kinds checked, nil for all
+	kinds map[string]bool
+}
+
+// compileConfig compiles and validates the patterns of config. Invalid ones
+// are reported in the error instead of silently never matching; the result
+// holds the valid ones.
+func compileConfig(config Config) (*compiledConfig, error) {
+	exclusions, excludeErr := compileExclusions(config)
+	patterns, patternErr := configPatterns(config)
+	names, namesErr := compileNameExclusions(config.ExcludeNames)
+	kinds, kindErr := compileKinds(config.OnlyKinds)
+	return &compiledConfig{
+		exclusions: exclusions,
+		patterns:   prioritizePatterns(patterns, config.PriorityPatterns),
+		names:      names,
+		kinds:      kinds,
+	}, errors.Join(excludeErr, patternErr, namesErr, kindErr)
+}
+
+// Validate reports every invalid pattern of c: malformed exclusion globs and
+// name mappings that cannot be compiled. An analyzer created for an invalid
+// configuration fails instead of running without those patterns. It also
+// reports SynonymConsistency without mappings, which would find nothing.
+func (c Config) Validate() error {
+	_, err := compileConfig(c)
+	// The canonical names are the replacements of the mappings, which may
+	// also come from CheckURL
+	if c.SynonymConsistency && len(c.Check) == 0 && len(c.Mappings) == 0 && c.CheckURL == "" {
+		err = errors.Join(err, errors.New("synonym-consistency needs check or mappings, whose replacements are the canonical names"))
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/build/constraint"
+)
+
+// HasExcludedBuildConstraint reports whether the //go:build line of file
+// references one of the given tags outside of a negation. A file guarded by
+// "//go:build ignore" or "//go:build tools && linux" is matched by the tags
+// "ignore" and "tools" respectively.
+func HasExcludedBuildConstraint(file *ast.File, tags []string) bool {
+	if len(tags) == 0 {
+		return false
+	}
+
+	excluded := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		excluded[tag] = true
+	}
+
+	expr := goBuildExpr(file)
+	return expr != nil && referencesTag(expr, excluded)
+}
+
+// BuildConstraint returns the expression of the //go:build line of file, such
+// as "windows && integration", or "" when the file has none.
+func BuildConstraint(file *ast.File) string {
+	if expr := goBuildExpr(file); expr != nil {
+		return expr.String()
+	}
+	return ""
+}
+
+// goBuildExpr returns the parsed //go:build line of file, or nil.
+func goBuildExpr(file *ast.File) constraint.Expr {
+	for _, group := range file.Comments {
+		// Build constraints must appear before the package clause
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) {
+				continue
+			}
+			if expr, err := constraint.Parse(comment.Text); err == nil {
+				return expr
+			}
+		}
+	}
+	return nil
+}
+
+func referencesTag(expr constraint.Expr, tags map[string]bool) bool {
+	switch e := expr.(type) {
+	case *constraint.TagExpr:
+		return tags[e.Tag]
+	case *constraint.AndExpr:
+		return referencesTag(e.X, tags) || referencesTag(e.Y, tags)
+	case *constraint.OrExpr:
+		return referencesTag(e.X, tags) || referencesTag(e.Y, tags)
+	default:
+		// Negated tags do not guard the file behind the tag
+		return false
+	}
+}
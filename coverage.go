@@ -0,0 +1,117 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// PatternCoverage reports how many declared identifiers in files each
+// configured mapping matches, keyed by the mapping's original. Every pattern
+// is tested against every identifier, so an identifier matched by several
+// patterns counts for each of them. Mappings that match nothing are present
+// with a count of zero. The identifiers are those the analyzer checks:
+// excluded files and declaration kinds, allowed names, the standard library
+// names of GoStdlibAllowedNames and excluded names are skipped, and mappings
+// restricted to declaration kinds only count those. Without type information
+// the types of a mapping are not looked at. An invalid configuration is
+// reported as an error.
+func PatternCoverage(files []string, config Config) (map[string]int, error) {
+	compiled, err := compileConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	patterns := compiled.patterns
+
+	coverage := make(map[string]int, len(patterns))
+	for _, pattern := range patterns {
+		coverage[pattern.original] = 0
+	}
+
+	allowed := allowedNames(config)
+	count := func(ident *ast.Ident, kind string) {
+		if ident.Name == "_" || isGoKeyword(ident.Name) || allowed[ident.Name] || compiled.names.match(ident.Name) {
+			return
+		}
+		for _, pattern := range patterns {
+			if pattern.kinds != nil && !pattern.kinds[kind] {
+				continue
+			}
+			if replaceInName(ident.Name, pattern.original, pattern.replacement, config.CaseSensitive) != ident.Name {
+				coverage[pattern.original]++
+			}
+		}
+	}
+
+	for _, filename := range files {
+		// Excluded files are not even parsed
+		if _, excluded := compiled.exclusions.match(filename); excluded {
+			continue
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", filename, err)
+		}
+		pass := &analysis.Pass{Fset: fset, Files: []*ast.File{file}}
+		_, skipped := filterFiles(pass, config, compiled)
+		walkDeclarations(pass, inspector.New(pass.Files), skipped, config.CheckLoopVars, compiled.kinds, nil, count, count, nil)
+	}
+	return coverage, nil
+}
+
+// declaredIdents returns the identifiers declared in file, the same set of
+// declarations the analyzer checks.
+func declaredIdents(file *ast.File) []*ast.Ident {
+	var idents []*ast.Ident
+	add := func(ident *ast.Ident) {
+		if ident != nil && ident.Name != "_" {
+			idents = append(idents, ident)
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			add(n.Name)
+		case *ast.TypeSpec:
+			add(n.Name)
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				add(name)
+			}
+		case *ast.Field:
+			for _, name := range n.Names {
+				add(name)
+			}
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				return true
+			}
+			for _, lhs := range n.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				//nolint:staticcheck // ast.Object is the only resolution available without type information
+				if ok && ident.Obj != nil && ident.Obj.Decl == n {
+					add(ident)
+				}
+			}
+		case *ast.RangeStmt:
+			if n.Tok != token.DEFINE {
+				return true
+			}
+			if ident, ok := n.Key.(*ast.Ident); ok {
+				add(ident)
+			}
+			if ident, ok := n.Value.(*ast.Ident); ok {
+				add(ident)
+			}
+		}
+		return true
+	})
+	return idents
+}
@@ -0,0 +1,191 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// declarationNodes are the nodes walkDeclarations needs to see, with the
+// declaration kinds they are needed for. Files are always needed; the
+// statements declaring local variables only for local declarations.
+var declarationNodes = []struct {
+	node  ast.Node
+	kinds []string
+	local bool
+}{
+	{(*ast.File)(nil), nil, false},
+	{(*ast.FuncDecl)(nil), []string{KindFunc, KindMethod, KindParam, KindResult}, false},
+	{(*ast.TypeSpec)(nil), []string{KindType}, false},
+	{(*ast.ValueSpec)(nil), []string{KindVar, KindConst}, false},
+	{(*ast.StructType)(nil), []string{KindField}, false},
+	{(*ast.InterfaceType)(nil), []string{KindMethod}, false},
+	{(*ast.FuncType)(nil), []string{KindParam, KindResult}, false},
+	{(*ast.Field)(nil), []string{KindField, KindMethod, KindParam, KindResult}, false},
+	{(*ast.AssignStmt)(nil), []string{KindVar}, true},
+	{(*ast.ForStmt)(nil), []string{KindVar}, true},
+	{(*ast.RangeStmt)(nil), []string{KindVar}, true},
+}
+
+// declarationNodesFor returns the nodes walkDeclarations needs to see for
+// the declaration kinds in kinds, or for all kinds if kinds is nil, and for
+// the short variable declarations and range variables if locals is set.
+func declarationNodesFor(kinds map[string]bool, locals bool) []ast.Node {
+	var nodes []ast.Node
+	for _, decl := range declarationNodes {
+		if decl.local && !locals {
+			continue
+		}
+		needed := kinds == nil || decl.kinds == nil
+		for _, kind := range decl.kinds {
+			needed = needed || kinds[kind]
+		}
+		if needed {
+			nodes = append(nodes, decl.node)
+		}
+	}
+	return nodes
+}
+
+// filterFiles returns the files of pass that are not excluded by name, by
+// build constraints, for using cgo or for being generated, and the set of
+// the skipped ones.
+func filterFiles(pass *analysis.Pass, config Config, compiled *compiledConfig) ([]*ast.File, map[*ast.File]bool) {
+	var files []*ast.File
+	skipped := make(map[*ast.File]bool)
+	for _, file := range pass.Files {
+		filename := pass.Fset.Position(file.Pos()).Filename
+		if _, excluded := compiled.exclusions.match(filename); excluded || HasExcludedBuildConstraint(file, config.ExcludeBuildConstraints) ||
+			config.SkipCgo && isCgoFile(file) || !config.IncludeGenerated && IsGenerated(file) {
+			skipped[file] = true
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, skipped
+}
+
+// walkDeclarations calls declare, in source order, for every identifier
+// declared in the files of pass that are not skipped, with its declaration
+// kind. Only the kinds in kinds are declared, all if it is nil, and only the
+// nodes they need are walked. Variables declared by short variable
+// declarations and range clauses are passed to declareLocal instead, and
+// not walked at all if it is nil. Names exported to C and short loop
+// variables, unless checkLoopVars is set, are not declared. The walk also
+// visits the node types in extra; visit is called for every visited node but
+// files, with the file it is in.
+func walkDeclarations(pass *analysis.Pass, ins *inspector.Inspector, skipped map[*ast.File]bool, checkLoopVars bool,
+	kinds map[string]bool, extra []ast.Node, declare, declareLocal func(ident *ast.Ident, kind string), visit func(n ast.Node, file *ast.File),
+) {
+	nodeFilter := append(declarationNodesFor(kinds, declareLocal != nil), extra...)
+
+	// Names can be reached twice, e.g. parameters as names of their function
+	// and as fields, and some are excluded before they are reached
+	seen := make(map[*ast.Ident]bool)
+	emit := func(ident *ast.Ident, kind string) {
+		if ident == nil || seen[ident] || kinds != nil && !kinds[kind] {
+			return
+		}
+		seen[ident] = true
+		declare(ident, kind)
+	}
+	emitLocal := func(ident *ast.Ident) {
+		if seen[ident] || kinds != nil && !kinds[KindVar] {
+			return
+		}
+		seen[ident] = true
+		declareLocal(ident, KindVar)
+	}
+	// fieldKinds holds the declaration kind of field names, recorded when
+	// their struct, interface or function type is visited
+	fieldKinds := make(map[*ast.Ident]string)
+
+	// Files are visited before their declarations, so current tracks the file being walked
+	var current *ast.File
+	ins.Preorder(nodeFilter, func(n ast.Node) {
+		if file, ok := n.(*ast.File); ok {
+			current = file
+			return
+		}
+		if skipped[current] {
+			return
+		}
+
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			// Names exported to C are fixed by the C side
+			if hasExportDirective(node) {
+				seen[node.Name] = true
+			}
+			// The receiver is visited next, as a field
+			recordKinds(fieldKinds, node.Recv, KindParam)
+			if node.Recv != nil {
+				emit(node.Name, KindMethod)
+			} else {
+				emit(node.Name, KindFunc)
+			}
+			if node.Type != nil && node.Type.Params != nil {
+				for _, param := range node.Type.Params.List {
+					for _, name := range param.Names {
+						emit(name, KindParam)
+					}
+				}
+			}
+			if node.Type != nil && node.Type.Results != nil {
+				for _, result := range node.Type.Results.List {
+					for _, name := range result.Names {
+						emit(name, KindResult)
+					}
+				}
+			}
+		case *ast.TypeSpec:
+			emit(node.Name, KindType)
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				emit(name, valueKind(pass, name))
+			}
+		case *ast.StructType:
+			recordKinds(fieldKinds, node.Fields, KindField)
+		case *ast.InterfaceType:
+			recordKinds(fieldKinds, node.Methods, KindMethod)
+		case *ast.FuncType:
+			recordKinds(fieldKinds, node.Params, KindParam)
+			recordKinds(fieldKinds, node.Results, KindResult)
+		case *ast.Field:
+			for _, name := range node.Names {
+				emit(name, fieldKinds[name])
+			}
+		case *ast.AssignStmt:
+			// Short variable declarations; names that are only reassigned are skipped
+			if node.Tok == token.DEFINE {
+				for _, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && isDefinition(pass, ident, node) {
+						emitLocal(ident)
+					}
+				}
+			}
+		case *ast.ForStmt:
+			// Loop variables are visited before the init statement declaring them
+			if init, ok := node.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE && !checkLoopVars {
+				for _, lhs := range init.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && isLoopVarName(ident.Name) {
+						seen[ident] = true
+					}
+				}
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.DEFINE {
+				for _, expr := range []ast.Expr{node.Key, node.Value} {
+					if ident, ok := expr.(*ast.Ident); ok && (checkLoopVars || !isLoopVarName(ident.Name)) {
+						emitLocal(ident)
+					}
+				}
+			}
+		}
+		if visit != nil {
+			visit(n, current)
+		}
+	})
+}
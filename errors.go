@@ -0,0 +1,250 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// errorNameCategory is the diagnostic category used by the error naming rule.
+const errorNameCategory = "error-naming"
+
+// errorConstructors are the calls recognized as producing an error when no
+// type information is available.
+var errorConstructors = map[string]bool{
+	"errors.New":  true,
+	"errors.Join": true,
+	"fmt.Errorf":  true,
+}
+
+// checkErrorNames reports error variables that do not follow the ErrXxx
+// convention for package level variables, or err/errXxx for local ones.
+func checkErrorNames(pass *analysis.Pass, files []*ast.File) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					checkErrorValueSpec(pass, file, spec.(*ast.ValueSpec), true)
+				}
+			case *ast.FuncDecl:
+				if decl.Body == nil {
+					continue
+				}
+				ast.Inspect(decl.Body, func(n ast.Node) bool {
+					switch n := n.(type) {
+					case *ast.ValueSpec:
+						checkErrorValueSpec(pass, file, n, false)
+					case *ast.AssignStmt:
+						if n.Tok != token.DEFINE {
+							return true
+						}
+						for i, lhs := range n.Lhs {
+							ident, ok := lhs.(*ast.Ident)
+							if !ok || !isDefinition(pass, ident, n) {
+								continue
+							}
+							var value ast.Expr
+							if len(n.Lhs) == len(n.Rhs) {
+								value = n.Rhs[i]
+							}
+							if isErrorVar(pass, ident, nil, value) {
+								checkErrorName(pass, file, ident, false)
+							}
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+func checkErrorValueSpec(pass *analysis.Pass, file *ast.File, spec *ast.ValueSpec, packageLevel bool) {
+	for i, ident := range spec.Names {
+		var value ast.Expr
+		if len(spec.Names) == len(spec.Values) {
+			value = spec.Values[i]
+		}
+		if ident.Name != "_" && isErrorVar(pass, ident, spec.Type, value) {
+			checkErrorName(pass, file, ident, packageLevel)
+		}
+	}
+}
+
+// isErrorVar reports whether ident declares a variable of type error. Without
+// type information only an explicit error type or a call to a well-known
+// error constructor is recognized.
+func isErrorVar(pass *analysis.Pass, ident *ast.Ident, typ, value ast.Expr) bool {
+	if pass.TypesInfo != nil {
+		obj, ok := pass.TypesInfo.Defs[ident].(*types.Var)
+		return ok && types.Identical(obj.Type(), types.Universe.Lookup("error").Type())
+	}
+
+	if typ != nil {
+		name, ok := typ.(*ast.Ident)
+		return ok && name.Name == "error"
+	}
+	call, ok := value.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && errorConstructors[pkg.Name+"."+sel.Sel.Name]
+}
+
+// isErrorName reports whether name already follows the error naming
+// convention: ErrXxx/errXxx, plus err and errN for local variables.
+func isErrorName(name string, packageLevel bool) bool {
+	for _, prefix := range []string{"Err", "err"} {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		if rest == "" {
+			return prefix == "err"
+		}
+		first, _ := utf8.DecodeRuneInString(rest)
+		if unicode.IsUpper(first) || (!packageLevel && isDigits(rest)) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorName suggests a conventional name for the error variable name, e.g.
+// FooError -> ErrFoo, parseErr -> errParse, e -> err and error1 -> err1.
+// Package level names are only suggested when something is left to name.
+func errorName(name string, exported, packageLevel bool) (string, bool) {
+	base := name
+	for _, prefix := range []string{"error", "Error", "err", "Err"} {
+		if trimmed, ok := strings.CutPrefix(base, prefix); ok {
+			base = trimmed
+			break
+		}
+	}
+	for _, suffix := range []string{"Error", "Err"} {
+		if trimmed, ok := strings.CutSuffix(base, suffix); ok {
+			base = trimmed
+			break
+		}
+	}
+	if strings.EqualFold(base, "e") {
+		base = ""
+	}
+
+	prefix := "err"
+	if exported {
+		prefix = "Err"
+	}
+	switch {
+	case base == "" || isDigits(base):
+		if packageLevel {
+			return "", false
+		}
+		return "err" + base, true
+	default:
+		first, size := utf8.DecodeRuneInString(base)
+		return prefix + string(unicode.ToUpper(first)) + base[size:], true
+	}
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+func checkErrorName(pass *analysis.Pass, file *ast.File, ident *ast.Ident, packageLevel bool) {
+	if isErrorName(ident.Name, packageLevel) {
+		return
+	}
+
+	exported := packageLevel && ident.IsExported()
+	suggestedName, ok := errorName(ident.Name, exported, packageLevel)
+	if !ok {
+		convention := "errXxx"
+		if exported {
+			convention = "ErrXxx"
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      ident.Pos(),
+			End:      ident.End(),
+			Category: errorNameCategory,
+			Message:  fmt.Sprintf("error variable '%s' should be named %s", ident.Name, convention),
+		})
+		return
+	}
+
+	diagnostic := analysis.Diagnostic{
+		Pos:      ident.Pos(),
+		End:      ident.End(),
+		Category: errorNameCategory,
+		Message:  fmt.Sprintf("error variable '%s' should be named '%s'", ident.Name, suggestedName),
+	}
+	if edits := variableRenameEdits(pass, file, ident, suggestedName); edits != nil {
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Rename '%s' to '%s'", ident.Name, suggestedName),
+			TextEdits: edits,
+		}}
+	}
+	pass.Report(diagnostic)
+}
+
+// variableRenameEdits returns edits renaming the variable declared by decl and
+// its uses, or nil when newName is already taken. Without type information
+// uses are only resolved within file, and any identifier in file named newName
+// counts as taken. With it, newName must not be visible at any use.
+func variableRenameEdits(pass *analysis.Pass, file *ast.File, decl *ast.Ident, newName string) []analysis.TextEdit {
+	files := []*ast.File{file}
+	if pass.TypesInfo != nil {
+		obj := pass.TypesInfo.Defs[decl]
+		if obj == nil || obj.Parent() == nil {
+			return nil
+		}
+		if _, taken := obj.Parent().LookupParent(newName, decl.Pos()); taken != nil {
+			return nil
+		}
+		files = pass.Files
+	}
+
+	edits := []analysis.TextEdit{{Pos: decl.Pos(), End: decl.End(), NewText: []byte(newName)}}
+	conflict := false
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok || ident == decl {
+				return true
+			}
+			switch {
+			case sameObject(pass, ident, decl):
+				if pass.Pkg != nil {
+					if scope := pass.Pkg.Scope().Innermost(ident.Pos()); scope != nil {
+						if _, shadow := scope.LookupParent(newName, ident.Pos()); shadow != nil {
+							conflict = true
+						}
+					}
+				}
+				edits = append(edits, analysis.TextEdit{Pos: ident.Pos(), End: ident.End(), NewText: []byte(newName)})
+			case pass.TypesInfo == nil && ident.Name == newName:
+				conflict = true
+			}
+			return true
+		})
+	}
+	if conflict {
+		return nil
+	}
+	return edits
+}
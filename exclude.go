@@ -0,0 +1,292 @@
+package gonamefix
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ExcludeRule is a structured exclusion entry with an optional explanation.
+// Patterns containing a slash are matched against the whole path and may use
+// "**" to match any number of directories; other patterns match the base name.
+type ExcludeRule struct {
+	// Pattern is the glob the path is matched against
+	Pattern string `mapstructure:"pattern"`
+	// Reason documents why matching paths are excluded
+	Reason string `mapstructure:"reason"`
+}
+
+// Exclusion describes the rule that caused a path to be skipped.
+type Exclusion struct {
+	// Source names the config option the rule came from: exclude-files,
+	// exclude-files-regex, exclude-dirs or exclude
+	Source string
+	// Pattern is the pattern that matched
+	Pattern string
+	// Reason is the documented reason, if any
+	Reason string
+}
+
+// String renders the exclusion in a human readable form.
+func (e Exclusion) String() string {
+	msg := "excluded by " + e.Source + " pattern '" + e.Pattern + "'"
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	return msg
+}
+
+// SkipVendor reports whether path, a file or directory, lies in a vendor
+// directory and vendored code is not included. It is the only place deciding
+// about vendored code, for the analyzer as well as the command's directory
+// walker.
+func SkipVendor(path string, includeVendor bool) bool {
+	if includeVendor {
+		return false
+	}
+	for _, segment := range strings.Split(filepath.ToSlash(path), "/") {
+		if segment == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipExcludedDir reports whether every file below dir is excluded by one of
+// the exclude-dirs patterns, so that the command's directory walker need not
+// enter it. Patterns matching only part of a file name do not skip the
+// directory; the analyzer still excludes those files.
+func SkipExcludedDir(dir string, excludeDirs []string) bool {
+	_, skip := MatchExcludedDir(dir, excludeDirs)
+	return skip
+}
+
+// MatchExcludedDir returns the exclude-dirs pattern for which
+// SkipExcludedDir skips dir. The boolean result is false when dir is entered.
+func MatchExcludedDir(dir string, excludeDirs []string) (string, bool) {
+	prefix := dir + string(filepath.Separator)
+	for _, pattern := range excludeDirs {
+		if pattern != "" && pattern != "vendor" && strings.Contains(prefix, pattern) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// exclusionMatcher holds the exclusion patterns of a Config, validated and
+// split up once so that matching a file does no parsing of patterns.
+type exclusionMatcher struct {
+	includeVendor bool
+	files         []string
+	fileRegexps   []*regexp.Regexp
+	dirs          []string
+	rules         []pathRule
+}
+
+// pathRule is an ExcludeRule with its pattern split into path segments.
+// Patterns without a slash have no segments and match the base name.
+type pathRule struct {
+	ExcludeRule
+	segments []string
+}
+
+// testFilePattern is the exclude-files pattern of test files, which
+// IncludeTests drops.
+const testFilePattern = "*_test.go"
+
+// compileExclusions validates the exclusion patterns of config. Invalid
+// patterns are left out of the matcher and reported together in the error.
+// Empty entries, as left by splitting an empty flag value, are ignored.
+func compileExclusions(config Config) (*exclusionMatcher, error) {
+	m := &exclusionMatcher{includeVendor: config.IncludeVendor}
+	var errs []error
+
+	for _, pattern := range config.ExcludeFiles {
+		// include-tests wins over a "*_test.go" entry, default or given, so the
+		// other patterns need not be repeated without it
+		if pattern == "" || config.IncludeTests && pattern == testFilePattern {
+			continue
+		}
+		if err := validateGlob(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("exclude-files pattern %q: %w", pattern, err))
+			continue
+		}
+		m.files = append(m.files, pattern)
+	}
+
+	for _, expr := range config.ExcludeFilesRegex {
+		if expr == "" {
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("exclude-files-regex %q: %w", expr, err))
+			continue
+		}
+		m.fileRegexps = append(m.fileRegexps, re)
+	}
+
+	for _, pattern := range config.ExcludeDirs {
+		// Vendored code is decided by SkipVendor, so include-vendor wins over a "vendor" entry
+		if pattern == "" || pattern == "vendor" {
+			continue
+		}
+		m.dirs = append(m.dirs, pattern)
+	}
+
+	for _, rule := range config.Exclude {
+		if rule.Pattern == "" {
+			continue
+		}
+		compiled := pathRule{ExcludeRule: rule}
+		if strings.Contains(rule.Pattern, "/") {
+			compiled.segments = strings.Split(strings.TrimPrefix(rule.Pattern, "/"), "/")
+			if !strings.HasPrefix(rule.Pattern, "/") && compiled.segments[0] != "**" {
+				// Unanchored patterns may match at any directory depth
+				compiled.segments = append([]string{"**"}, compiled.segments...)
+			}
+		}
+		if err := validateGlob(rule.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("exclude pattern %q: %w", rule.Pattern, err))
+			continue
+		}
+		m.rules = append(m.rules, compiled)
+	}
+
+	return m, errors.Join(errs...)
+}
+
+// validateGlob reports whether pattern is a well-formed filepath.Match
+// pattern. A "]" without an opening "[" is rejected too: filepath.Match
+// takes it literally, but it is almost always a typo and would silently
+// stop the pattern from matching.
+func validateGlob(pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return err
+	}
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			if !inClass {
+				return errors.New("unmatched ']'")
+			}
+			inClass = false
+		}
+	}
+	return nil
+}
+
+// match returns the first rule that excludes filename.
+func (m *exclusionMatcher) match(filename string) (Exclusion, bool) {
+	if SkipVendor(filename, m.includeVendor) {
+		return Exclusion{Source: "vendor", Pattern: "vendor/", Reason: "vendored code is skipped unless include-vendor is set"}, true
+	}
+
+	base := filepath.Base(filename)
+	for _, pattern := range m.files {
+		if matched, _ := filepath.Match(pattern, base); matched {
+			return Exclusion{Source: "exclude-files", Pattern: pattern}, true
+		}
+	}
+
+	var slashed string
+	if len(m.fileRegexps) > 0 {
+		slashed = filepath.ToSlash(moduleRelative(filename))
+	}
+	for _, re := range m.fileRegexps {
+		if re.MatchString(slashed) {
+			return Exclusion{Source: "exclude-files-regex", Pattern: re.String()}, true
+		}
+	}
+
+	for _, pattern := range m.dirs {
+		if strings.Contains(filename, pattern) {
+			return Exclusion{Source: "exclude-dirs", Pattern: pattern}, true
+		}
+	}
+
+	var pathParts []string
+	for _, rule := range m.rules {
+		if rule.segments == nil {
+			if matched, _ := filepath.Match(rule.Pattern, base); matched {
+				return Exclusion{Source: "exclude", Pattern: rule.Pattern, Reason: rule.Reason}, true
+			}
+			continue
+		}
+		if pathParts == nil {
+			pathParts = strings.Split(strings.TrimPrefix(filepath.ToSlash(filename), "/"), "/")
+		}
+		if matchSegments(rule.segments, pathParts) {
+			return Exclusion{Source: "exclude", Pattern: rule.Pattern, Reason: rule.Reason}, true
+		}
+	}
+
+	return Exclusion{}, false
+}
+
+// moduleRelative returns filename relative to the root of the module
+// containing it, the nearest directory above it with a go.mod, so that
+// exclude-files-regex expressions match the same path whether the file is
+// named by an absolute path, as by golangci-lint, or from the module root.
+// Relative paths, and files outside of any module, are returned as given.
+func moduleRelative(filename string) string {
+	if !filepath.IsAbs(filename) {
+		return filename
+	}
+	for dir := filepath.Dir(filename); ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+			if rel, err := filepath.Rel(dir, filename); err == nil {
+				return rel
+			}
+			return filename
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return filename
+		}
+	}
+}
+
+// MatchExclusion returns the first rule in config that excludes filename.
+// The boolean result is false when the file would be analyzed. Invalid
+// patterns, which Config.Validate reports, never match.
+func MatchExclusion(filename string, config Config) (Exclusion, bool) {
+	m, _ := compileExclusions(config)
+	return m.match(filename)
+}
+
+func shouldExcludeFile(filename string, config Config) bool {
+	_, excluded := MatchExclusion(filename, config)
+	return excluded
+}
+
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// "**" consumes zero or more path segments
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(path) == 0 {
+			return false
+		}
+		if matched, _ := filepath.Match(pattern[0], path[0]); !matched {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+
+	return len(path) == 0
+}
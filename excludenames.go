@@ -0,0 +1,53 @@
+package gonamefix
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// nameExclusions matches the identifiers of Config.ExcludeNames, which the
+// mappings never flag: exact names and globs such as *Service.
+type nameExclusions struct {
+	exact map[string]bool
+	globs []string
+}
+
+// compileNameExclusions compiles names, reporting malformed globs. A nil
+// result excludes nothing.
+func compileNameExclusions(names []string) (*nameExclusions, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	exclusions := &nameExclusions{exact: make(map[string]bool)}
+	var errs []error
+	for _, name := range names {
+		if !strings.ContainsAny(name, "*?[") {
+			exclusions.exact[name] = true
+			continue
+		}
+		if err := validateGlob(name); err != nil {
+			errs = append(errs, fmt.Errorf("exclude-names %q: %w", name, err))
+			continue
+		}
+		exclusions.globs = append(exclusions.globs, name)
+	}
+	return exclusions, errors.Join(errs...)
+}
+
+// match reports whether name is excluded.
+func (e *nameExclusions) match(name string) bool {
+	if e == nil {
+		return false
+	}
+	if e.exact[name] {
+		return true
+	}
+	for _, glob := range e.globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
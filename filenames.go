@@ -0,0 +1,89 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// fileNamePrefix starts the message of the findings of Config.CheckFilenames.
+const fileNamePrefix = "suggest renaming file "
+
+// checkFileNames reports the files whose base name the mappings would
+// change, at their package clause. Files cannot be renamed by a text edit, so
+// the diagnostics carry no fixes.
+func checkFileNames(pass *analysis.Pass, files []*ast.File, patterns []namePattern, caseSensitive bool) {
+	for _, file := range files {
+		base := filepath.Base(pass.Fset.Position(file.Package).Filename)
+		suggested, pattern, ok := suggestFileName(base, patterns, caseSensitive)
+		if !ok {
+			continue
+		}
+		message := fmt.Sprintf("%s'%s' to '%s'", fileNamePrefix, base, suggested)
+		pass.Report(analysis.Diagnostic{
+			Pos:      file.Package,
+			End:      file.Package + token.Pos(len("package")),
+			Category: pattern.id,
+			URL:      pattern.url,
+			Message:  withURL(message, pattern.url),
+		})
+	}
+}
+
+// suggestFileName applies patterns to base, the name of a Go file, and
+// returns the suggested name with the first pattern that changed it. The
+// parts between underscores are matched like identifiers, so camelCase words
+// in them are found too; names without the .go extension are left alone.
+func suggestFileName(base string, patterns []namePattern, caseSensitive bool) (string, namePattern, bool) {
+	stem, ok := strings.CutSuffix(base, ".go")
+	if !ok || stem == "" {
+		return "", namePattern{}, false
+	}
+	parts := strings.Split(stem, "_")
+	var first namePattern
+	changed := false
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		suggested, pattern, ok := suggestName(part, patterns, caseSensitive)
+		if !ok || suggested == "" {
+			continue
+		}
+		if !changed {
+			first, changed = pattern, true
+		}
+		parts[i] = suggested
+	}
+	if !changed {
+		return "", namePattern{}, false
+	}
+	return strings.Join(parts, "_") + ".go", first, true
+}
+
+// SuggestFileName returns the path the mappings of config suggest for the Go
+// file filename with Config.CheckFilenames, e.g. req_handler.go for
+// request_handler.go in the same directory, and whether they change it.
+// Mappings restricted to types or declaration kinds do not apply to file
+// names.
+func SuggestFileName(filename string, config Config) (string, bool) {
+	compiled, err := compileConfig(config)
+	if err != nil {
+		return "", false
+	}
+	suggested, _, ok := suggestFileName(filepath.Base(filename), patternsFor(compiled.patterns, nil, ""), config.CaseSensitive)
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(filepath.Dir(filename), suggested), true
+}
+
+// IsFileNameFinding reports whether d is a finding of Config.CheckFilenames
+// rather than of an identifier.
+func IsFileNameFinding(d analysis.Diagnostic) bool {
+	return strings.HasPrefix(d.Message, fileNamePrefix)
+}
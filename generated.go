@@ -0,0 +1,22 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// IsGenerated reports whether file carries the "// Code generated ... DO NOT
+// EDIT." comment before its package clause, as described at
+// https://go.dev/s/generatedcode. Only the comments of the file are needed,
+// as parsed with parser.ParseComments.
+func IsGenerated(file *ast.File) bool {
+	return ast.IsGenerated(file)
+}
+
+// isGeneratedSource is IsGenerated for the source of a file that does not
+// parse: only its package clause and the comments before it are parsed.
+func isGeneratedSource(src []byte) bool {
+	file, _ := parser.ParseFile(token.NewFileSet(), "", src, parser.ParseComments|parser.PackageClauseOnly)
+	return file != nil && ast.IsGenerated(file)
+}
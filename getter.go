@@ -0,0 +1,206 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// getterCategory is the diagnostic category used by the getter naming rule.
+const getterCategory = "getter"
+
+// getterExceptions are Get-prefixed names that are not plain getters.
+var getterExceptions = map[string]bool{
+	"GetAll": true,
+}
+
+// getterName returns the name a GetXxx method should have, or false when name
+// is not a Get-prefixed getter name.
+func getterName(name string) (string, bool) {
+	rest := strings.TrimPrefix(name, "Get")
+	if rest == name || rest == "" || getterExceptions[name] || isGetOrX(rest) {
+		return "", false
+	}
+	first, _ := utf8.DecodeRuneInString(rest)
+	if !unicode.IsUpper(first) {
+		// Getter, Gets... are ordinary words
+		return "", false
+	}
+	return rest, true
+}
+
+// isGetOrX reports whether rest, a name without its Get prefix, makes it a
+// GetOrX name such as GetOrCreate, which does more than get. Words starting
+// with Or, as in GetOrder, are plain getters.
+func isGetOrX(rest string) bool {
+	after, ok := strings.CutPrefix(rest, "Or")
+	if !ok || after == "" {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(after)
+	return unicode.IsUpper(first)
+}
+
+// checkGetters reports methods named GetXxx that take no parameters and
+// return a single result, suggesting Xxx.
+func checkGetters(pass *analysis.Pass, files []*ast.File) {
+	members := declaredMembers(files)
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+			if fn.Type.Params.NumFields() != 0 || fn.Type.Results.NumFields() != 1 {
+				continue
+			}
+			suggestedName, ok := getterName(fn.Name.Name)
+			if !ok {
+				continue
+			}
+
+			typeName := receiverTypeName(fn.Recv.List[0].Type)
+			if hasMember(pass, fn, typeName, suggestedName, members) || implementsImportedInterface(pass, fn) {
+				continue
+			}
+
+			diagnostic := analysis.Diagnostic{
+				Pos:      fn.Name.Pos(),
+				End:      fn.Name.End(),
+				Category: getterCategory,
+				Message:  fmt.Sprintf("getter '%s' should be named '%s'", fn.Name.Name, suggestedName),
+			}
+			if edits := methodRenameEdits(pass, fn, suggestedName); edits != nil {
+				diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   fmt.Sprintf("Rename '%s' to '%s'", fn.Name.Name, suggestedName),
+					TextEdits: edits,
+				}}
+			}
+			pass.Report(diagnostic)
+		}
+	}
+}
+
+// declaredMembers collects the field and method names declared in files per
+// type name. It backs the conflict check when no type information is available.
+func declaredMembers(files []*ast.File) map[string]map[string]bool {
+	members := make(map[string]map[string]bool)
+	add := func(typeName, member string) {
+		if members[typeName] == nil {
+			members[typeName] = make(map[string]bool)
+		}
+		members[typeName][member] = true
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv != nil && len(decl.Recv.List) == 1 {
+					add(receiverTypeName(decl.Recv.List[0].Type), decl.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					for _, field := range structType.Fields.List {
+						for _, name := range field.Names {
+							add(typeSpec.Name.Name, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return members
+}
+
+// hasMember reports whether the receiver type of fn already has a field or
+// method called name, which is usually why the Get prefix was chosen.
+func hasMember(pass *analysis.Pass, fn *ast.FuncDecl, typeName, name string, members map[string]map[string]bool) bool {
+	if pass.TypesInfo != nil && pass.Pkg != nil {
+		if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+			recv := obj.Type().(*types.Signature).Recv()
+			member, _, _ := types.LookupFieldOrMethod(recv.Type(), true, pass.Pkg, name)
+			return member != nil
+		}
+	}
+	return members[typeName][name]
+}
+
+// implementsImportedInterface reports whether fn is required by an interface
+// declared in an imported package that its receiver type implements. Such
+// methods cannot be renamed. Without type information nothing is known.
+func implementsImportedInterface(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	if pass.TypesInfo == nil || pass.Pkg == nil {
+		return false
+	}
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := obj.Type().(*types.Signature).Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+
+	for _, imported := range pass.Pkg.Imports() {
+		scope := imported.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !typeName.Exported() {
+				continue
+			}
+			iface, ok := typeName.Type().Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			if method, _, _ := types.LookupFieldOrMethod(iface, false, imported, fn.Name.Name); method == nil {
+				continue
+			}
+			if types.Implements(recv, iface) || types.Implements(types.NewPointer(recv), iface) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// methodRenameEdits returns edits renaming the method declared by fn and every
+// use of it in the package. Uses can only be found with type information, so
+// nil is returned without it.
+func methodRenameEdits(pass *analysis.Pass, fn *ast.FuncDecl, newName string) []analysis.TextEdit {
+	return objectRenameEdits(pass, fn.Name, newName)
+}
+
+// objectRenameEdits returns edits renaming the object declared by decl and
+// every use of it in the package, or nil without type information.
+func objectRenameEdits(pass *analysis.Pass, decl *ast.Ident, newName string) []analysis.TextEdit {
+	if pass.TypesInfo == nil {
+		return nil
+	}
+	obj := pass.TypesInfo.Defs[decl]
+	if obj == nil {
+		return nil
+	}
+
+	edits := []analysis.TextEdit{{Pos: decl.Pos(), End: decl.End(), NewText: []byte(newName)}}
+	for ident, use := range pass.TypesInfo.Uses {
+		if use == obj {
+			edits = append(edits, analysis.TextEdit{Pos: ident.Pos(), End: ident.End(), NewText: []byte(newName)})
+		}
+	}
+	return edits
+}
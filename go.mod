@@ -2,10 +2,12 @@ module github.com/xbpk3t/gonamefix
 
 go 1.24.4
 
-require golang.org/x/tools v0.36.0
+require (
+	golang.org/x/mod v0.27.0
+	golang.org/x/tools v0.36.0
+)
 
 require (
-	golang.org/x/mod v0.27.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 )
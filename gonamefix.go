@@ -1,10 +1,15 @@
 package gonamefix
 
 import (
+	"errors"
+	"fmt"
 	"go/ast"
-	"path/filepath"
+	"log/slog"
 	"regexp"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
@@ -15,122 +20,365 @@ const doc = "gonamefix checks for prohibited naming conventions and suggests rep
 
 // NewAnalyzer creates a new analyzer with the given configuration
 func NewAnalyzer(config Config) *analysis.Analyzer {
+	// Names the standard library forces on implementations are never flagged
+	config.AllowedLongNames = append(GoStdlibAllowedNames(), config.AllowedLongNames...)
+
+	// Patterns are compiled once and reused for every package and file
+	compiled, err := compileConfig(config)
+
 	return &analysis.Analyzer{
 		Name:     "gonamefix",
 		Doc:      doc,
 		Requires: []*analysis.Analyzer{inspect.Analyzer},
 		Run: func(pass *analysis.Pass) (interface{}, error) {
-			return runWithConfig(pass, config)
+			if err != nil {
+				return nil, fmt.Errorf("invalid configuration: %w", err)
+			}
+			return runWithConfig(pass, config, compiled)
 		},
 	}
 }
 
-// Analyzer is the default analyzer for gonamefix - requires configuration
-var Analyzer = NewAnalyzer(Config{
-	Check:         [][]string{}, // No default mappings - must be configured
-	ExcludeFiles:  []string{"*.pb.go", "*_test.go"},
-	ExcludeDirs:   []string{"vendor", "node_modules", ".git"},
-	CaseSensitive: false,
-})
+// Analyzer is the default analyzer for gonamefix - requires configuration.
+// It has no mappings of its own and uses the ones registered in DefaultRegistry.
+var Analyzer = newRegistryAnalyzer(DefaultRegistry)
+
+// DefaultConfig returns the configuration used by the default Analyzer, without any mappings.
+func DefaultConfig() Config {
+	return Config{
+		Check:         [][]string{}, // No default mappings - must be configured
+		ExcludeFiles:  []string{"*.pb.go", "*_test.go"},
+		ExcludeDirs:   []string{"node_modules", ".git"},
+		CaseSensitive: false,
+
+		ExcludeBuildConstraints: []string{"ignore"},
+	}
+}
 
 // Config represents configuration for the gonamefix linter.
 type Config struct {
 	// Check contains mapping of long names to short names [original, replacement]
 	Check [][]string `mapstructure:"check"`
+	// Mappings are structured mappings, applied in addition to Check, that can set a rule ID and a documentation URL
+	Mappings []Mapping `mapstructure:"mappings"`
+	// DocsBaseURL is the documentation link of mappings without a URL of their own. The
+	// placeholders {id}, {original} and {replacement} are filled in for each mapping
+	DocsBaseURL string `mapstructure:"docs-base-url"`
+	// CheckURL is an https URL of a shared mapping file whose check and mappings are
+	// merged beneath the configured ones. It is read by the gonamefix command, which
+	// downloads and caches the file; the analyzer itself never accesses the network
+	CheckURL string `mapstructure:"check-url"`
 	// ExcludeFiles contains file patterns to exclude
 	ExcludeFiles []string `mapstructure:"exclude-files"`
+	// ExcludeFilesRegex contains regular expressions of files to exclude, matched
+	// against the whole slash-separated path, e.g. `^api/.*(_gen|\.generated)\.go$`.
+	// Absolute paths are made relative to the root of their module first
+	ExcludeFilesRegex []string `mapstructure:"exclude-files-regex"`
 	// ExcludeDirs contains directory patterns to exclude
 	ExcludeDirs []string `mapstructure:"exclude-dirs"`
 	// CaseSensitive controls whether the matching is case sensitive (default: false for camelCase)
 	CaseSensitive bool `mapstructure:"case-sensitive"`
+	// IncludeVendor analyzes code in vendor directories, which is skipped by default
+	IncludeVendor bool `mapstructure:"include-vendor"`
+	// IncludeTests analyzes test files by dropping the "*_test.go" pattern, one of the
+	// defaults, from ExcludeFiles. Other patterns matching test files still apply
+	IncludeTests bool `mapstructure:"include-tests"`
+	// IncludeGenerated analyzes generated files, those with a "// Code generated ... DO NOT
+	// EDIT." comment before the package clause, which are skipped by default
+	IncludeGenerated bool `mapstructure:"include-generated"`
+	// ExcludeBuildConstraints skips files whose //go:build line references one of these tags, e.g. "ignore"
+	ExcludeBuildConstraints []string `mapstructure:"exclude-build-constraints"`
+	// SkipCgo skips files that import "C". Without it they are checked, except for //export
+	// functions and names selected from C
+	SkipCgo bool `mapstructure:"skip-cgo"`
+	// AllowedLongNames lists identifiers that are never flagged, matched exactly
+	AllowedLongNames []string `mapstructure:"allowed-long-names"`
+	// ExcludeNames lists identifiers the mappings never flag or fix, matched exactly
+	// or as globs such as "*Service". Other rules still check them
+	ExcludeNames []string `mapstructure:"exclude-names"`
+	// Exclude contains structured exclusion rules that document why a path is skipped
+	Exclude []ExcludeRule `mapstructure:"exclude"`
+	// Initialisms flags initialisms that are not written in a consistent case (userId -> userID)
+	Initialisms bool `mapstructure:"initialisms"`
+	// InitialismList replaces the built-in initialism list when set
+	InitialismList []string `mapstructure:"initialism-list"`
+	// ExtraInitialisms extends the initialism list with project specific words
+	ExtraInitialisms []string `mapstructure:"extra-initialisms"`
+	// Hungarian flags Hungarian notation prefixes such as strUserName or bIsValid
+	Hungarian bool `mapstructure:"hungarian"`
+	// HungarianPrefixes replaces the built-in list of Hungarian prefixes when set
+	HungarianPrefixes []string `mapstructure:"hungarian-prefixes"`
+	// HungarianExceptions lists names that are never treated as Hungarian notation
+	HungarianExceptions []string `mapstructure:"hungarian-exceptions"`
+	// NoSnakeCase flags snake_case identifiers such as user_name and suggests camelCase
+	NoSnakeCase bool `mapstructure:"no-snake-case"`
+	// InterfaceNaming flags single-method interfaces not named after their method (Read -> Reader)
+	InterfaceNaming bool `mapstructure:"interface-naming"`
+	// InterfaceNamingExceptions lists interface names the interface naming rule accepts as they are
+	InterfaceNamingExceptions []string `mapstructure:"interface-naming-exceptions"`
+	// GetterNaming flags GetXxx methods without parameters and with a single result, suggesting Xxx
+	GetterNaming bool `mapstructure:"getter-naming"`
+	// ErrorNaming flags error variables not named ErrXxx at package level or err/errXxx locally
+	ErrorNaming bool `mapstructure:"error-naming"`
+	// BoolNaming flags bool variables, fields and functions whose names do not read as predicates (valid -> isValid)
+	BoolNaming bool `mapstructure:"bool-naming"`
+	// BoolPrefixes replaces the built-in predicate prefixes (is, has, can, ...) when set
+	BoolPrefixes []string `mapstructure:"bool-prefixes"`
+	// BoolNamingExceptions lists boolean names the rule accepts as they are, in addition to ok, found and done
+	BoolNamingExceptions []string `mapstructure:"bool-naming-exceptions"`
+	// BoolNamingKinds limits the rule to some of "var", "field" and "func" (default: all)
+	BoolNamingKinds []string `mapstructure:"bool-naming-kinds"`
+	// NoStutter flags exported top-level names that repeat the package name (config.ConfigLoader -> config.Loader)
+	NoStutter bool `mapstructure:"no-stutter"`
+	// StutterUnexported also applies NoStutter to unexported top-level names
+	StutterUnexported bool `mapstructure:"stutter-unexported"`
+	// NoAllCaps flags constants named in SCREAMING_SNAKE_CASE and suggests CamelCase (MAX_RETRY_COUNT -> MaxRetryCount)
+	NoAllCaps bool `mapstructure:"no-all-caps"`
+	// AllCapsVars also applies NoAllCaps to variables
+	AllCapsVars bool `mapstructure:"all-caps-vars"`
+	// SynonymConsistency reports identifiers using a synonym (cfg, conf) of a canonical replacement
+	// (config) in packages that also use the canonical form
+	SynonymConsistency bool `mapstructure:"synonym-consistency"`
+	// ReceiverConsistency flags methods whose receiver name differs from the other methods of the type
+	ReceiverConsistency bool `mapstructure:"receiver-consistency"`
+	// MaxLength flags identifiers longer than this many characters (0 disables the rule)
+	MaxLength int `mapstructure:"max-length"`
+	// CheckLoopVars also checks identifiers of two characters or fewer declared by for-loop init
+	// statements and range clauses, such as i, j, k and v, which are skipped by default
+	CheckLoopVars bool `mapstructure:"check-loop-vars"`
+	// MaxRelated caps the other occurrences of a flagged identifier attached to its diagnostic as
+	// related information (0 uses the default of 10, a negative value attaches none)
+	MaxRelated int `mapstructure:"max-related"`
+	// TraceMappings logs every identifier tested against every pattern to stderr
+	TraceMappings bool `mapstructure:"trace-mappings"`
+	// FallbackToTokenizer scans identifier tokens of files that fail to parse instead of skipping them
+	FallbackToTokenizer bool `mapstructure:"fallback-to-tokenizer"`
+	// CheckComments reports words in comments that a mapping would replace ("the request object"),
+	// without suggested fixes. Directives, URLs and code in backticks are skipped
+	CheckComments bool `mapstructure:"check-comments"`
+	// CheckFilenames applies the mappings to the base names of the checked files, split at
+	// underscores and camelCase, e.g. request_handler.go -> req_handler.go. Findings are
+	// reported at the package clause, without fixes
+	CheckFilenames bool `mapstructure:"check-filenames"`
+	// CheckStrings applies the mappings to string literal arguments of the given calls, such as
+	// logging keys in log.With("request_id", id). Keys are split at underscores
+	CheckStrings []StringCall `mapstructure:"check-strings"`
+	// CheckCompositeLitKeys also checks keys of composite literals such as Config{request: "x"}
+	CheckCompositeLitKeys bool `mapstructure:"check-composite-lit-keys"`
+	// ReplaceInTestTableNames also checks test case names in table-driven tests, e.g. {name: "processRequest"}.
+	// Only files that are not excluded are checked, so *_test.go must be removed from ExcludeFiles
+	ReplaceInTestTableNames bool `mapstructure:"replace-in-test-table-names"`
+	// TestNameFields replaces the struct field names treated as test case names (default: name, testName, caseName, desc)
+	TestNameFields []string `mapstructure:"test-name-fields"`
+	// Transforms post-process every suggested name in order, after the mapping is applied.
+	// They can only be set from Go code
+	Transforms []Transform `mapstructure:"-"`
+	// PriorityPatterns lists originals whose mappings are always tried first, regardless of their position in Check
+	PriorityPatterns []string `mapstructure:"priority-patterns"`
+	// OnlyKinds limits the checked declarations to some kinds: func, method, type, var, const, field,
+	// param and result (default: all). Rules that do not check declarations one by one are not affected
+	OnlyKinds []string `mapstructure:"only-kinds"`
+}
+
+// HasRules reports whether config enables any check: name mappings or one of the opt-in rules.
+func (c Config) HasRules() bool {
+	return len(c.Check) > 0 || len(c.Mappings) > 0 || c.Initialisms || c.MaxLength > 0 || c.Hungarian || c.ReceiverConsistency ||
+		c.NoSnakeCase || c.InterfaceNaming || c.GetterNaming || c.ErrorNaming || c.BoolNaming || c.NoStutter || c.NoAllCaps ||
+		c.SynonymConsistency
 }
 
 type namePattern struct {
 	regex       *regexp.Regexp
 	original    string
 	replacement string
+	// id is the rule ID used as diagnostic category and url links to its documentation
+	id  string
+	url string
+	// types and kinds restrict the pattern, see Mapping.Types and Mapping.Kinds;
+	// nil kinds apply it to every kind
+	types []string
+	kinds map[string]bool
 }
 
-func runWithConfig(pass *analysis.Pass, config Config) (interface{}, error) {
+func runWithConfig(pass *analysis.Pass, config Config, compiled *compiledConfig) (interface{}, error) {
 
-	// Skip if file should be excluded
-	filename := pass.Fset.Position(pass.Files[0].Pos()).Filename
-	if shouldExcludeFile(filename, config) {
+	// Skip files excluded by name, by build constraints or for using cgo
+	files, skipped := filterFiles(pass, config, compiled)
+	if len(files) == 0 {
 		return nil, nil
 	}
 
-	// Build name mappings from config
-	nameMappings := buildNameMappings(config.Check)
-	if len(nameMappings) == 0 {
-		return nil, nil
+	pass = withRelatedOccurrences(pass, config.MaxRelated)
+
+	// Words in comments and strings have no owning type or declaration kind,
+	// so they are only checked by unrestricted patterns
+	patterns := compiled.patterns
+	unscoped := patternsFor(patterns, nil, "")
+	restricted := hasRestrictedPatterns(patterns)
+	var owners *typeOwners
+	for _, pattern := range patterns {
+		if len(pattern.types) > 0 {
+			owners = &typeOwners{pass: pass}
+			break
+		}
+	}
+
+	var initialisms map[string]bool
+	if config.Initialisms {
+		initialisms = buildInitialisms(config)
+	}
+
+	var hungarian *hungarianRule
+	if config.Hungarian {
+		hungarian = buildHungarianRule(config)
+	}
+
+	// Package level rules look at all methods of a type at once
+	if config.ReceiverConsistency {
+		checkReceiverNames(pass, files)
+	}
+	if config.GetterNaming {
+		checkGetters(pass, files)
+	}
+	if config.ErrorNaming {
+		checkErrorNames(pass, files)
+	}
+	if config.BoolNaming {
+		checkBoolNames(pass, files, buildBoolRule(config))
+	}
+	if config.NoStutter {
+		checkStutter(pass, files, config.StutterUnexported)
+	}
+	if config.SynonymConsistency {
+		checkSynonyms(pass, files, config.MappingPairs())
+	}
+	if config.NoAllCaps {
+		checkAllCaps(pass, files, buildInitialisms(config), config.AllCapsVars)
+	}
+
+	allowed := make(map[string]bool, len(config.AllowedLongNames))
+	for _, name := range config.AllowedLongNames {
+		allowed[name] = true
+	}
+
+	if config.CheckComments {
+		checkComments(pass, files, unscoped, config.CaseSensitive, allowed)
+	}
+	if config.CheckFilenames {
+		checkFileNames(pass, files, unscoped, config.CaseSensitive)
+	}
+
+	var interfaceExceptions map[string]bool
+	if config.InterfaceNaming {
+		interfaceExceptions = make(map[string]bool)
+		for _, name := range append(DefaultInterfaceNameExceptions(), config.InterfaceNamingExceptions...) {
+			interfaceExceptions[name] = true
+		}
 	}
 
-	// Compile regex patterns
-	patterns := buildPatterns(nameMappings, config.CaseSensitive)
+	if len(patterns) == 0 && initialisms == nil && config.MaxLength <= 0 && hungarian == nil &&
+		!config.NoSnakeCase && interfaceExceptions == nil && len(config.CheckStrings) == 0 {
+		return nil, nil
+	}
 
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
-	nodeFilter := []ast.Node{
-		(*ast.Ident)(nil),
-		(*ast.FuncDecl)(nil),
-		(*ast.TypeSpec)(nil),
-		(*ast.ValueSpec)(nil),
-		(*ast.Field)(nil),
+	var nodeFilter []ast.Node
+	if config.CheckCompositeLitKeys || config.ReplaceInTestTableNames {
+		nodeFilter = append(nodeFilter, (*ast.CompositeLit)(nil))
+	}
+	if len(config.CheckStrings) > 0 {
+		nodeFilter = append(nodeFilter, (*ast.CallExpr)(nil))
 	}
 
+	var tracer *slog.Logger
+	if config.TraceMappings {
+		tracer = newTraceLogger()
+	}
+
+	// Renames are planned across the package and reported once all are known
+	plan := &renamePlan{}
+
 	// Track checked identifiers to avoid duplicates
 	checked := make(map[*ast.Ident]bool)
+	checkName := func(ident *ast.Ident, kind string, local bool) {
+		if ident == nil || checked[ident] {
+			return
+		}
+		checked[ident] = true
+
+		// Keywords are never flagged and allowed names are verbose by design
+		if isGoKeyword(ident.Name) || allowed[ident.Name] {
+			return
+		}
+
+		identPatterns := unscoped
+		if restricted {
+			identPatterns = patternsFor(patterns, owners.of(ident), kind)
+		}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		if tracer != nil {
+			traceMappings(tracer, pass, ident, identPatterns, config.CaseSensitive)
+		}
+		checkIdentifier(ident, identPatterns, config.CaseSensitive, config.Transforms, compiled.names, plan)
+		if config.NoSnakeCase {
+			checkSnakeCase(ident, plan)
+		}
+		if local {
+			return
+		}
+		if initialisms != nil {
+			checkInitialisms(ident, initialisms, plan)
+		}
+		if config.MaxLength > 0 {
+			checkMaxLength(pass, ident, config.MaxLength, identPatterns, config.CaseSensitive)
+		}
+		if hungarian != nil {
+			checkHungarian(ident, hungarian, plan)
+		}
+	}
+	check := func(ident *ast.Ident, kind string) { checkName(ident, kind, false) }
+	// Local variables declared with := and range are only checked by the
+	// mappings and the snake_case rule; the other rules check declarations
+	var checkLocal func(ident *ast.Ident, kind string)
+	if len(patterns) > 0 || config.NoSnakeCase {
+		checkLocal = func(ident *ast.Ident, kind string) { checkName(ident, kind, true) }
+	}
+
+	var testNameFields map[string]bool
+	if config.ReplaceInTestTableNames {
+		testNameFields = buildTestNameFields(config)
+	}
+
+	walkDeclarations(pass, inspect, skipped, config.CheckLoopVars, compiled.kinds, nodeFilter, check, checkLocal, func(n ast.Node, file *ast.File) {
 		switch node := n.(type) {
-		case *ast.FuncDecl:
-			if node.Name != nil && !checked[node.Name] {
-				checkIdentifier(pass, node.Name, patterns, config.CaseSensitive)
-				checked[node.Name] = true
-			}
-			// Check function parameters
-			if node.Type != nil && node.Type.Params != nil {
-				for _, param := range node.Type.Params.List {
-					for _, name := range param.Names {
-						if !checked[name] {
-							checkIdentifier(pass, name, patterns, config.CaseSensitive)
-							checked[name] = true
-						}
-					}
-				}
+		case *ast.TypeSpec:
+			if interfaceExceptions != nil {
+				checkInterfaceName(pass, node, interfaceExceptions)
 			}
-			// Check function results
-			if node.Type != nil && node.Type.Results != nil {
-				for _, result := range node.Type.Results.List {
-					for _, name := range result.Names {
-						if !checked[name] {
-							checkIdentifier(pass, name, patterns, config.CaseSensitive)
-							checked[name] = true
-						}
-					}
-				}
+		case *ast.CallExpr:
+			checkStringArgs(pass, node, config.CheckStrings, unscoped, config.CaseSensitive)
+		case *ast.CompositeLit:
+			if testNameFields != nil && strings.HasSuffix(pass.Fset.Position(file.Pos()).Filename, "_test.go") {
+				checkTestTableNames(pass, node, testNameFields, unscoped, config.CaseSensitive)
 			}
-		case *ast.TypeSpec:
-			if node.Name != nil && !checked[node.Name] {
-				checkIdentifier(pass, node.Name, patterns, config.CaseSensitive)
-				checked[node.Name] = true
+			// Fields of C structs are named by C
+			if !config.CheckCompositeLitKeys || compiled.kinds != nil && !compiled.kinds[KindField] || isCgoFile(file) && isCgoSelector(node.Type) {
+				return
 			}
-		case *ast.ValueSpec:
-			for _, name := range node.Names {
-				if !checked[name] {
-					checkIdentifier(pass, name, patterns, config.CaseSensitive)
-					checked[name] = true
+			// Keys are usage sites of struct fields, so they follow the field rename
+			for _, elt := range node.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
 				}
-			}
-		case *ast.Field:
-			for _, name := range node.Names {
-				if !checked[name] {
-					checkIdentifier(pass, name, patterns, config.CaseSensitive)
-					checked[name] = true
+				if key, ok := kv.Key.(*ast.Ident); ok {
+					check(key, KindField)
 				}
 			}
 		}
 	})
+	plan.report(pass)
 
 	return nil, nil
 }
@@ -145,9 +393,21 @@ func buildNameMappings(check [][]string) map[string]string {
 	return mappings
 }
 
-func buildPatterns(mappings map[string]string, caseSensitive bool) []namePattern {
+// buildPatterns compiles the regular expression of every mapping, in the
+// order of their originals so that the patterns are the same on every run.
+// Mappings whose expression does not compile are left out and reported in
+// the error.
+func buildPatterns(mappings map[string]string, caseSensitive bool) ([]namePattern, error) {
+	originals := make([]string, 0, len(mappings))
+	for original := range mappings {
+		originals = append(originals, original)
+	}
+	slices.Sort(originals)
+
 	var patterns []namePattern
-	for original, replacement := range mappings {
+	var errs []error
+	for _, original := range originals {
+		replacement := mappings[original]
 		var regex *regexp.Regexp
 		var err error
 
@@ -162,18 +422,46 @@ func buildPatterns(mappings map[string]string, caseSensitive bool) []namePattern
 			regex, err = regexp.Compile(pattern)
 		}
 
-		if err == nil {
-			patterns = append(patterns, namePattern{
-				regex:       regex,
-				original:    original,
-				replacement: replacement,
-			})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mapping %s: %w", MappingCategory(original, replacement), err))
+			continue
 		}
+		patterns = append(patterns, namePattern{
+			regex:       regex,
+			original:    original,
+			replacement: replacement,
+		})
 	}
-	return patterns
+	return patterns, errors.Join(errs...)
 }
 
-func checkIdentifier(pass *analysis.Pass, ident *ast.Ident, patterns []namePattern, caseSensitive bool) {
+// prioritizePatterns moves patterns whose original is listed in priority to the front,
+// in the order given by priority. The relative order of the remaining patterns is kept.
+func prioritizePatterns(patterns []namePattern, priority []string) []namePattern {
+	if len(priority) == 0 {
+		return patterns
+	}
+
+	result := make([]namePattern, 0, len(patterns))
+	moved := make(map[int]bool)
+	for _, original := range priority {
+		for i, pattern := range patterns {
+			if !moved[i] && pattern.original == original {
+				result = append(result, pattern)
+				moved[i] = true
+			}
+		}
+	}
+
+	for i, pattern := range patterns {
+		if !moved[i] {
+			result = append(result, pattern)
+		}
+	}
+	return result
+}
+
+func checkIdentifier(ident *ast.Ident, patterns []namePattern, caseSensitive bool, transforms []Transform, excluded *nameExclusions, plan *renamePlan) {
 	if ident == nil || ident.Name == "" {
 		return
 	}
@@ -183,16 +471,75 @@ func checkIdentifier(pass *analysis.Pass, ident *ast.Ident, patterns []namePatte
 		return
 	}
 
-	name := ident.Name
+	// Excluded names keep their spelling, whatever mappings they contain
+	if excluded.match(ident.Name) {
+		return
+	}
+
+	if suggestedName, pattern, ok := suggestName(ident.Name, patterns, caseSensitive); ok {
+		suggestedName = applyTransforms(transforms, pattern, ident.Name, suggestedName)
+		if suggestedName == ident.Name || suggestedName == "" {
+			return
+		}
+		plan.add(ident, suggestedName, pattern.id, pattern.url)
+	}
+}
+
+// MappingCategory returns the display name of the mapping original ->
+// replacement, e.g. "request→req", used in reports that list mappings.
+// Diagnostics carry the mapping's rule ID instead, see MappingRuleID.
+func MappingCategory(original, replacement string) string {
+	return original + "→" + replacement
+}
 
+// suggestName applies the first matching pattern to name. Only the first match
+// is used to avoid duplicate reports for the same identifier.
+func suggestName(name string, patterns []namePattern, caseSensitive bool) (string, namePattern, bool) {
 	for _, pattern := range patterns {
 		suggestedName := replaceInName(name, pattern.original, pattern.replacement, caseSensitive)
-
 		if suggestedName != name {
-			pass.Reportf(ident.Pos(), "suggest replacing '%s' with '%s'", name, suggestedName)
-			break // Only report the first match to avoid duplicate reports
+			return suggestedName, pattern, true
 		}
 	}
+	return name, namePattern{}, false
+}
+
+// isDefinition reports whether ident is newly declared by the short variable
+// declaration stmt rather than reassigned.
+func isDefinition(pass *analysis.Pass, ident *ast.Ident, stmt *ast.AssignStmt) bool {
+	if ident.Name == "_" {
+		return false
+	}
+	if pass.TypesInfo != nil {
+		return pass.TypesInfo.Defs[ident] != nil
+	}
+	//nolint:staticcheck // ast.Object is the only resolution available without type information
+	return ident.Obj != nil && ident.Obj.Decl == stmt
+}
+
+// reportRename reports ident with a suggested fix renaming it to suggestedName.
+// A documentation url is appended to the message.
+func reportRename(pass *analysis.Pass, ident *ast.Ident, suggestedName, category, url string) {
+	message := fmt.Sprintf("suggest replacing '%s' with '%s'", ident.Name, suggestedName)
+	pass.Report(analysis.Diagnostic{
+		Pos:      ident.Pos(),
+		End:      ident.End(),
+		Category: category,
+		URL:      url,
+		Message:  withURL(message, url),
+		SuggestedFixes: []analysis.SuggestedFix{
+			{
+				Message: message,
+				TextEdits: []analysis.TextEdit{
+					{
+						Pos:     ident.Pos(),
+						End:     ident.End(),
+						NewText: []byte(suggestedName),
+					},
+				},
+			},
+		},
+	})
 }
 
 func replaceInName(name, original, replacement string, caseSensitive bool) string {
@@ -205,8 +552,8 @@ func replaceInName(name, original, replacement string, caseSensitive bool) strin
 		return replacement
 	} else if !caseSensitive && strings.EqualFold(name, original) {
 		// Preserve case style for case insensitive match
-		if len(name) > 0 && isUpperCase(rune(name[0])) {
-			return strings.Title(replacement)
+		if first, _ := utf8.DecodeRuneInString(name); isUpperCase(first) {
+			return capitalize(replacement)
 		}
 		return replacement
 	}
@@ -215,59 +562,66 @@ func replaceInName(name, original, replacement string, caseSensitive bool) strin
 	return replaceCamelCase(name, original, replacement, caseSensitive)
 }
 
+// replaceCamelCase replaces original at the start of name or as an embedded
+// camelCase word. It works on runes, so multi-byte names are never sliced
+// within a character.
 func replaceCamelCase(name, original, replacement string, caseSensitive bool) string {
-	result := name
-
-	if !caseSensitive {
-		lowerName := strings.ToLower(name)
-		lowerOriginal := strings.ToLower(original)
-
-		// Check if original is at the beginning (case insensitive)
-		if strings.HasPrefix(lowerName, lowerOriginal) {
-			if len(name) == len(original) {
-				// Preserve original case style
-				if isUpperCase(rune(name[0])) {
-					return strings.Title(replacement)
-				}
-				return replacement
-			} else if len(name) > len(original) && isUpperCase(rune(name[len(original)])) {
-				// camelCase: requestHandler -> reqHandler
-				if isUpperCase(rune(name[0])) {
-					return strings.Title(replacement) + name[len(original):]
-				}
-				return replacement + name[len(original):]
+	nameRunes, originalRunes := []rune(name), []rune(original)
+
+	// Check if original is at the beginning: requestHandler -> reqHandler
+	if n := len(originalRunes); n > 0 && n <= len(nameRunes) && hasRunePrefix(nameRunes, originalRunes, caseSensitive) {
+		rest := nameRunes[n:]
+		if len(rest) == 0 || isUpperCase(rest[0]) {
+			// Preserve original case style
+			if !caseSensitive && isUpperCase(nameRunes[0]) {
+				return capitalize(replacement) + string(rest)
 			}
+			return replacement + string(rest)
 		}
+	}
 
-		// Check if original is embedded in camelCase
-		titleOriginal := strings.Title(original)
-		if idx := strings.Index(name, titleOriginal); idx > 0 {
-			// Make sure it's a proper word boundary
-			if idx+len(titleOriginal) == len(name) ||
-				(idx+len(titleOriginal) < len(name) && isUpperCase(rune(name[idx+len(titleOriginal)]))) {
-				return name[:idx] + strings.Title(replacement) + name[idx+len(titleOriginal):]
-			}
-		}
-	} else {
-		// Case sensitive matching (similar logic but without toLower)
-		if strings.HasPrefix(name, original) {
-			if len(name) == len(original) {
-				return replacement
-			} else if len(name) > len(original) && isUpperCase(rune(name[len(original)])) {
-				return replacement + name[len(original):]
-			}
+	// Check if original is embedded in camelCase
+	titleOriginal := []rune(capitalize(original))
+	if idx := embeddedWordIndex(nameRunes, titleOriginal); idx > 0 {
+		return string(nameRunes[:idx]) + capitalize(replacement) + string(nameRunes[idx+len(titleOriginal):])
+	}
+
+	return name
+}
+
+// hasRunePrefix reports whether name starts with prefix, ignoring case unless
+// caseSensitive is set.
+func hasRunePrefix(name, prefix []rune, caseSensitive bool) bool {
+	for i, r := range prefix {
+		if name[i] != r && (caseSensitive || unicode.ToLower(name[i]) != unicode.ToLower(r)) {
+			return false
 		}
+	}
+	return true
+}
 
-		titleOriginal := strings.Title(original)
-		if idx := strings.Index(name, titleOriginal); idx > 0 {
-			if idx+len(titleOriginal) == len(name) ||
-				(idx+len(titleOriginal) < len(name) && isUpperCase(rune(name[idx+len(titleOriginal)]))) {
-				return name[:idx] + strings.Title(replacement) + name[idx+len(titleOriginal):]
-			}
+// embeddedWordIndex returns the rune index of the first occurrence of word
+// after the start of name that ends on a camelCase word boundary, or -1. Only
+// that single occurrence is replaced, so requestRequest becomes reqRequest.
+func embeddedWordIndex(name, word []rune) int {
+	if len(word) == 0 {
+		return -1
+	}
+	for idx := 1; idx+len(word) <= len(name); idx++ {
+		if !slices.Equal(name[idx:idx+len(word)], word) {
+			continue
+		}
+		if end := idx + len(word); end == len(name) || isUpperCase(name[end]) {
+			return idx
 		}
 	}
+	return -1
+}
 
-	return result
+// isLoopVarName reports whether a loop variable name is short enough to be
+// idiomatic, like i, j, k, v or ok.
+func isLoopVarName(name string) bool {
+	return utf8.RuneCountInString(name) <= 2
 }
 
 func isUpperCase(r rune) bool {
@@ -306,21 +660,3 @@ func isGoKeyword(name string) bool {
 
 	return keywords[name]
 }
-
-func shouldExcludeFile(filename string, config Config) bool {
-	base := filepath.Base(filename)
-	for _, pattern := range config.ExcludeFiles {
-		matched, err := filepath.Match(pattern, base)
-		if err == nil && matched {
-			return true
-		}
-	}
-
-	for _, pattern := range config.ExcludeDirs {
-		if strings.Contains(filename, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
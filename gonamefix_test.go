@@ -1,8 +1,21 @@
 package gonamefix
 
 import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
@@ -89,14 +102,14 @@ func TestConfigFunctions(t *testing.T) {
 	}
 
 	// Test buildPatterns
-	patterns := buildPatterns(mappings, false)
-	if len(patterns) != 2 {
+	patterns, err := buildPatterns(mappings, false)
+	if err != nil || len(patterns) != 2 {
 		t.Errorf("Expected 2 patterns, got %d", len(patterns))
 	}
 
 	// Test case sensitive patterns
-	patterns = buildPatterns(mappings, true)
-	if len(patterns) != 2 {
+	patterns, err = buildPatterns(mappings, true)
+	if err != nil || len(patterns) != 2 {
 		t.Errorf("Expected 2 patterns for case sensitive, got %d", len(patterns))
 	}
 }
@@ -182,6 +195,120 @@ func TestShouldExcludeFile(t *testing.T) {
 	}
 }
 
+func TestExcludeFilesRegex(t *testing.T) {
+	// Globs and regular expressions apply together
+	config := Config{
+		ExcludeFiles:      []string{"*.pb.go"},
+		ExcludeFilesRegex: []string{`(^|/)api/.*(_gen|\.generated)\.go$`},
+	}
+
+	tests := []struct {
+		filename string
+		expected bool
+	}{
+		{"api/user_gen.go", true},
+		{"/repo/api/v1/user.generated.go", true},
+		{"api/user.pb.go", true},
+		{"api/user.go", false},
+		{"internal/user_gen.go", false},
+		{"myapi/user_gen.go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := shouldExcludeFile(tt.filename, config); got != tt.expected {
+				t.Errorf("shouldExcludeFile(%q) = %t, want %t", tt.filename, got, tt.expected)
+			}
+		})
+	}
+
+	exclusion, _ := MatchExclusion("api/user_gen.go", config)
+	if want := "excluded by exclude-files-regex pattern '(^|/)api/.*(_gen|\\.generated)\\.go$'"; exclusion.String() != want {
+		t.Errorf("MatchExclusion() = %q, want %q", exclusion.String(), want)
+	}
+
+	// Absolute paths, as given by golangci-lint, are matched relative to the
+	// root of their module
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config = Config{ExcludeFilesRegex: []string{`^api/.*_gen\.go$`}}
+	if !shouldExcludeFile(filepath.Join(root, "api", "user_gen.go"), config) {
+		t.Error("absolute path not matched relative to the module root")
+	}
+	if shouldExcludeFile(filepath.Join(root, "internal", "api", "user_gen.go"), config) {
+		t.Error("absolute path below another api directory excluded")
+	}
+}
+
+func TestSkipVendor(t *testing.T) {
+	config := Config{ExcludeDirs: []string{"vendor"}}
+	if !shouldExcludeFile("/repo/vendor/example.com/lib/lib.go", config) {
+		t.Error("vendored file analyzed by default")
+	}
+
+	// include-vendor also overrides a "vendor" entry of exclude-dirs
+	config.IncludeVendor = true
+	if shouldExcludeFile("/repo/vendor/example.com/lib/lib.go", config) {
+		t.Error("vendored file excluded with include-vendor")
+	}
+}
+
+func TestIncludeTests(t *testing.T) {
+	config := DefaultConfig()
+	config.ExcludeFiles = append(config.ExcludeFiles, "mock_*_test.go")
+	config.ExcludeFilesRegex = []string{"_integration_test\\.go$"}
+	config.IncludeTests = true
+
+	tests := []struct {
+		filename string
+		excluded bool
+	}{
+		// The default *_test.go pattern, also when given explicitly, is dropped
+		{"pkg/handler_test.go", false},
+		{"pkg/handler.go", false},
+		// The other patterns still apply, test files included
+		{"api/types.pb.go", true},
+		{"pkg/mock_store_test.go", true},
+		{"pkg/db_integration_test.go", true},
+	}
+	for _, tt := range tests {
+		if got := shouldExcludeFile(tt.filename, config); got != tt.excluded {
+			t.Errorf("shouldExcludeFile(%q) with include-tests = %t, want %t", tt.filename, got, tt.excluded)
+		}
+	}
+
+	config.IncludeTests = false
+	if !shouldExcludeFile("pkg/handler_test.go", config) {
+		t.Error("test file analyzed without include-tests")
+	}
+}
+
+func TestSkipExcludedDir(t *testing.T) {
+	excludeDirs := []string{"node_modules", "gen/", "vendor", "mock_"}
+	tests := []struct {
+		dir     string
+		want    bool
+		pattern string
+	}{
+		{filepath.Join("web", "node_modules"), true, "node_modules"},
+		{filepath.Join("web", "node_modules", "lib"), true, "node_modules"},
+		{filepath.Join("api", "gen"), true, "gen/"},
+		{filepath.Join("api", "generated"), false, ""},
+		// vendor is decided by SkipVendor, and mock_ may match file names only
+		{filepath.Join("lib", "vendor"), false, ""},
+		{filepath.Join("lib", "mocks"), false, ""},
+	}
+	for _, tt := range tests {
+		if got := SkipExcludedDir(tt.dir, excludeDirs); got != tt.want {
+			t.Errorf("SkipExcludedDir(%q) = %t, want %t", tt.dir, got, tt.want)
+		}
+		if pattern, _ := MatchExcludedDir(tt.dir, excludeDirs); pattern != tt.pattern {
+			t.Errorf("MatchExcludedDir(%q) = %q, want %q", tt.dir, pattern, tt.pattern)
+		}
+	}
+}
+
 func TestEdgeCases(t *testing.T) {
 	// Test with empty strings and nil values
 	result := replaceInName("", "request", "req", false)
@@ -229,3 +356,1365 @@ func TestEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestPrioritizePatterns(t *testing.T) {
+	patterns := []namePattern{
+		{original: "user", replacement: "usr"},
+		{original: "request", replacement: "req"},
+		{original: "response", replacement: "res"},
+		{original: "server", replacement: "srv"},
+	}
+
+	result := prioritizePatterns(patterns, []string{"response", "request", "missing"})
+
+	expected := []string{"response", "request", "user", "server"}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d patterns, got %d", len(expected), len(result))
+	}
+	for i, original := range expected {
+		if result[i].original != original {
+			t.Errorf("pattern %d: got %q, want %q", i, result[i].original, original)
+		}
+	}
+
+	// No priority list keeps the order untouched
+	result = prioritizePatterns(patterns, nil)
+	for i := range patterns {
+		if result[i].original != patterns[i].original {
+			t.Errorf("pattern %d: got %q, want %q", i, result[i].original, patterns[i].original)
+		}
+	}
+}
+
+func TestAnalyzerPriorityPatterns(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	// Both mappings match "userRequest"; the priority list decides which one is reported
+	config := Config{
+		Check: [][]string{
+			{"user", "usr"},
+			{"request", "req"},
+		},
+		PriorityPatterns: []string{"request"},
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "priority")
+}
+
+func TestMatchExclusion(t *testing.T) {
+	config := Config{
+		ExcludeFiles: []string{"*.pb.go"},
+		ExcludeDirs:  []string{"vendor"},
+		Exclude: []ExcludeRule{
+			{Pattern: "**/*_string.go", Reason: "stringer output"},
+			{Pattern: "internal/legacy/*.go"},
+			{Pattern: "/gen/**"},
+		},
+	}
+
+	tests := []struct {
+		filename string
+		excluded bool
+		source   string
+		pattern  string
+		reason   string
+	}{
+		{"api/types.pb.go", true, "exclude-files", "*.pb.go", ""},
+		{"vendor/pkg/file.go", true, "vendor", "vendor/", "vendored code is skipped unless include-vendor is set"},
+		{"/repo/vendor/example.com/lib/lib.go", true, "vendor", "vendor/", "vendored code is skipped unless include-vendor is set"},
+		{"/repo/vendored/lib.go", false, "", "", ""},
+		{"pkg/kind_string.go", true, "exclude", "**/*_string.go", "stringer output"},
+		{"kind_string.go", true, "exclude", "**/*_string.go", "stringer output"},
+		{"/repo/internal/legacy/old.go", true, "exclude", "internal/legacy/*.go", ""},
+		{"/repo/internal/legacy/sub/old.go", false, "", "", ""},
+		{"/gen/models/user.go", true, "exclude", "/gen/**", ""},
+		{"/repo/gen/models/user.go", false, "", "", ""},
+		{"pkg/main.go", false, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			exclusion, excluded := MatchExclusion(tt.filename, config)
+			if excluded != tt.excluded {
+				t.Fatalf("MatchExclusion(%q) excluded = %t, want %t", tt.filename, excluded, tt.excluded)
+			}
+			if exclusion.Source != tt.source || exclusion.Pattern != tt.pattern || exclusion.Reason != tt.reason {
+				t.Errorf("MatchExclusion(%q) = %+v, want source %q pattern %q reason %q",
+					tt.filename, exclusion, tt.source, tt.pattern, tt.reason)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		Check:        [][]string{{"request", "req"}},
+		ExcludeFiles: []string{"*.pb.go", ""},
+		Exclude:      []ExcludeRule{{Pattern: "**/gen/*.go"}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	invalid := Config{
+		Check:             [][]string{{"request", "req"}, {"response"}},
+		ExcludeFiles:      []string{"*.pb.go]"},
+		ExcludeFilesRegex: []string{`_gen\.go$`, `(api/`},
+		ExcludeNames:      []string{"*Service", "[a-Service"},
+		Exclude:           []ExcludeRule{{Pattern: "internal/[a-/*.go"}},
+	}
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want the invalid patterns reported")
+	}
+	for _, want := range []string{`exclude-files pattern "*.pb.go]"`, `exclude-files-regex "(api/"`, `exclude-names "[a-Service"`, `exclude pattern "internal/[a-/*.go"`, `check entry ["response"]`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want it to mention %s", err, want)
+		}
+	}
+
+	// The analyzer refuses to run instead of ignoring the invalid patterns
+	if _, _, err := AnalyzeSource("a.go", []byte("package a\n"), invalid); err == nil {
+		t.Error("AnalyzeSource() with an invalid configuration returned no error")
+	}
+
+	if err := (Config{SynonymConsistency: true}).Validate(); err == nil || !strings.Contains(err.Error(), "synonym-consistency") {
+		t.Errorf("Validate() of synonym-consistency without mappings = %v, want an error", err)
+	}
+}
+
+func BenchmarkExclusionMatch(b *testing.B) {
+	config := Config{
+		ExcludeFiles: []string{"*.pb.go", "*_test.go", "*.gen.go"},
+		ExcludeDirs:  []string{"node_modules", ".git", "testdata"},
+		Exclude: []ExcludeRule{
+			{Pattern: "**/*_string.go", Reason: "stringer output"},
+			{Pattern: "internal/legacy/*.go"},
+			{Pattern: "/gen/**"},
+		},
+	}
+	paths := make([]string, 100_000)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("/repo/pkg%d/sub%d/file%d.go", i%100, i%7, i)
+	}
+
+	m, err := compileExclusions(config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for range b.N {
+		for _, path := range paths {
+			m.match(path)
+		}
+	}
+}
+
+func TestExclusionString(t *testing.T) {
+	exclusion := Exclusion{Source: "exclude", Pattern: "**/*_string.go", Reason: "stringer output"}
+	expected := "excluded by exclude pattern '**/*_string.go' (stringer output)"
+	if exclusion.String() != expected {
+		t.Errorf("String() = %q, want %q", exclusion.String(), expected)
+	}
+
+	exclusion = Exclusion{Source: "exclude-files", Pattern: "*_test.go"}
+	expected = "excluded by exclude-files pattern '*_test.go'"
+	if exclusion.String() != expected {
+		t.Errorf("String() = %q, want %q", exclusion.String(), expected)
+	}
+}
+
+func TestAnalyzerCompositeLitKeys(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+			{"database", "db"},
+		},
+		CheckCompositeLitKeys: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "compositelit")
+}
+
+func TestAnalyzerInitialisms(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Initialisms:      true,
+		ExtraInitialisms: []string{"SKU"},
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "initialisms")
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"userName", []string{"user", "Name"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"parseURL", []string{"parse", "URL"}},
+		{"utf8Reader", []string{"utf8", "Reader"}},
+		{"max_retry_count", []string{"max", "retry", "count"}},
+		{"MAX_RETRY", []string{"MAX", "RETRY"}},
+		{"_private", []string{"private"}},
+		{"ID", []string{"ID"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := splitWords(tt.name)
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("splitWords(%q) = %q, want %q", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeInitialisms(t *testing.T) {
+	initialisms := buildInitialisms(Config{})
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"userId", "userID"},
+		{"parseUrl", "parseURL"},
+		{"idList", "idList"},
+		{"IdList", "IDList"},
+		{"Id", "ID"},
+		{"id", "id"},
+		{"HttpJsonApi", "HTTPJSONAPI"},
+		{"userID", "userID"},
+		{"identity", "identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := normalizeInitialisms(tt.name, initialisms)
+			if result != tt.expected {
+				t.Errorf("normalizeInitialisms(%q) = %q, want %q", tt.name, result, tt.expected)
+			}
+		})
+	}
+
+	// An explicit list replaces the built-in one
+	custom := buildInitialisms(Config{InitialismList: []string{"URL"}})
+	if result := normalizeInitialisms("userId", custom); result != "userId" {
+		t.Errorf("normalizeInitialisms with custom list = %q, want %q", result, "userId")
+	}
+}
+
+func TestAnalyzeTokens(t *testing.T) {
+	src := []byte(`package broken
+
+var request string
+
+func handleResponse( {
+	return
+}
+`)
+
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+			{"response", "res"},
+		},
+	}
+
+	fset := token.NewFileSet()
+	diagnostics := AnalyzeTokens(fset, "broken.go", src, config)
+
+	expected := []string{
+		"broken.go:3:5: [partial] suggest replacing 'request' with 'req'",
+		"broken.go:5:6: [partial] suggest replacing 'handleResponse' with 'handleRes'",
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, d := range diagnostics {
+		pos := fset.Position(d.Pos)
+		got := fmt.Sprintf("%s:%d:%d: %s", pos.Filename, pos.Line, pos.Column, d.Message)
+		if got != expected[i] {
+			t.Errorf("diagnostic %d = %q, want %q", i, got, expected[i])
+		}
+	}
+
+	// Excluded files are not scanned either
+	if diagnostics := AnalyzeTokens(token.NewFileSet(), "broken_test.go", src, Config{
+		Check:        config.Check,
+		ExcludeFiles: []string{"*_test.go"},
+	}); len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics for excluded file, got %d", len(diagnostics))
+	}
+}
+
+func TestAnalyzerMaxLength(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	// The mappings report on their own and also feed the shortened suggestion
+	config := Config{
+		Check: [][]string{
+			{"configuration", "config"},
+			{"manager", "mgr"},
+			{"request", "req"},
+		},
+		// Pin the pattern order so the mapping diagnostic is deterministic
+		PriorityPatterns: []string{"configuration", "manager", "request"},
+		MaxLength:        20,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "maxlength")
+}
+
+func TestAnalyzerAllowedLongNames(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+			{"response", "res"},
+			{"serve", "srv"},
+		},
+		AllowedLongNames: []string{"ErrRequestTimeout", "Request"},
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "allowed")
+}
+
+func TestAnalyzerExcludeNames(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{
+			{"user", "usr"},
+			{"request", "req"},
+			{"service", "svc"},
+		},
+		ExcludeNames: []string{"userService", "RequestID", "*Service"},
+	}
+
+	// Excluded names get neither a diagnostic nor a fix, while other names
+	// using the same mappings still get both
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "excludenames")
+}
+
+func TestAnalyzerCheckFilenames(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+			{"response", "res"},
+		},
+		ExcludeFiles:   []string{"*_gen.go"},
+		CheckFilenames: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "filenames")
+}
+
+func TestAnalyzerSkipsGeneratedFiles(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{{"request", "req"}},
+	}
+
+	// Only the files with the marker before the package clause are skipped
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "generated")
+}
+
+func TestIncludeGenerated(t *testing.T) {
+	src := "// Code generated by MockGen. DO NOT EDIT.\n\npackage mocks\n\nvar requestCount int\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "mock.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsGenerated(file) {
+		t.Fatal("IsGenerated() = false, want true")
+	}
+	// A file that does not parse is recognized by its package clause
+	if !isGeneratedSource([]byte(src + "func broken( {\n")) {
+		t.Error("isGeneratedSource() = false for a broken generated file, want true")
+	}
+	if isGeneratedSource([]byte("package mocks\n\n// Code generated by MockGen. DO NOT EDIT.\n")) {
+		t.Error("isGeneratedSource() = true for a marker after the package clause, want false")
+	}
+
+	config := Config{Check: [][]string{{"request", "req"}}}
+	for _, includeGenerated := range []bool{false, true} {
+		config.IncludeGenerated = includeGenerated
+		diagnostics, err := AnalyzeFile(fset, file, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[bool]int{false: 0, true: 1}[includeGenerated]; len(diagnostics) != want {
+			t.Errorf("AnalyzeFile() with include-generated %t = %d diagnostics, want %d", includeGenerated, len(diagnostics), want)
+		}
+		// Files that do not parse are recognized by their leading comments too
+		broken := src + "func {\n"
+		if got := len(AnalyzeTokens(token.NewFileSet(), "mock.go", []byte(broken), config)) > 0; got != includeGenerated {
+			t.Errorf("AnalyzeTokens() with include-generated %t reported findings: %t", includeGenerated, got)
+		}
+	}
+}
+
+func TestSuggestFileName(t *testing.T) {
+	config := Config{
+		Check:    [][]string{{"request", "req"}, {"handler", "h"}},
+		Mappings: []Mapping{{Original: "server", Replacement: "srv", Kinds: []string{KindType}}},
+	}
+	tests := []struct {
+		filename, want string
+		ok             bool
+	}{
+		{filepath.Join("pkg", "request_handler.go"), filepath.Join("pkg", "req_h.go"), true},
+		// Like an identifier, a part is changed by its first matching mapping only
+		{"requestHandler_test.go", "requestH_test.go", true},
+		{"request__x.go", "req__x.go", true},
+		// Mappings restricted to declaration kinds do not apply
+		{"server.go", "", false},
+		{"request.md", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := SuggestFileName(tt.filename, config)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("SuggestFileName(%q) = %q, %t, want %q, %t", tt.filename, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAnalyzerHungarian(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Hungarian:           true,
+		HungarianExceptions: []string{"nGram"},
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "hungarian")
+}
+
+func TestAnalyzerReceiverConsistency(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		ReceiverConsistency: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "receiver")
+}
+
+func TestHasExcludedBuildConstraint(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected bool
+	}{
+		{"ignore", "//go:build ignore\n\npackage p\n", true},
+		{"tools with other tags", "//go:build tools && linux\n\npackage p\n", true},
+		{"negated", "//go:build !ignore\n\npackage p\n", false},
+		{"other tag", "//go:build linux\n\npackage p\n", false},
+		{"no constraint", "package p\n", false},
+		{"after package clause", "package p\n\n//go:build ignore\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "p.go", tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := HasExcludedBuildConstraint(file, []string{"ignore", "tools"})
+			if result != tt.expected {
+				t.Errorf("HasExcludedBuildConstraint(%q) = %t, want %t", tt.src, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnalyzerNoSnakeCase(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		NoSnakeCase: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "snakecase")
+}
+
+func TestMappingRegistry(t *testing.T) {
+	registry := &MappingRegistry{}
+	registry.Register("http", [][]string{
+		{"request", "req"},
+		{"response", "res"},
+	})
+	registry.Register("database", [][]string{
+		{"database", "db"},
+		{"request", "rq"}, // Duplicate original, the earlier registration wins
+		{"invalid"},
+	})
+
+	config := registry.Build()
+	expected := [][]string{
+		{"request", "req"},
+		{"response", "res"},
+		{"database", "db"},
+	}
+	if len(config.Check) != len(expected) {
+		t.Fatalf("Build() returned %d mappings, want %d: %v", len(config.Check), len(expected), config.Check)
+	}
+	for i, pair := range expected {
+		if config.Check[i][0] != pair[0] || config.Check[i][1] != pair[1] {
+			t.Errorf("mapping %d = %v, want %v", i, config.Check[i], pair)
+		}
+	}
+
+	// Defaults are kept
+	if len(config.ExcludeFiles) == 0 {
+		t.Errorf("Build() should keep the default exclusions")
+	}
+
+	// Registering a name again replaces its mappings
+	registry.Register("http", [][]string{{"server", "srv"}})
+	config = registry.Build()
+	if len(config.Check) != 3 || config.Check[0][0] != "server" {
+		t.Errorf("Build() after re-registering = %v", config.Check)
+	}
+}
+
+func TestMappingRegistryConcurrent(t *testing.T) {
+	registry := &MappingRegistry{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			registry.Register(fmt.Sprintf("source%d", i), [][]string{{fmt.Sprintf("word%d", i), "w"}})
+			_ = registry.Build()
+		}(i)
+	}
+	wg.Wait()
+
+	if config := registry.Build(); len(config.Check) != 10 {
+		t.Errorf("Build() returned %d mappings, want 10", len(config.Check))
+	}
+}
+
+func TestAnalyzerUsesDefaultRegistry(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	DefaultRegistry.Register("test", [][]string{{"request", "req"}})
+	defer DefaultRegistry.Register("test", nil)
+
+	analysistest.Run(t, testdata, Analyzer, "registry")
+}
+
+func ExampleMappingRegistry() {
+	registry := &MappingRegistry{}
+	registry.Register("http", [][]string{{"request", "req"}, {"response", "res"}})
+	registry.Register("database", [][]string{{"database", "db"}})
+
+	fmt.Println(registry.Build().Check)
+	// Output: [[request req] [response res] [database db]]
+}
+
+func TestAnalyzerInterfaceNaming(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		InterfaceNaming:           true,
+		InterfaceNamingExceptions: []string{"Repository"},
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "interfaces")
+}
+
+func TestReplaceInNameMultipleOccurrences(t *testing.T) {
+	tests := []struct {
+		input         string
+		caseSensitive bool
+		expected      string
+	}{
+		{"requestRequest", false, "reqRequest"},
+		{"RequestRequest", false, "ReqRequest"},
+		{"requestRequestRequest", false, "reqRequestRequest"},
+		{"myRequestRequest", false, "myReqRequest"},
+		// The first embedded occurrence is not a word boundary, the second one is
+		{"myRequestsRequest", false, "myRequestsReq"},
+		{"requestRequest", true, "reqRequest"},
+		{"myRequestsRequest", true, "myRequestsReq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := replaceInName(tt.input, "request", "req", tt.caseSensitive)
+			if result != tt.expected {
+				t.Errorf("replaceInName(%q, %q, %q, %t) = %q, want %q",
+					tt.input, "request", "req", tt.caseSensitive, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReplaceInNameMultiByte(t *testing.T) {
+	tests := []struct {
+		input       string
+		original    string
+		replacement string
+		expected    string
+	}{
+		{"запросHandler", "запрос", "зап", "запHandler"},
+		{"handleЗапрос", "запрос", "зап", "handleЗап"},
+		{"handleЗапросData", "запрос", "зап", "handleЗапData"},
+		// Lower-casing İ yields two runes, so byte offsets of the lower-cased
+		// name do not apply to the name itself
+		{"İRequest", "i", "x", "xRequest"},
+		{"r", "request", "req", "r"},
+		{"reqΩ", "request", "req", "reqΩ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := replaceInName(tt.input, tt.original, tt.replacement, false)
+			if result != tt.expected {
+				t.Errorf("replaceInName(%q, %q, %q, false) = %q, want %q",
+					tt.input, tt.original, tt.replacement, result, tt.expected)
+			}
+		})
+	}
+}
+
+func FuzzReplaceInName(f *testing.F) {
+	seeds := []struct {
+		name, original, replacement string
+	}{
+		{"requestHandler", "request", "req"},
+		{"HTTPRequest", "request", "req"},
+		{"userID", "id", "identifier"},
+		{"request2Handler", "request", "req"},
+		{"request_id", "request", "req"},
+		{"_request", "request", "req"},
+		{"запросОбработчик", "запрос", "зап"},
+		{"обработчикЗапрос", "запрос", "зап"},
+		{"İRequest", "i", "x"},
+		{"Key", "key", "k"},
+		{"r", "request", "req"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.name, seed.original, seed.replacement, false)
+		f.Add(seed.name, seed.original, seed.replacement, true)
+	}
+
+	isIdentifier := func(s string) bool { return token.IsIdentifier(s) || token.IsKeyword(s) }
+	f.Fuzz(func(t *testing.T, name, original, replacement string, caseSensitive bool) {
+		result := replaceInName(name, original, replacement, caseSensitive)
+		if isIdentifier(name) && isIdentifier(replacement) && !isIdentifier(result) {
+			t.Errorf("replaceInName(%q, %q, %q, %t) = %q, not an identifier",
+				name, original, replacement, caseSensitive, result)
+		}
+	})
+}
+
+func TestAnalyzerGetterNaming(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		GetterNaming: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "getter")
+}
+
+func TestTraceMappings(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "trace.go", "package p\n\nvar userRequest int\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ident := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.ValueSpec).Names[0]
+
+	patterns, err := buildPatterns(buildNameMappings([][]string{{"request", "req"}, {"user", "usr"}, {"server", "srv"}}), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	traceMappings(logger, &analysis.Pass{Fset: fset}, ident, patterns, false)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(patterns) {
+		t.Fatalf("got %d trace lines, want one per pattern (%d):\n%s", len(lines), len(patterns), buf.String())
+	}
+	for _, want := range []string{
+		"identifier=userRequest pattern_tested=request→req matched=true position=trace.go:3:5",
+		"identifier=userRequest pattern_tested=user→usr matched=true",
+		"identifier=userRequest pattern_tested=server→srv matched=false",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("trace output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestAnalyzerErrorNaming(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		ErrorNaming: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "errnaming")
+}
+
+func TestPatternCoverage(t *testing.T) {
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+			{"response", "res"},
+			{"user", "usr"},
+			{"temporary", "temp"},
+			{"write", "w"},
+		},
+		AllowedLongNames: []string{"ResponseWriter"},
+		ExcludeNames:     []string{"legacy*"},
+	}
+
+	coverage, err := PatternCoverage([]string{"testdata/src/coverage/coverage.go"}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{
+		"request":   4, // RequestHandler, userRequest, handleRequest, request
+		"response":  1, // userResponse
+		"user":      3, // userRequest, user, userResponse
+		"temporary": 0,
+		"write":     0, // WriteString is a standard library name
+	}
+	if len(coverage) != len(expected) {
+		t.Fatalf("PatternCoverage() = %v, want %v", coverage, expected)
+	}
+	for original, count := range expected {
+		if coverage[original] != count {
+			t.Errorf("PatternCoverage()[%q] = %d, want %d", original, coverage[original], count)
+		}
+	}
+}
+
+func TestAnalyzerBoolNaming(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		BoolNaming: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "booleans")
+}
+
+func TestBoolNamingKinds(t *testing.T) {
+	rule := buildBoolRule(Config{BoolNamingKinds: []string{BoolKindVar, BoolKindFunc}})
+	if rule.kinds[BoolKindField] {
+		t.Error("field kind enabled, want only var and func")
+	}
+
+	tests := map[string]string{
+		"enabled":  "isEnabled",
+		"valid":    "isValid",
+		"children": "hasChildren",
+		"Visible":  "IsVisible",
+		"retries":  "hasRetries",
+	}
+	for name, expected := range tests {
+		if got, ok := rule.suggest(name); !ok || got != expected {
+			t.Errorf("suggest(%q) = %q, %v; want %q", name, got, ok, expected)
+		}
+	}
+	if got, ok := rule.suggest("exists"); ok {
+		t.Errorf("suggest(%q) = %q, want no suggestion", "exists", got)
+	}
+}
+
+func TestAnalyzerNoStutter(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		NoStutter:         true,
+		StutterUnexported: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "configv2")
+}
+
+func TestSuggest(t *testing.T) {
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+			{"user", "usr"},
+			{"server", "srv"},
+			{"write", "w"},
+		},
+		AllowedLongNames: []string{"ServeHTTP"},
+		ExcludeNames:     []string{"legacy*"},
+	}
+
+	tests := []struct {
+		name     string
+		expected []Replacement
+	}{
+		{
+			name: "count",
+		},
+		{
+			name: "request",
+			expected: []Replacement{
+				{SuggestedName: "req", MatchedPattern: "request→req", Confidence: 1},
+			},
+		},
+		{
+			name: "userRequest",
+			expected: []Replacement{
+				{SuggestedName: "userReq", MatchedPattern: "request→req", Confidence: 7.0 / 11},
+				{SuggestedName: "usrRequest", MatchedPattern: "user→usr", Confidence: 4.0 / 11},
+			},
+		},
+		// Like the analyzer, standard library and excluded names are kept
+		{
+			name: "WriteString",
+		},
+		{
+			name: "legacyUserRequest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.name, config)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Suggest(%q) = %+v, want %+v", tt.name, got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Suggest(%q)[%d] = %+v, want %+v", tt.name, i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzerReplaceInTestTableNames(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+		},
+		ReplaceInTestTableNames: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "testnames")
+}
+
+func TestAnalyzerNoAllCaps(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		NoAllCaps:   true,
+		AllCapsVars: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "allcaps")
+}
+
+func TestAnalyzerLoopVars(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	// Aggressive single letter mappings must not touch idiomatic loop variables
+	config := Config{
+		Check: [][]string{
+			{"i", "index"},
+			{"j", "index"},
+			{"k", "key"},
+			{"v", "value"},
+		},
+	}
+	analysistest.Run(t, testdata, NewAnalyzer(config), "loopvars")
+
+	config.CheckLoopVars = true
+	analysistest.Run(t, testdata, NewAnalyzer(config), "loopvarschecked")
+}
+
+func TestLocalVarsRules(t *testing.T) {
+	src := `package locals
+
+var userId, extremelyLongName int
+
+func handle(values []int) {
+	userId := 1
+	for _, extremelyLongName := range values {
+		userId += extremelyLongName
+	}
+	user_name := userId
+	_ = user_name
+}
+`
+	// Only the mappings and snake_case look at := and range variables
+	config := Config{Initialisms: true, MaxLength: 12, NoSnakeCase: true}
+	fset, diagnostics, err := AnalyzeSource("locals.go", []byte(src), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, fmt.Sprintf("%d: %s", fset.Position(d.Pos).Line, d.Message))
+	}
+	if len(got) != 3 || !strings.HasPrefix(got[0], "3: ") || !strings.HasPrefix(got[1], "3: ") || !strings.Contains(got[2], "user_name") {
+		t.Errorf("diagnostics = %q, want the two package-level names and user_name", got)
+	}
+}
+
+func TestAnalyzerTransforms(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{
+			{"request", "req"},
+		},
+		Transforms: []Transform{TrimSuffix("Impl"), EnsureCamelCase, EnsureMaxLength(8)},
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "transforms")
+}
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		name        string
+		transform   Transform
+		replacement string
+		identifier  string
+		expected    string
+	}{
+		{"trim suffix", TrimSuffix("Impl"), "reqImpl", "requestImpl", "req"},
+		{"trim suffix only when the identifier has it", TrimSuffix("Impl"), "reqImpl", "reqImplFoo", "reqImpl"},
+		{"trim suffix keeps a non-empty name", TrimSuffix("Impl"), "Impl", "Impl", "Impl"},
+		{"max length", EnsureMaxLength(3), "usrReq", "userRequest", "usr"},
+		{"max length disabled", EnsureMaxLength(0), "usrReq", "userRequest", "usrReq"},
+		{"camel case", EnsureCamelCase, "req_count", "request_count", "reqCount"},
+		{"camel case keeps exported names exported", EnsureCamelCase, "req_count", "Request_count", "ReqCount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.transform("request", tt.replacement, tt.identifier); got != tt.expected {
+				t.Errorf("transform(%q, %q) = %q, want %q", tt.replacement, tt.identifier, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnalyzerSynonymConsistency(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	config := Config{
+		Check: [][]string{
+			{"cfg", "config"},
+			{"conf", "config"},
+			{"srv", "server"},
+		},
+		SynonymConsistency: true,
+	}
+
+	analyzer := NewAnalyzer(config)
+	analysistest.Run(t, testdata, analyzer, "synonyms")
+}
+
+func TestSynonymGroups(t *testing.T) {
+	groups := synonymGroups([][]string{
+		{"cfg", "config"},
+		{"configuration", "config"},
+		{"conf", "config"},
+		{"request", "req"},
+	})
+
+	if got := strings.Join(groups["config"], ","); got != "cfg,conf,configuration" {
+		t.Errorf("synonyms of config = %s, want cfg,conf,configuration", got)
+	}
+	if got := strings.Join(groups["req"], ","); got != "request" {
+		t.Errorf("synonyms of req = %s, want request", got)
+	}
+}
+
+func TestAnalyzerCgo(t *testing.T) {
+	filename := filepath.Join(analysistest.TestData(), "src", "cgo", "cgo.go")
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := Config{
+		Check:                 [][]string{{"request", "req"}},
+		NoSnakeCase:           true,
+		CheckCompositeLitKeys: true,
+	}
+
+	// The //export function, C selectors and C struct fields are left alone
+	fset, diagnostics, err := AnalyzeSource(filename, src, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, fmt.Sprintf("%d: %s", fset.Position(d.Pos).Line, d.Message))
+	}
+	want := []string{
+		"19: suggest replacing 'newRequestInfo' with 'newReqInfo'",
+		"20: suggest replacing 'requestInfo' with 'reqInfo'",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diagnostics = %q, want %q", got, want)
+	}
+
+	config.SkipCgo = true
+	if _, diagnostics, err := AnalyzeSource(filename, src, config); err != nil || len(diagnostics) != 0 {
+		t.Errorf("with SkipCgo got %d diagnostics (err %v), want the file to be skipped", len(diagnostics), err)
+	}
+}
+
+func TestAnalyzeTokensSkipsCgoSelectors(t *testing.T) {
+	src := []byte(`package broken
+
+import "C"
+
+var requestSize = C.request_size(
+`)
+	diagnostics := AnalyzeTokens(token.NewFileSet(), "broken.go", src, Config{Check: [][]string{{"request", "req"}}})
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].Message, "'requestSize'") {
+		t.Errorf("AnalyzeTokens() = %v, want only requestSize reported", diagnostics)
+	}
+}
+
+func TestAnalyzerCheckComments(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{
+		Check:         [][]string{{"request", "req"}, {"response", "res"}},
+		CheckComments: true,
+	}
+	analysistest.Run(t, testdata, NewAnalyzer(config), "comments")
+}
+
+func TestIsDirectiveComment(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"//go:generate stringer", true},
+		{"//nolint:gonamefix", true},
+		{"//lint:ignore U1000 unused", true},
+		{"//export goCallback", true},
+		{"// +build linux", true},
+		{"// TODO: handle the request", false},
+		{"//TODO: handle the request", false},
+		{"// Note: the request", false},
+		{"/* go:generate */", false},
+	}
+	for _, tt := range tests {
+		if got := isDirectiveComment(tt.text); got != tt.want {
+			t.Errorf("isDirectiveComment(%q) = %t, want %t", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzerCheckStrings(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{
+		Check: [][]string{{"request", "req"}, {"response", "res"}},
+		CheckStrings: []StringCall{
+			{Call: "slog.With"},
+			{Call: "*.WithField"},
+			{Call: "log/slog.Logger.Info", Arg: 1},
+		},
+	}
+	analysistest.RunWithSuggestedFixes(t, testdata, NewAnalyzer(config), "checkstrings")
+}
+
+func TestAnalyzerRenameCollisions(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{
+		Check: [][]string{{"request", "req"}, {"requisition", "req"}},
+	}
+	analysistest.RunWithSuggestedFixes(t, testdata, NewAnalyzer(config), "collisions")
+}
+
+func TestRenamePlanSwap(t *testing.T) {
+	src := `package swap
+
+var requestSize int
+var reqSize int
+`
+	config := Config{Check: [][]string{{"request", "req"}, {"req", "request"}}}
+	_, diagnostics, err := AnalyzeSource("swap.go", []byte(src), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 2 {
+		t.Fatalf("got %d diagnostics, want 2", len(diagnostics))
+	}
+	// Each name is renamed away, so neither rename collides
+	for _, d := range diagnostics {
+		if len(d.SuggestedFixes) != 1 {
+			t.Errorf("%s: got %d fixes, want 1", d.Message, len(d.SuggestedFixes))
+		}
+	}
+}
+
+func TestRenamePlanWithoutTypes(t *testing.T) {
+	src := `package collide
+
+var requestTimeout int
+var reqTimeout int
+`
+	config := Config{Check: [][]string{{"request", "req"}}}
+	fset, diagnostics, err := AnalyzeSource("collide.go", []byte(src), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(diagnostics))
+	}
+	d := diagnostics[0]
+	if len(d.SuggestedFixes) != 0 {
+		t.Errorf("got a fix for a rename colliding with an existing declaration")
+	}
+	if len(d.Related) != 1 || fset.Position(d.Related[0].Pos).Line != 4 {
+		t.Errorf("related = %v, want the declaration of reqTimeout on line 4", d.Related)
+	}
+}
+
+func TestAnalyzerRelatedOccurrences(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{Check: [][]string{{"request", "req"}}}
+
+	results := analysistest.Run(t, testdata, NewAnalyzer(config), "related")
+	if len(results) != 1 || len(results[0].Diagnostics) != 1 {
+		t.Fatalf("got %d results, want one diagnostic", len(results))
+	}
+	fset := results[0].Pass.Fset
+	var lines []int
+	for _, related := range results[0].Diagnostics[0].Related {
+		lines = append(lines, fset.Position(related.Pos).Line)
+	}
+	if fmt.Sprint(lines) != "[6 10]" {
+		t.Errorf("related lines = %v, want [6 10]", lines)
+	}
+
+	// Without type information occurrences are resolved within the file
+	src, err := os.ReadFile(filepath.Join(testdata, "src", "related", "related.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.MaxRelated = 1
+	_, diagnostics, err := AnalyzeSource("related.go", src, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 1 || len(diagnostics[0].Related) != 1 {
+		t.Fatalf("got %v, want one diagnostic with one related occurrence", diagnostics)
+	}
+
+	config.MaxRelated = -1
+	if _, diagnostics, _ := AnalyzeSource("related.go", src, config); len(diagnostics[0].Related) != 0 {
+		t.Errorf("got %d related occurrences, want none with a negative MaxRelated", len(diagnostics[0].Related))
+	}
+}
+
+func TestAnalyzerTypeScopedMappings(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{
+		Mappings: []Mapping{
+			{Original: "request", Replacement: "req", Types: []string{"internal/api.Handler"}},
+			{Original: "response", Replacement: "res", Types: []string{"internal/api.*"}},
+		},
+		CheckCompositeLitKeys: true,
+	}
+	analysistest.Run(t, testdata, NewAnalyzer(config), "typescope/internal/api", "typescope/other")
+
+	config.Mappings[0].Types = []string{"Handler"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `types pattern "Handler"`) {
+		t.Errorf("Validate() = %v, want the unqualified types pattern reported", err)
+	}
+}
+
+func TestAnalyzerMappingKinds(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{
+		Check:    [][]string{{"configuration", "config"}},
+		Mappings: []Mapping{{Original: "request", Replacement: "req", Kinds: []string{KindParam, KindVar}}},
+	}
+	analysistest.Run(t, testdata, NewAnalyzer(config), "kinds")
+
+	config.Mappings[0].Kinds = []string{"parameter"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `unknown kind "parameter"`) {
+		t.Errorf("Validate() = %v, want the unknown kind reported", err)
+	}
+}
+
+func TestAnalyzerOnlyKinds(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{
+		Check:                 [][]string{{"request", "req"}},
+		OnlyKinds:             []string{KindFunc, KindParam},
+		CheckCompositeLitKeys: true,
+	}
+	analysistest.Run(t, testdata, NewAnalyzer(config), "onlykinds")
+
+	config.OnlyKinds = []string{"function"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `only-kinds: unknown kind "function"`) {
+		t.Errorf("Validate() = %v, want the unknown kind reported", err)
+	}
+}
+
+func TestDeclarationNodesFor(t *testing.T) {
+	if got, want := len(declarationNodesFor(nil, true)), len(declarationNodes); got != want {
+		t.Errorf("declarationNodesFor(nil) has %d nodes, want all %d", got, want)
+	}
+	// Types need their specs only
+	got := declarationNodesFor(map[string]bool{KindType: true}, true)
+	want := []ast.Node{(*ast.File)(nil), (*ast.TypeSpec)(nil)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("declarationNodesFor(type) = %T, want %T", got, want)
+	}
+}
+
+func TestAnalyzerRuleIDs(t *testing.T) {
+	testdata := analysistest.TestData()
+	config := Config{
+		Check:       [][]string{{"response", "res"}},
+		Mappings:    []Mapping{{Original: "request", Replacement: "req", ID: "team/short-request", URL: "https://wiki.example.com/req"}},
+		DocsBaseURL: "https://docs.example.com/naming#{id}",
+	}
+
+	results := analysistest.Run(t, testdata, NewAnalyzer(config), "ruleids")
+	var categories []string
+	for _, d := range results[0].Diagnostics {
+		categories = append(categories, d.Category+" "+d.URL)
+	}
+	sort.Strings(categories)
+	want := []string{
+		"gonamefix/response-res https://docs.example.com/naming#gonamefix/response-res",
+		"team/short-request https://wiki.example.com/req",
+	}
+	if strings.Join(categories, "\n") != strings.Join(want, "\n") {
+		t.Errorf("categories = %q, want %q", categories, want)
+	}
+}
+
+func TestInventory(t *testing.T) {
+	src := `package a
+
+type Server struct {
+	httpClient string
+}
+
+func (s *Server) HandleRequest(userID int) (ok bool) {
+	for i := 0; i < userID; i++ {
+		retryCount := i
+		_ = retryCount
+	}
+	return true
+}
+
+const maxRetries = 3
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "a.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	excluded, err := parser.ParseFile(fset, "a.pb.go", "package a\n\nvar generatedName int\n", parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identifiers, err := Inventory(fset, []*ast.File{file, excluded}, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, id := range identifiers {
+		got = append(got, fmt.Sprintf("%d:%s %s %v %s", id.Line, id.Name, id.Kind, id.Exported, strings.Join(id.Words, "/")))
+	}
+	want := []string{
+		"3:Server type true Server",
+		"4:httpClient field false http/Client",
+		"7:HandleRequest method true Handle/Request",
+		"7:userID param false user/ID",
+		"7:ok result false ok",
+		"7:s param false s",
+		"9:retryCount var false retry/Count",
+		"15:maxRetries const false max/Retries",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Inventory() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFilterMappings(t *testing.T) {
+	config := Config{
+		Check:    [][]string{{"request", "req"}, {"response", "res"}, {"password", "pwd"}},
+		Mappings: []Mapping{{Original: "user", Replacement: "usr", ID: "naming/user"}},
+	}
+
+	tests := []struct {
+		only, skip []string
+		want       string
+		wantErr    string
+	}{
+		{only: []string{"Request", "gonamefix/password-pwd"}, want: "request password"},
+		{only: []string{"naming/user"}, want: "user"},
+		{skip: []string{"response", "user"}, want: "request password"},
+		{only: []string{"request", "response"}, skip: []string{"response"}, want: "request"},
+		{only: []string{"request", "reqest"}, want: "request", wantErr: `"reqest" matches no mapping`},
+		{skip: []string{"gonamefix/user-usr"}, want: "request response password user", wantErr: `"gonamefix/user-usr" matches no mapping`},
+	}
+	for _, tt := range tests {
+		filtered, err := config.FilterMappings(tt.only, tt.skip)
+		var originals []string
+		for _, pair := range filtered.MappingPairs() {
+			originals = append(originals, pair[0])
+		}
+		if got := strings.Join(originals, " "); got != tt.want {
+			t.Errorf("FilterMappings(%q, %q) kept %q, want %q", tt.only, tt.skip, got, tt.want)
+		}
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("FilterMappings(%q, %q) error = %v, want %q", tt.only, tt.skip, err, tt.wantErr)
+		}
+	}
+	if len(config.Check) != 3 {
+		t.Errorf("FilterMappings() modified the receiver")
+	}
+}
@@ -0,0 +1,93 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// hungarianCategory is the diagnostic category used by the Hungarian notation rule.
+const hungarianCategory = "hungarian"
+
+// DefaultHungarianPrefixes returns the built-in list of type prefixes
+// recognized by the Hungarian notation rule.
+func DefaultHungarianPrefixes() []string {
+	return []string{"str", "sz", "lp", "dw", "i", "n", "b", "p", "f", "m_"}
+}
+
+// DefaultHungarianExceptions returns names that look like Hungarian notation
+// but are ordinary words or brand names.
+func DefaultHungarianExceptions() []string {
+	return []string{"iOS", "iPhone", "iPad", "iPod", "iMac", "iCloud", "eBay", "bTree", "pH"}
+}
+
+type hungarianRule struct {
+	prefixes   []string
+	exceptions map[string]bool
+}
+
+func buildHungarianRule(config Config) *hungarianRule {
+	prefixes := config.HungarianPrefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultHungarianPrefixes()
+	}
+
+	exceptions := make(map[string]bool)
+	for _, name := range DefaultHungarianExceptions() {
+		exceptions[name] = true
+	}
+	for _, name := range config.HungarianExceptions {
+		exceptions[name] = true
+	}
+
+	return &hungarianRule{prefixes: prefixes, exceptions: exceptions}
+}
+
+// strip returns name without its Hungarian prefix. The prefix must be followed
+// by an upper case letter (strName, not string), or by any letter for prefixes
+// ending in an underscore (m_count). The longest matching prefix wins.
+func (r *hungarianRule) strip(name string) (string, bool) {
+	if r.exceptions[name] {
+		return name, false
+	}
+
+	best := ""
+	for _, prefix := range r.prefixes {
+		if len(prefix) <= len(best) || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		next, _ := utf8.DecodeRuneInString(name[len(prefix):])
+		if strings.HasSuffix(prefix, "_") && unicode.IsLetter(next) || unicode.IsUpper(next) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return name, false
+	}
+
+	// The remainder becomes a lowerCamel name; a leading initialism is lowered as a whole (pURL -> url)
+	words := splitWords(name[len(best):])
+	if len(words) == 0 {
+		return name, false
+	}
+	if words[0] == strings.ToUpper(words[0]) {
+		words[0] = strings.ToLower(words[0])
+	} else {
+		first, size := utf8.DecodeRuneInString(words[0])
+		words[0] = string(unicode.ToLower(first)) + words[0][size:]
+	}
+
+	stripped := strings.Join(words, "")
+	if token.IsKeyword(stripped) || isGoKeyword(stripped) {
+		return name, false
+	}
+	return stripped, true
+}
+
+func checkHungarian(ident *ast.Ident, rule *hungarianRule, plan *renamePlan) {
+	if suggestedName, ok := rule.strip(ident.Name); ok {
+		plan.add(ident, suggestedName, hungarianCategory, "")
+	}
+}
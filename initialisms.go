@@ -0,0 +1,79 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+)
+
+// initialismCategory is the diagnostic category used by the initialism rule.
+const initialismCategory = "initialism"
+
+// DefaultInitialisms returns the built-in list of initialisms, following the
+// list used by golint and the Go code review comments.
+func DefaultInitialisms() []string {
+	return []string{
+		"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+		"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+		"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+		"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+	}
+}
+
+func buildInitialisms(config Config) map[string]bool {
+	list := config.InitialismList
+	if len(list) == 0 {
+		list = DefaultInitialisms()
+	}
+
+	initialisms := make(map[string]bool, len(list)+len(config.ExtraInitialisms))
+	for _, word := range list {
+		initialisms[strings.ToUpper(word)] = true
+	}
+	for _, word := range config.ExtraInitialisms {
+		initialisms[strings.ToUpper(word)] = true
+	}
+	return initialisms
+}
+
+// normalizeInitialisms rewrites every initialism in name to a consistent case.
+// An initialism that starts a lowerCamel name stays lower case (idList), any
+// other occurrence is upper-cased (userId -> userID, IdList -> IDList).
+func normalizeInitialisms(name string, initialisms map[string]bool) string {
+	words := splitWords(name)
+	if len(words) == 0 || strings.Contains(name, "_") {
+		// Underscore separated names are handled by the snake case rules
+		return name
+	}
+
+	changed := false
+	for i, word := range words {
+		upper := strings.ToUpper(word)
+		if !initialisms[upper] || word == upper {
+			continue
+		}
+		if i == 0 && word == strings.ToLower(word) {
+			continue
+		}
+		words[i] = upper
+		changed = true
+	}
+
+	if !changed {
+		return name
+	}
+	return strings.Join(words, "")
+}
+
+func checkInitialisms(ident *ast.Ident, initialisms map[string]bool, plan *renamePlan) {
+	if ident.Name == "" || isGoKeyword(ident.Name) || !unicode.IsLetter([]rune(ident.Name)[0]) {
+		return
+	}
+
+	suggestedName := normalizeInitialisms(ident.Name, initialisms)
+	if suggestedName == ident.Name {
+		return
+	}
+
+	plan.add(ident, suggestedName, initialismCategory, "")
+}
@@ -0,0 +1,98 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// interfaceNameCategory is the diagnostic category used by the interface naming rule.
+const interfaceNameCategory = "interface-name"
+
+// DefaultInterfaceNameExceptions returns established single-method interface
+// names that do not follow the method+er convention.
+func DefaultInterfaceNameExceptions() []string {
+	return []string{
+		"Handler", "Marshaler", "Unmarshaler", "TextMarshaler", "TextUnmarshaler",
+		"BinaryMarshaler", "BinaryUnmarshaler", "Valuer", "Interface",
+	}
+}
+
+// agentNouns returns the accepted interface names for a method: Read -> Reader,
+// Close -> Closer, Stop -> Stopper, Generate -> Generator.
+func agentNouns(method string) []string {
+	nouns := []string{method + "er"}
+	last, _ := utf8.DecodeLastRuneInString(method)
+	switch {
+	case last == 'e':
+		stem := strings.TrimSuffix(method, "e")
+		nouns = append(nouns, method+"r", stem+"or")
+	case strings.ContainsRune("aeiou", last):
+		nouns = append(nouns, method+"or")
+	default:
+		nouns = append(nouns, method+string(last)+"er", method+"or")
+	}
+	return nouns
+}
+
+// conventionalInterfaceName returns the suggested name for an interface with the given method.
+func conventionalInterfaceName(method string) string {
+	if strings.HasSuffix(method, "e") {
+		return method + "r"
+	}
+	return method + "er"
+}
+
+// followsInterfaceConvention reports whether name is an agent noun of method,
+// optionally with a qualifying prefix (FileReader for Read). The comparison
+// ignores the case of the first letter so unexported interfaces are accepted.
+func followsInterfaceConvention(name, method string) bool {
+	for _, noun := range agentNouns(method) {
+		if strings.EqualFold(name, noun) || strings.HasSuffix(name, noun) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkInterfaceName(pass *analysis.Pass, spec *ast.TypeSpec, exceptions map[string]bool) {
+	iface, ok := spec.Type.(*ast.InterfaceType)
+	if !ok || spec.Name == nil || exceptions[spec.Name.Name] {
+		return
+	}
+
+	// Only interfaces with exactly one method and nothing embedded are checked
+	if iface.Methods == nil || len(iface.Methods.List) != 1 {
+		return
+	}
+	field := iface.Methods.List[0]
+	if len(field.Names) != 1 {
+		return
+	}
+	if _, ok := field.Type.(*ast.FuncType); !ok {
+		return
+	}
+
+	method := field.Names[0].Name
+	if followsInterfaceConvention(spec.Name.Name, method) {
+		return
+	}
+
+	suggestedName := conventionalInterfaceName(method)
+	if !spec.Name.IsExported() {
+		first, size := utf8.DecodeRuneInString(suggestedName)
+		suggestedName = string(unicode.ToLower(first)) + suggestedName[size:]
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:      spec.Name.Pos(),
+		End:      spec.Name.End(),
+		Category: interfaceNameCategory,
+		Message: fmt.Sprintf("single-method interface '%s' should be named after its method %s, e.g. '%s'",
+			spec.Name.Name, method, suggestedName),
+	})
+}
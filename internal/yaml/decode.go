@@ -0,0 +1,172 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal decodes data into v, which must be a pointer to a struct. Struct
+// fields are matched by their mapstructure tag, or their lower-cased name
+// without one; fields tagged "-" are never set. Keys without a matching
+// field are an error, so a typo in a configuration file is reported instead
+// of being ignored. Errors carry the line and the path of the value, e.g.
+// "line 4: mappings[0].kinds: expected a sequence".
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("yaml: Unmarshal needs a non-nil pointer, got %T", v)
+	}
+	root, err := parse(data)
+	if err != nil {
+		return err
+	}
+	if isNull(root) {
+		return nil
+	}
+	return decode(root, rv.Elem(), "")
+}
+
+func decode(n node, v reflect.Value, path string) error {
+	if isNull(n) {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decode(n, v.Elem(), path)
+	case reflect.Struct:
+		return decodeStruct(n, v, path)
+	case reflect.Slice:
+		s, ok := n.(*sequence)
+		if !ok {
+			return mismatch(n, path, "a sequence")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(s.items), len(s.items))
+		for i, item := range s.items {
+			if err := decode(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Map:
+		m, ok := n.(*mapping)
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return mismatch(n, path, "a mapping")
+		}
+		result := reflect.MakeMapWithSize(v.Type(), len(m.keys))
+		for _, key := range m.keys {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decode(m.values[key], elem, join(path, key)); err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+		v.Set(result)
+		return nil
+	}
+
+	s, ok := n.(*scalar)
+	if !ok {
+		return mismatch(n, path, "a "+v.Kind().String())
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s.value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s.value)
+		if err != nil || s.quoted {
+			return errorf(s.at, "%s: expected true or false, found %q", describe(path), s.value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s.value, 0, v.Type().Bits())
+		if err != nil {
+			return errorf(s.at, "%s: expected an integer, found %q", describe(path), s.value)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s.value, 0, v.Type().Bits())
+		if err != nil {
+			return errorf(s.at, "%s: expected an unsigned integer, found %q", describe(path), s.value)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s.value, v.Type().Bits())
+		if err != nil {
+			return errorf(s.at, "%s: expected a number, found %q", describe(path), s.value)
+		}
+		v.SetFloat(f)
+	default:
+		return errorf(s.at, "%s: cannot decode into %s", describe(path), v.Type())
+	}
+	return nil
+}
+
+func decodeStruct(n node, v reflect.Value, path string) error {
+	m, ok := n.(*mapping)
+	if !ok {
+		return mismatch(n, path, "a mapping")
+	}
+
+	fields := make(map[string]int)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = i
+	}
+
+	for _, key := range m.keys {
+		i, ok := fields[key]
+		if !ok && path == "" {
+			return errorf(m.values[key].line(), "unknown key %q", key)
+		}
+		if !ok {
+			return errorf(m.values[key].line(), "%s: unknown key %q", path, key)
+		}
+		if err := decode(m.values[key], v.Field(i), join(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mismatch(n node, path, want string) error {
+	found := "a scalar"
+	switch n.(type) {
+	case *mapping:
+		found = "a mapping"
+	case *sequence:
+		found = "a sequence"
+	}
+	return errorf(n.line(), "%s: expected %s, found %s", describe(path), want, found)
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func describe(path string) string {
+	if path == "" {
+		return "document"
+	}
+	return path
+}
@@ -0,0 +1,433 @@
+// Package yaml parses the subset of YAML used by gonamefix configuration and
+// mapping files, and decodes it into structs by their mapstructure tags, so
+// that the module needs no third-party YAML library.
+//
+// Supported are block mappings and sequences, flow sequences and mappings
+// such as [request, req], plain, single-quoted and double-quoted scalars and
+// comments. Anchors, tags, multi-line scalars and multiple documents are not.
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// node is a parsed value: *mapping, *sequence or *scalar.
+type node interface {
+	line() int
+}
+
+// mapping is a YAML mapping with its keys in document order.
+type mapping struct {
+	at     int
+	keys   []string
+	values map[string]node
+}
+
+// sequence is a YAML sequence.
+type sequence struct {
+	at    int
+	items []node
+}
+
+// scalar is a YAML scalar. Quoted scalars are always strings; plain ones are
+// interpreted by the type they are decoded into.
+type scalar struct {
+	at     int
+	value  string
+	quoted bool
+}
+
+func (m *mapping) line() int  { return m.at }
+func (s *sequence) line() int { return s.at }
+func (s *scalar) line() int   { return s.at }
+
+// isNull reports whether n is an empty or null value.
+func isNull(n node) bool {
+	s, ok := n.(*scalar)
+	return ok && !s.quoted && (s.value == "" || s.value == "~" || s.value == "null")
+}
+
+// Error is a syntax or decoding error at a line of the document.
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+func errorf(line int, format string, args ...any) error {
+	return &Error{Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// sourceLine is a non-blank line without its comment.
+type sourceLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// parser parses block structure line by line; flow collections and scalars
+// within a line are parsed by flowParser.
+type parser struct {
+	lines []sourceLine
+	pos   int
+}
+
+// parse parses a document. An empty document is an empty mapping.
+func parse(data []byte) (node, error) {
+	p := &parser{}
+	for i, text := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text = stripComment(text)
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, errorf(i+1, "tabs are not allowed for indentation")
+		}
+		if i == 0 || len(p.lines) == 0 {
+			if t := strings.TrimSpace(trimmed); t == "---" {
+				continue
+			}
+		}
+		if strings.TrimSpace(trimmed) == "..." {
+			break
+		}
+		p.lines = append(p.lines, sourceLine{number: i + 1, indent: len(text) - len(trimmed), text: strings.TrimRight(trimmed, " \t")})
+	}
+	if len(p.lines) == 0 {
+		return &mapping{at: 1, values: map[string]node{}}, nil
+	}
+
+	n, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, errorf(p.lines[p.pos].number, "unexpected indentation")
+	}
+	return n, nil
+}
+
+// stripComment removes a comment, a # at the start of the line or after
+// whitespace, outside of quotes.
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// parseBlock parses the mapping, sequence or single value starting at the
+// current line, which is indented by indent.
+func (p *parser) parseBlock(indent int) (node, error) {
+	line := p.lines[p.pos]
+	if isSequenceItem(line.text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitKey(line.text); ok {
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return parseFlow(line.text, line.number)
+}
+
+func (p *parser) parseMapping(indent int) (node, error) {
+	m := &mapping{at: p.lines[p.pos].number, values: map[string]node{}}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, errorf(line.number, "unexpected indentation")
+		}
+		key, rest, ok := splitKey(line.text)
+		if !ok {
+			return nil, errorf(line.number, "expected a key, found %q", line.text)
+		}
+		if _, dup := m.values[key]; dup {
+			return nil, errorf(line.number, "duplicate key %q", key)
+		}
+		p.pos++
+
+		value, err := p.parseValue(rest, line, indent)
+		if err != nil {
+			return nil, err
+		}
+		m.keys = append(m.keys, key)
+		m.values[key] = value
+	}
+	return m, nil
+}
+
+// parseValue parses the value of a key or sequence item: rest of its line, or
+// the block below it when the line ends after the key.
+func (p *parser) parseValue(rest string, line sourceLine, indent int) (node, error) {
+	if rest != "" {
+		return parseFlow(rest, line.number)
+	}
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		// A sequence may start at the indentation of its key
+		if next.indent > indent || next.indent == indent && isSequenceItem(next.text) && !isSequenceItem(line.text) {
+			return p.parseBlock(next.indent)
+		}
+	}
+	return &scalar{at: line.number}, nil
+}
+
+func (p *parser) parseSequence(indent int) (node, error) {
+	s := &sequence{at: p.lines[p.pos].number}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || line.indent == indent && !isSequenceItem(line.text) {
+			break
+		}
+		if line.indent > indent {
+			return nil, errorf(line.number, "unexpected indentation")
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if _, _, isKey := splitKey(rest); isKey || isSequenceItem(rest) {
+			// The item is a block starting on the same line: continue it as a
+			// line of its own, indented to where it starts
+			p.lines[p.pos] = sourceLine{number: line.number, indent: indent + len(line.text) - len(rest), text: rest}
+			item, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			s.items = append(s.items, item)
+			continue
+		}
+
+		p.pos++
+		item, err := p.parseValue(rest, line, indent)
+		if err != nil {
+			return nil, err
+		}
+		s.items = append(s.items, item)
+	}
+	return s, nil
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits a "key: value" line. Keys may be quoted; a line starting
+// with a flow collection or quoted scalar without a colon after it is no key.
+func splitKey(text string) (key, rest string, ok bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' || isSequenceItem(text) {
+		return "", "", false
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		after := text[end+1:]
+		if after != ":" && !strings.HasPrefix(after, ": ") {
+			return "", "", false
+		}
+		unquoted, err := unquote(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return unquoted, strings.TrimSpace(after[1:]), true
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i == len(text)-1 || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// closingQuote returns the index of the quote closing the scalar text starts
+// with, or -1.
+func closingQuote(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unquote returns the value of a quoted scalar.
+func unquote(text string) (string, error) {
+	if text[0] == '\'' {
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+	return strconv.Unquote(text)
+}
+
+// flowParser parses a value written on a single line: a flow collection or a
+// scalar.
+type flowParser struct {
+	text string
+	pos  int
+	line int
+}
+
+func parseFlow(text string, line int) (node, error) {
+	f := &flowParser{text: text, line: line}
+	n, err := f.value(false)
+	if err != nil {
+		return nil, err
+	}
+	f.skipSpaces()
+	if f.pos < len(f.text) {
+		return nil, errorf(line, "unexpected %q after value", f.text[f.pos:])
+	}
+	return n, nil
+}
+
+func (f *flowParser) skipSpaces() {
+	for f.pos < len(f.text) && f.text[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+// value parses a collection or scalar. Inside a collection plain scalars end
+// at the next separator.
+func (f *flowParser) value(inFlow bool) (node, error) {
+	f.skipSpaces()
+	if f.pos >= len(f.text) {
+		return &scalar{at: f.line}, nil
+	}
+	switch f.text[f.pos] {
+	case '[':
+		return f.sequence()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		end := closingQuote(f.text[f.pos:])
+		if end < 0 {
+			return nil, errorf(f.line, "unterminated quoted string")
+		}
+		value, err := unquote(f.text[f.pos : f.pos+end+1])
+		if err != nil {
+			return nil, errorf(f.line, "invalid quoted string: %v", err)
+		}
+		f.pos += end + 1
+		return &scalar{at: f.line, value: value, quoted: true}, nil
+	}
+
+	start := f.pos
+	for f.pos < len(f.text) {
+		c := f.text[f.pos]
+		if inFlow && (c == ',' || c == ']' || c == '}' || c == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ')) {
+			break
+		}
+		f.pos++
+	}
+	return &scalar{at: f.line, value: strings.TrimSpace(f.text[start:f.pos])}, nil
+}
+
+func (f *flowParser) sequence() (node, error) {
+	s := &sequence{at: f.line}
+	f.pos++ // [
+	for {
+		f.skipSpaces()
+		if f.pos >= len(f.text) {
+			return nil, errorf(f.line, "unterminated flow sequence")
+		}
+		if f.text[f.pos] == ']' {
+			f.pos++
+			return s, nil
+		}
+		item, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		s.items = append(s.items, item)
+		if err := f.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *flowParser) mapping() (node, error) {
+	m := &mapping{at: f.line, values: map[string]node{}}
+	f.pos++ // {
+	for {
+		f.skipSpaces()
+		if f.pos >= len(f.text) {
+			return nil, errorf(f.line, "unterminated flow mapping")
+		}
+		if f.text[f.pos] == '}' {
+			f.pos++
+			return m, nil
+		}
+		key, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		k, ok := key.(*scalar)
+		if !ok {
+			return nil, errorf(f.line, "flow mapping keys must be scalars")
+		}
+		f.skipSpaces()
+		if f.pos >= len(f.text) || f.text[f.pos] != ':' {
+			return nil, errorf(f.line, "expected ':' after key %q", k.value)
+		}
+		f.pos++
+		value, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m.values[k.value]; dup {
+			return nil, errorf(f.line, "duplicate key %q", k.value)
+		}
+		m.keys = append(m.keys, k.value)
+		m.values[k.value] = value
+		if err := f.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the comma after a collection item, leaving a closing
+// bracket for the caller.
+func (f *flowParser) separator(closing byte) error {
+	f.skipSpaces()
+	if f.pos < len(f.text) && f.text[f.pos] == ',' {
+		f.pos++
+		return nil
+	}
+	if f.pos >= len(f.text) && closing == ']' {
+		return errorf(f.line, "unterminated flow sequence")
+	}
+	if f.pos >= len(f.text) {
+		return errorf(f.line, "unterminated flow mapping")
+	}
+	if f.text[f.pos] == closing {
+		return nil
+	}
+	return errorf(f.line, "expected ',' or '%c'", closing)
+}
@@ -0,0 +1,108 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type rule struct {
+	Pattern string `mapstructure:"pattern"`
+	Reason  string `mapstructure:"reason"`
+}
+
+type config struct {
+	Check         [][]string        `mapstructure:"check"`
+	ExcludeFiles  []string          `mapstructure:"exclude-files"`
+	Exclude       []rule            `mapstructure:"exclude"`
+	CaseSensitive bool              `mapstructure:"case-sensitive"`
+	MaxLength     int               `mapstructure:"max-length"`
+	DocsBaseURL   string            `mapstructure:"docs-base-url"`
+	Labels        map[string]string `mapstructure:"labels"`
+	Ignored       string            `mapstructure:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	src := `---
+# gonamefix configuration
+check:
+  - [request, req]
+  - ["response", 'res'] # quoted
+- [wrong, indent]
+`
+	var c config
+	if err := Unmarshal([]byte(src), &c); err == nil {
+		t.Fatal("Unmarshal() accepted a sequence item after the mapping ended")
+	}
+
+	src = `---
+# gonamefix configuration
+check:
+  - [request, req]
+  - ["response", 'res'] # quoted
+  -
+    - configuration
+    - config
+exclude-files:
+- "*.pb.go"
+- '*_test.go'
+exclude:
+  - pattern: "**/*_string.go"
+    reason: stringer output
+  - {pattern: /gen/**, reason: "generated: do not edit"}
+case-sensitive: true
+max-length: 30
+docs-base-url: https://wiki.example.com/naming#{id}
+labels: {team: platform}
+`
+	c = config{}
+	if err := Unmarshal([]byte(src), &c); err != nil {
+		t.Fatal(err)
+	}
+	want := config{
+		Check:         [][]string{{"request", "req"}, {"response", "res"}, {"configuration", "config"}},
+		ExcludeFiles:  []string{"*.pb.go", "*_test.go"},
+		Exclude:       []rule{{"**/*_string.go", "stringer output"}, {"/gen/**", "generated: do not edit"}},
+		CaseSensitive: true,
+		MaxLength:     30,
+		DocsBaseURL:   "https://wiki.example.com/naming#{id}",
+		Labels:        map[string]string{"team": "platform"},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Unmarshal() = %+v\nwant %+v", c, want)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"check:\n  - [request, req]\nexclude-file: ['*.go']\n", `line 3: unknown key "exclude-file"`},
+		{"exclude:\n  - pattern: a\n    reasons: b\n", `line 3: exclude[0]: unknown key "reasons"`},
+		{"case-sensitive: yes please\n", `line 1: case-sensitive: expected true or false`},
+		{"max-length: long\n", `line 1: max-length: expected an integer`},
+		{"check: request\n", `line 1: check: expected a sequence, found a scalar`},
+		{"check:\n  - [request, req\n", `line 2: unterminated flow sequence`},
+		{"check: []\ncheck: []\n", `line 2: duplicate key "check"`},
+		{"check:\n\t- [a, b]\n", `line 2: tabs are not allowed`},
+		{"check:\n  - [a, b]\n    - [c, d]\n", `line 3: unexpected indentation`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var c config
+			err := Unmarshal([]byte(tt.src), &c)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Unmarshal(%q) = %v, want %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalEmpty(t *testing.T) {
+	c := config{MaxLength: 10}
+	if err := Unmarshal([]byte("# nothing here\n"), &c); err != nil || c.MaxLength != 10 {
+		t.Errorf("Unmarshal() of an empty document = %+v, %v; want the value unchanged", c, err)
+	}
+}
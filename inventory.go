@@ -0,0 +1,56 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Identifier is a declared identifier as the analyzer sees it.
+type Identifier struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Exported bool   `json:"exported"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	// Words is the segmentation the mappings are matched against
+	Words []string `json:"words"`
+}
+
+// Inventory returns the identifiers declared in files, in the order the
+// analyzer visits them, whatever the mappings of config. Files and names the
+// analyzer would not check, such as excluded files and keywords, are left
+// out, so the inventory matches what the analyzer sees. Like AnalyzeFile it
+// works without type information.
+func Inventory(fset *token.FileSet, files []*ast.File, config Config) ([]Identifier, error) {
+	compiled, err := compileConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	pass := &analysis.Pass{Fset: fset, Files: files}
+	_, skipped := filterFiles(pass, config, compiled)
+
+	var identifiers []Identifier
+	declare := func(ident *ast.Ident, kind string) {
+		if ident.Name == "_" || isGoKeyword(ident.Name) {
+			return
+		}
+		pos := fset.Position(ident.Pos())
+		identifiers = append(identifiers, Identifier{
+			Name:     ident.Name,
+			Kind:     kind,
+			Exported: ident.IsExported(),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Words:    splitWords(ident.Name),
+		})
+	}
+	// Local variables are listed too, which the mappings look at
+	walkDeclarations(pass, inspector.New(files), skipped, config.CheckLoopVars, compiled.kinds, nil, declare, declare, nil)
+	return identifiers, nil
+}
@@ -0,0 +1,110 @@
+package gonamefix
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Kinds of declarations a mapping can be restricted to with Mapping.Kinds.
+const (
+	KindFunc   = "func"
+	KindMethod = "method"
+	KindType   = "type"
+	KindVar    = "var"
+	KindConst  = "const"
+	KindField  = "field"
+	KindParam  = "param"
+	KindResult = "result"
+)
+
+// declKinds lists every declaration kind, in documentation order.
+var declKinds = []string{KindFunc, KindMethod, KindType, KindVar, KindConst, KindField, KindParam, KindResult}
+
+// validateKind reports whether kind is one of the declaration kinds.
+func validateKind(kind string) error {
+	for _, known := range declKinds {
+		if kind == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown kind %q (expected one of %v)", kind, declKinds)
+}
+
+// compileKinds returns the set of kinds, or nil for every kind when kinds is
+// empty. Unknown kinds are reported in the error.
+func compileKinds(kinds []string) (map[string]bool, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	set := make(map[string]bool, len(kinds))
+	var errs []error
+	for _, kind := range kinds {
+		if err := validateKind(kind); err != nil {
+			errs = append(errs, fmt.Errorf("only-kinds: %w", err))
+			continue
+		}
+		set[kind] = true
+	}
+	return set, errors.Join(errs...)
+}
+
+// valueKind returns KindConst or KindVar for a name declared by a value spec.
+func valueKind(pass *analysis.Pass, ident *ast.Ident) string {
+	if pass.TypesInfo != nil {
+		if _, ok := pass.TypesInfo.Defs[ident].(*types.Const); ok {
+			return KindConst
+		}
+		return KindVar
+	}
+	//nolint:staticcheck // ast.Object is the only resolution available without type information
+	if ident.Obj != nil && ident.Obj.Kind == ast.Con {
+		return KindConst
+	}
+	return KindVar
+}
+
+// recordKinds records kind for the names declared by fields.
+func recordKinds(kinds map[*ast.Ident]string, fields *ast.FieldList, kind string) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			kinds[name] = kind
+		}
+	}
+}
+
+// patternsFor returns the patterns that apply to an identifier of the given
+// declaration kind owned by owner: every unrestricted pattern, and the
+// restricted ones whose types match owner and whose kinds include kind.
+// Identifiers that are no declarations, like words in comments, have neither
+// owner nor kind and only get unrestricted patterns.
+func patternsFor(patterns []namePattern, owner *types.Named, kind string) []namePattern {
+	applicable := make([]namePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if len(pattern.types) > 0 && !matchesType(owner, pattern.types) {
+			continue
+		}
+		if pattern.kinds != nil && !pattern.kinds[kind] {
+			continue
+		}
+		applicable = append(applicable, pattern)
+	}
+	return applicable
+}
+
+// hasRestrictedPatterns reports whether any of patterns is restricted to
+// types or declaration kinds.
+func hasRestrictedPatterns(patterns []namePattern) bool {
+	for _, pattern := range patterns {
+		if len(pattern.types) > 0 || pattern.kinds != nil {
+			return true
+		}
+	}
+	return false
+}
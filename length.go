@@ -0,0 +1,48 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// maxLengthCategory is the diagnostic category used by the max-length rule.
+const maxLengthCategory = "max-length"
+
+func checkMaxLength(pass *analysis.Pass, ident *ast.Ident, maxLength int, patterns []namePattern, caseSensitive bool) {
+	length := utf8.RuneCountInString(ident.Name)
+	if length <= maxLength {
+		return
+	}
+
+	message := fmt.Sprintf("'%s' is %d chars (max %d)", ident.Name, length, maxLength)
+	if shortened := abbreviate(ident.Name, patterns, caseSensitive); shortened != ident.Name {
+		message += fmt.Sprintf("; applying known abbreviations gives '%s' (%d)", shortened, utf8.RuneCountInString(shortened))
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:      ident.Pos(),
+		End:      ident.End(),
+		Category: maxLengthCategory,
+		Message:  message,
+	})
+}
+
+// abbreviate applies every pattern to every word of name, unlike suggestName
+// which stops at the first matching pattern and occurrence.
+func abbreviate(name string, patterns []namePattern, caseSensitive bool) string {
+	for _, pattern := range patterns {
+		// Each pass replaces one occurrence; cap the passes in case a
+		// replacement reintroduces its original
+		for i := 0; i < len(name); i++ {
+			replaced := replaceInName(name, pattern.original, pattern.replacement, caseSensitive)
+			if replaced == name {
+				break
+			}
+			name = replaced
+		}
+	}
+	return name
+}
@@ -0,0 +1,303 @@
+// Package lsp implements the small part of the Language Server Protocol needed
+// to offer gonamefix suggested fixes as quick fix code actions in an editor.
+//
+// The server speaks JSON-RPC 2.0 over a stream such as stdin/stdout, keeps the
+// text of open documents in memory (full document sync) and answers
+// textDocument/codeAction requests by analyzing the current buffer.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// severityWarning is the LSP diagnostic severity used for every finding.
+const severityWarning = 2
+
+// Server is a gonamefix language server. The zero value is not usable; create
+// one with NewServer.
+type Server struct {
+	config gonamefix.Config
+
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+// NewServer returns a server that analyzes documents with config.
+func NewServer(config gonamefix.Config) *Server {
+	return &Server{config: config, docs: make(map[string][]byte)}
+}
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from in and writes responses to out until the client
+// sends exit or in is closed.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	r := bufio.NewReader(in)
+	for {
+		body, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			if err := writeMessage(out, message{JSONRPC: "2.0", Error: &responseError{Code: codeParseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		result, rpcErr := s.handle(msg.Method, msg.Params)
+		if msg.ID == nil {
+			// Notifications get no response
+			continue
+		}
+		response := message{JSONRPC: "2.0", ID: msg.ID, Result: result, Error: rpcErr}
+		if rpcErr == nil && result == nil {
+			response.Result = json.RawMessage("null")
+		}
+		if err := writeMessage(out, response); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(method string, params json.RawMessage) (interface{}, *responseError) {
+	switch method {
+	case "initialize":
+		return initializeResult{Capabilities: serverCapabilities{
+			TextDocumentSync:   textDocumentSyncFull,
+			CodeActionProvider: true,
+		}}, nil
+	case "initialized", "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		s.setDocument(p.TextDocument.URI, p.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		// With full sync the last change holds the whole document
+		if n := len(p.ContentChanges); n > 0 {
+			s.setDocument(p.TextDocument.URI, p.ContentChanges[n-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var p didCloseParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		s.mu.Lock()
+		delete(s.docs, p.TextDocument.URI)
+		s.mu.Unlock()
+		return nil, nil
+	case "textDocument/codeAction":
+		var p codeActionParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.codeActions(p), nil
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func (s *Server) setDocument(uri, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs[uri] = []byte(text)
+}
+
+// codeActions returns a quick fix for every suggested fix of a diagnostic
+// within one line of the requested range. Buffers that do not parse get none.
+func (s *Server) codeActions(p codeActionParams) []codeAction {
+	s.mu.Lock()
+	src, ok := s.docs[p.TextDocument.URI]
+	s.mu.Unlock()
+	if !ok {
+		return []codeAction{}
+	}
+
+	fset, diagnostics, err := gonamefix.AnalyzeSource(uriFilename(p.TextDocument.URI), src, s.config)
+	if err != nil {
+		return []codeAction{}
+	}
+
+	actions := []codeAction{}
+	for _, d := range diagnostics {
+		line := fset.Position(d.Pos).Line - 1
+		if line < p.Range.Start.Line-1 || line > p.Range.End.Line+1 {
+			continue
+		}
+		for _, fix := range d.SuggestedFixes {
+			edits := make([]textEdit, 0, len(fix.TextEdits))
+			for _, edit := range fix.TextEdits {
+				edits = append(edits, textEdit{
+					Range:   toRange(fset, src, edit.Pos, edit.End),
+					NewText: string(edit.NewText),
+				})
+			}
+			actions = append(actions, codeAction{
+				Title:       fix.Message,
+				Kind:        "quickfix",
+				Diagnostics: []diagnostic{toDiagnostic(p.TextDocument.URI, fset, src, d)},
+				Edit:        workspaceEdit{Changes: map[string][]textEdit{p.TextDocument.URI: edits}},
+			})
+		}
+	}
+	return actions
+}
+
+func toDiagnostic(uri string, fset *token.FileSet, src []byte, d analysis.Diagnostic) diagnostic {
+	result := diagnostic{
+		Range:    toRange(fset, src, d.Pos, d.End),
+		Severity: severityWarning,
+		Source:   "gonamefix",
+		Code:     d.Category,
+		Message:  d.Message,
+	}
+	// The document is analyzed on its own, so related positions are in it too
+	for _, related := range d.Related {
+		result.RelatedInformation = append(result.RelatedInformation, relatedInformation{
+			Location: location{URI: uri, Range: toRange(fset, src, related.Pos, related.End)},
+			Message:  related.Message,
+		})
+	}
+	return result
+}
+
+// toRange converts the span from pos to end to an LSP range. An invalid end
+// gives an empty range at pos.
+func toRange(fset *token.FileSet, src []byte, pos, end token.Pos) Range {
+	if !end.IsValid() {
+		end = pos
+	}
+	return Range{
+		Start: position(src, fset.Position(pos).Offset),
+		End:   position(src, fset.Position(end).Offset),
+	}
+}
+
+// position converts a byte offset in src to an LSP position, whose character
+// counts UTF-16 code units.
+func position(src []byte, offset int) Position {
+	line, start := 0, 0
+	for i := 0; i < offset && i < len(src); i++ {
+		if src[i] == '\n' {
+			line++
+			start = i + 1
+		}
+	}
+
+	character := 0
+	for _, r := range string(src[start:offset]) {
+		if r >= 0x10000 {
+			character += 2
+		} else {
+			character++
+		}
+	}
+	return Position{Line: line, Character: character}
+}
+
+// uriFilename returns the file path of a file:// URI, or the URI itself when
+// it is not one. The path decides which exclusion rules apply.
+func uriFilename(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// readMessage reads one message framed by a Content-Length header.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if line == "" && length < 0 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q: %w", value, err)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return body, nil
+}
+
+// writeMessage writes msg framed by a Content-Length header.
+func writeMessage(w io.Writer, msg message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
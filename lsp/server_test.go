@@ -0,0 +1,113 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/xbpk3t/gonamefix"
+)
+
+func frame(t *testing.T, msg map[string]interface{}) string {
+	t.Helper()
+	body, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestServerCodeAction(t *testing.T) {
+	const uri = "file:///work/handler.go"
+	src := "package p\n\n// 日本\nfunc handleRequest() {}\n\nvar count int\n"
+
+	var in bytes.Buffer
+	in.WriteString(frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{}}))
+	in.WriteString(frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "go", "version": 1, "text": "package p\n"},
+	}}))
+	in.WriteString(frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri},
+		"contentChanges": []map[string]interface{}{{"text": src}},
+	}}))
+	codeActionRequest := func(id, line int) string {
+		return frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": "textDocument/codeAction", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"range": map[string]interface{}{
+				"start": map[string]interface{}{"line": line, "character": 0},
+				"end":   map[string]interface{}{"line": line, "character": 0},
+			},
+		}})
+	}
+	in.WriteString(codeActionRequest(2, 4)) // the line after the violation
+	in.WriteString(codeActionRequest(3, 0)) // too far away
+	in.WriteString(frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 4, "method": "unknown"}))
+	in.WriteString(frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "exit"}))
+
+	server := NewServer(gonamefix.Config{Check: [][]string{{"request", "req"}}})
+	var out bytes.Buffer
+	if err := server.Serve(&in, &out); err != nil {
+		t.Fatalf("Serve() returned error: %v", err)
+	}
+
+	r := bufio.NewReader(&out)
+	responses := make(map[int]json.RawMessage)
+	errs := make(map[int]*responseError)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			break
+		}
+		var msg struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *responseError  `json:"error"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		responses[msg.ID] = msg.Result
+		errs[msg.ID] = msg.Error
+	}
+
+	if len(responses) != 4 {
+		t.Fatalf("got %d responses, want 4 (notifications must not be answered)", len(responses))
+	}
+
+	var actions []codeAction
+	if err := json.Unmarshal(responses[2], &actions); err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("got %d code actions near line 4, want 1: %s", len(actions), responses[2])
+	}
+	action := actions[0]
+	if action.Kind != "quickfix" {
+		t.Errorf("Kind = %q, want quickfix", action.Kind)
+	}
+	edits := action.Edit.Changes[uri]
+	expected := textEdit{
+		Range:   Range{Start: Position{Line: 3, Character: 5}, End: Position{Line: 3, Character: 18}},
+		NewText: "handleReq",
+	}
+	if len(edits) != 1 || edits[0] != expected {
+		t.Errorf("edits = %+v, want [%+v]", edits, expected)
+	}
+
+	if string(responses[3]) != "[]" {
+		t.Errorf("code actions far from the violation = %s, want []", responses[3])
+	}
+	if errs[4] == nil || errs[4].Code != codeMethodNotFound {
+		t.Errorf("unknown method error = %+v, want code %d", errs[4], codeMethodNotFound)
+	}
+}
+
+func TestPositionCountsUTF16(t *testing.T) {
+	src := []byte("a\n😀x")
+	// 😀 is one rune but two UTF-16 code units
+	if got := position(src, len(src)-1); got != (Position{Line: 1, Character: 2}) {
+		t.Errorf("position() = %+v, want line 1 character 2", got)
+	}
+}
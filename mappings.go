@@ -0,0 +1,192 @@
+package gonamefix
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Mapping is the structured form of a name mapping, which can carry a rule ID
+// and a documentation link in addition to the words of an entry in Check.
+type Mapping struct {
+	// Original is the word that should not be used in names
+	Original string `mapstructure:"original"`
+	// Replacement is the word suggested instead
+	Replacement string `mapstructure:"replacement"`
+	// ID overrides the rule ID, gonamefix/<original>-<replacement> by default
+	ID string `mapstructure:"id"`
+	// URL links to documentation of the mapping and takes precedence over Config.DocsBaseURL
+	URL string `mapstructure:"url"`
+	// Types limits the mapping to fields, methods and parameters of these types, given
+	// as package path and type name glob, e.g. "internal/api.Handler" or "internal/api.*".
+	// Needs type information; identifiers without an owning type are never renamed by it
+	Types []string `mapstructure:"types"`
+	// Kinds limits the mapping to declarations of these kinds: func, method, type, var,
+	// const, field, param and result. Empty applies the mapping to every kind
+	Kinds []string `mapstructure:"kinds"`
+}
+
+// MappingRuleID returns the stable rule ID of the mapping original ->
+// replacement, e.g. "gonamefix/request-req". Diagnostics reported for the
+// mapping carry it as their category unless the mapping sets its own ID.
+func MappingRuleID(original, replacement string) string {
+	return "gonamefix/" + original + "-" + replacement
+}
+
+// MappingPairs returns the mappings of Check followed by those of Mappings, as
+// [original, replacement] pairs.
+func (c Config) MappingPairs() [][]string {
+	pairs := make([][]string, 0, len(c.Check)+len(c.Mappings))
+	pairs = append(pairs, c.Check...)
+	for _, mapping := range c.Mappings {
+		pairs = append(pairs, []string{mapping.Original, mapping.Replacement})
+	}
+	return pairs
+}
+
+// FilterMappings returns c with only the mappings selected by only, or all
+// when only is empty, minus those selected by skip. A name selects the
+// mappings whose original it is, compared case-insensitively, or whose rule
+// ID it is. A name that selects no mapping is an error, as it is most likely
+// a typo. Filtering before the analyzer is created keeps the unselected
+// mappings from being compiled at all.
+func (c Config) FilterMappings(only, skip []string) (Config, error) {
+	used := make(map[string]bool)
+	selects := func(names []string, original, id string) bool {
+		selected := false
+		for _, name := range names {
+			if strings.EqualFold(name, original) || name == id {
+				used[name] = true
+				selected = true
+			}
+		}
+		return selected
+	}
+	keep := func(original, id string) bool {
+		inOnly := selects(only, original, id)
+		inSkip := selects(skip, original, id)
+		return (len(only) == 0 || inOnly) && !inSkip
+	}
+
+	filtered := c
+	filtered.Check = nil
+	for _, pair := range c.Check {
+		if len(pair) != 2 || keep(pair[0], MappingRuleID(pair[0], pair[1])) {
+			filtered.Check = append(filtered.Check, pair)
+		}
+	}
+	filtered.Mappings = nil
+	for _, mapping := range c.Mappings {
+		id := mapping.ID
+		if id == "" {
+			id = MappingRuleID(mapping.Original, mapping.Replacement)
+		}
+		if keep(mapping.Original, id) {
+			filtered.Mappings = append(filtered.Mappings, mapping)
+		}
+	}
+
+	var errs []error
+	for _, name := range append(append([]string(nil), only...), skip...) {
+		if !used[name] {
+			errs = append(errs, fmt.Errorf("%q matches no mapping", name))
+		}
+	}
+	return filtered, errors.Join(errs...)
+}
+
+// MappingRule describes a configured mapping as a rule.
+type MappingRule struct {
+	// ID is the rule ID, the category of the mapping's diagnostics
+	ID          string
+	Original    string
+	Replacement string
+	// URL is the documentation link of the mapping, if any
+	URL string
+}
+
+// MappingRules returns the rules of the valid mappings of c, sorted by ID.
+func (c Config) MappingRules() []MappingRule {
+	patterns, _ := configPatterns(c)
+	rules := make([]MappingRule, 0, len(patterns))
+	for _, pattern := range patterns {
+		rules = append(rules, MappingRule{ID: pattern.id, Original: pattern.original, Replacement: pattern.replacement, URL: pattern.url})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// configPatterns compiles the mappings of config with their rule IDs and
+// documentation URLs. Malformed mappings, such as check entries that are not
+// pairs or have an empty original, are left out and reported in the error.
+func configPatterns(config Config) ([]namePattern, error) {
+	var errs []error
+	for _, pair := range config.Check {
+		if len(pair) != 2 || pair[0] == "" {
+			errs = append(errs, fmt.Errorf("check entry %q: expected [original, replacement]", pair))
+		}
+	}
+	for _, mapping := range config.Mappings {
+		if mapping.Original == "" {
+			errs = append(errs, fmt.Errorf("mapping with replacement %q: original is empty", mapping.Replacement))
+		}
+		for _, kind := range mapping.Kinds {
+			if err := validateKind(kind); err != nil {
+				errs = append(errs, fmt.Errorf("mapping %s: %w", MappingCategory(mapping.Original, mapping.Replacement), err))
+			}
+		}
+		for _, pattern := range mapping.Types {
+			if err := validateTypePattern(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("mapping %s: types pattern %q: %w", MappingCategory(mapping.Original, mapping.Replacement), pattern, err))
+			}
+		}
+	}
+
+	patterns, err := buildPatterns(buildNameMappings(config.MappingPairs()), config.CaseSensitive)
+	errs = append(errs, err)
+
+	structured := make(map[string]Mapping, len(config.Mappings))
+	for _, mapping := range config.Mappings {
+		structured[mapping.Original] = mapping
+	}
+
+	for i := range patterns {
+		pattern := &patterns[i]
+		mapping := structured[pattern.original]
+		pattern.id = mapping.ID
+		if pattern.id == "" {
+			pattern.id = MappingRuleID(pattern.original, pattern.replacement)
+		}
+		pattern.url = mapping.URL
+		pattern.types = mapping.Types
+		if len(mapping.Kinds) > 0 {
+			pattern.kinds = make(map[string]bool, len(mapping.Kinds))
+			for _, kind := range mapping.Kinds {
+				pattern.kinds[kind] = true
+			}
+		}
+		if pattern.url == "" && config.DocsBaseURL != "" {
+			pattern.url = expandDocsURL(config.DocsBaseURL, pattern)
+		}
+	}
+	return patterns, errors.Join(errs...)
+}
+
+// expandDocsURL fills the {id}, {original} and {replacement} placeholders of
+// template for pattern.
+func expandDocsURL(template string, pattern *namePattern) string {
+	return strings.NewReplacer(
+		"{id}", pattern.id,
+		"{original}", pattern.original,
+		"{replacement}", pattern.replacement,
+	).Replace(template)
+}
+
+// withURL appends the documentation link of a mapping to a diagnostic message.
+func withURL(message, url string) string {
+	if url == "" {
+		return message
+	}
+	return message + " (see " + url + ")"
+}
@@ -5,7 +5,10 @@ import (
 	"go/ast"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
@@ -123,9 +126,18 @@ func buildNameMappings(check [][]string) map[string]string {
 	return mappings
 }
 
+// buildPatterns compiles the pattern of every mapping, in the order of their
+// originals so that the patterns are the same on every run.
 func buildPatterns(mappings map[string]string, caseSensitive bool) []namePattern {
+	originals := make([]string, 0, len(mappings))
+	for original := range mappings {
+		originals = append(originals, original)
+	}
+	sort.Strings(originals)
+
 	var patterns []namePattern
-	for original, replacement := range mappings {
+	for _, original := range originals {
+		replacement := mappings[original]
 		var regex *regexp.Regexp
 		var err error
 
@@ -204,47 +216,58 @@ func checkIdentifier(pass *analysis.Pass, ident *ast.Ident, patterns []namePatte
 }
 
 func replacePreservingCase(input, original, replacement string) string {
-	// Handle camelCase preservation
-	if strings.Contains(strings.ToLower(input), strings.ToLower(original)) {
-		lowerInput := strings.ToLower(input)
-		lowerOriginal := strings.ToLower(original)
-
-		index := strings.Index(lowerInput, lowerOriginal)
-		if index == -1 {
-			return input
-		}
+	// Work on runes: lower-casing can change the byte length of a name, so byte
+	// offsets found in the lower-cased name do not apply to input
+	inputRunes, originalRunes := []rune(input), []rune(original)
+
+	index := indexFold(inputRunes, originalRunes)
+	if index == -1 {
+		return input
+	}
 
-		// Preserve the case of the replacement
-		var result strings.Builder
-		result.WriteString(input[:index])
+	// Preserve the case of the replacement
+	var result strings.Builder
+	result.WriteString(string(inputRunes[:index]))
 
-		// Handle capitalization based on the position and context
-		if index == 0 {
-			// At the beginning - check if original was capitalized
-			if len(input) > 0 && isUpperCase(rune(input[0])) {
-				result.WriteString(capitalize(replacement))
-			} else {
-				result.WriteString(replacement)
-			}
-		} else {
-			// In the middle - typically camelCase, so capitalize first letter
+	// Handle capitalization based on the position and context
+	if index == 0 {
+		// At the beginning - check if original was capitalized
+		if isUpperCase(inputRunes[0]) {
 			result.WriteString(capitalize(replacement))
+		} else {
+			result.WriteString(replacement)
 		}
+	} else {
+		// In the middle - typically camelCase, so capitalize first letter
+		result.WriteString(capitalize(replacement))
+	}
 
-		result.WriteString(input[index+len(original):])
+	result.WriteString(string(inputRunes[index+len(originalRunes):]))
 
-		return result.String()
-	}
+	return result.String()
+}
 
-	return input
+// indexFold returns the rune index of the first case-insensitive occurrence of
+// sub in s, or -1.
+func indexFold(s, sub []rune) int {
+	if len(sub) == 0 {
+		return -1
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if strings.EqualFold(string(s[i:i+len(sub)]), string(sub)) {
+			return i
+		}
+	}
+	return -1
 }
 
 func capitalize(s string) string {
-	if len(s) == 0 {
+	first, size := utf8.DecodeRuneInString(s)
+	if first == utf8.RuneError {
 		return s
 	}
 
-	return strings.ToUpper(s[:1]) + s[1:]
+	return string(unicode.ToUpper(first)) + s[size:]
 }
 
 func isUpperCase(r rune) bool {
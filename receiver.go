@@ -0,0 +1,169 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// receiverCategory is the diagnostic category used by the receiver consistency rule.
+const receiverCategory = "receiver-name"
+
+type receiverMethod struct {
+	decl     *ast.FuncDecl
+	receiver *ast.Ident
+}
+
+// checkReceiverNames reports methods whose receiver name differs from the name
+// used by the majority of the methods of the same type (ties go to the name of
+// the first declared method). Pointer and value receivers count as the same type.
+func checkReceiverNames(pass *analysis.Pass, files []*ast.File) {
+	methods := make(map[string][]receiverMethod)
+	var typeNames []string
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+			field := fn.Recv.List[0]
+			if len(field.Names) != 1 || field.Names[0].Name == "_" {
+				continue
+			}
+			typeName := receiverTypeName(field.Type)
+			if typeName == "" {
+				continue
+			}
+			if _, seen := methods[typeName]; !seen {
+				typeNames = append(typeNames, typeName)
+			}
+			methods[typeName] = append(methods[typeName], receiverMethod{decl: fn, receiver: field.Names[0]})
+		}
+	}
+
+	for _, typeName := range typeNames {
+		expected := majorityReceiverName(methods[typeName])
+		for _, method := range methods[typeName] {
+			if method.receiver.Name == expected {
+				continue
+			}
+			reportReceiverName(pass, typeName, method, expected)
+		}
+	}
+}
+
+func majorityReceiverName(methods []receiverMethod) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, method := range methods {
+		name := method.receiver.Name
+		counts[name]++
+		if best == "" || counts[name] > counts[best] {
+			best = name
+		}
+	}
+	return best
+}
+
+func reportReceiverName(pass *analysis.Pass, typeName string, method receiverMethod, expected string) {
+	receiver := method.receiver
+	diagnostic := analysis.Diagnostic{
+		Pos:      receiver.Pos(),
+		End:      receiver.End(),
+		Category: receiverCategory,
+		Message: fmt.Sprintf("receiver name '%s' of %s.%s should be '%s' for consistency with other methods of %s",
+			receiver.Name, typeName, method.decl.Name.Name, expected, typeName),
+	}
+
+	// Renaming would capture another variable if the expected name is already in use
+	uses, conflict := receiverUses(pass, method.decl, receiver, expected)
+	if !conflict {
+		edits := make([]analysis.TextEdit, 0, len(uses)+1)
+		for _, ident := range append([]*ast.Ident{receiver}, uses...) {
+			edits = append(edits, analysis.TextEdit{Pos: ident.Pos(), End: ident.End(), NewText: []byte(expected)})
+		}
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Rename receiver '%s' to '%s'", receiver.Name, expected),
+			TextEdits: edits,
+		}}
+	}
+
+	pass.Report(diagnostic)
+}
+
+// receiverUses returns the identifiers in the method body that refer to the
+// receiver, and whether the method already uses newName for something else:
+// a parameter, result or type parameter of its signature, or a name in its
+// body.
+func receiverUses(pass *analysis.Pass, decl *ast.FuncDecl, receiver *ast.Ident, newName string) ([]*ast.Ident, bool) {
+	var uses []*ast.Ident
+	conflict := false
+
+	// The receiver type holds the type parameters of the method
+	signature := []ast.Node{decl.Recv.List[0].Type, decl.Type}
+	for _, node := range signature {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && ident.Name == newName {
+				conflict = true
+			}
+			return !conflict
+		})
+	}
+	if conflict || decl.Body == nil {
+		return nil, conflict
+	}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch {
+		case sameObject(pass, ident, receiver):
+			uses = append(uses, ident)
+		case ident.Name == newName:
+			conflict = true
+		}
+		return true
+	})
+	return uses, conflict
+}
+
+// sameObject reports whether use refers to the object declared by decl. It
+// prefers type information and falls back to the parser's object resolution
+// when the driver does not provide types.
+func sameObject(pass *analysis.Pass, use, decl *ast.Ident) bool {
+	if use.Name != decl.Name {
+		return false
+	}
+	if pass.TypesInfo != nil {
+		if obj := pass.TypesInfo.ObjectOf(decl); obj != nil {
+			return pass.TypesInfo.Uses[use] == obj
+		}
+	}
+	//nolint:staticcheck // ast.Object is the only resolution available without type information
+	return use.Obj != nil && use.Obj == decl.Obj
+}
+
+// receiverTypeName returns the name of the receiver's base type, stripping
+// pointers and type parameters.
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
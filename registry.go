@@ -0,0 +1,80 @@
+package gonamefix
+
+import (
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// MappingRegistry accumulates name mappings registered by several packages.
+// It is safe for concurrent use.
+//
+// Packages that contribute mappings typically register them from an init
+// function, and the default Analyzer picks them up at run time:
+//
+//	func init() {
+//		gonamefix.DefaultRegistry.Register("http", [][]string{
+//			{"request", "req"},
+//			{"response", "res"},
+//		})
+//	}
+type MappingRegistry struct {
+	mu      sync.Mutex
+	names   []string
+	sources map[string][][]string
+}
+
+// DefaultRegistry is the registry consulted by the default Analyzer.
+var DefaultRegistry = &MappingRegistry{}
+
+// Register stores mappings under name. Registering the same name again
+// replaces the mappings previously registered under it.
+func (r *MappingRegistry) Register(name string, mappings [][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sources == nil {
+		r.sources = make(map[string][][]string)
+	}
+	if _, exists := r.sources[name]; !exists {
+		r.names = append(r.names, name)
+	}
+
+	copied := make([][]string, 0, len(mappings))
+	for _, pair := range mappings {
+		copied = append(copied, append([]string(nil), pair...))
+	}
+	r.sources[name] = copied
+}
+
+// Build returns the default configuration with the registered mappings as its
+// Check list. Mappings are merged in registration order and deduplicated by
+// original: the first registration of an original wins.
+func (r *MappingRegistry) Build() Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config := DefaultConfig()
+	seen := make(map[string]bool)
+	for _, name := range r.names {
+		for _, pair := range r.sources[name] {
+			if len(pair) != 2 || seen[pair[0]] {
+				continue
+			}
+			seen[pair[0]] = true
+			config.Check = append(config.Check, pair)
+		}
+	}
+	return config
+}
+
+// newRegistryAnalyzer returns an analyzer that builds its configuration from
+// registry on every run, so mappings registered after package initialization
+// are taken into account.
+func newRegistryAnalyzer(registry *MappingRegistry) *analysis.Analyzer {
+	analyzer := NewAnalyzer(DefaultConfig())
+	analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		return NewAnalyzer(registry.Build()).Run(pass)
+	}
+	return analyzer
+}
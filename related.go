@@ -0,0 +1,101 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// defaultMaxRelated is the number of other occurrences attached to a
+// diagnostic when Config.MaxRelated is 0.
+const defaultMaxRelated = 10
+
+// occurrenceIndex finds the other occurrences of a flagged identifier, so
+// reviewers can see how widely a name is used before renaming it. Objects are
+// resolved with type information when available and with ast.Object within
+// a file otherwise. The index is built on first use.
+type occurrenceIndex struct {
+	pass *analysis.Pass
+	defs map[token.Pos]any
+	uses map[any][]*ast.Ident
+}
+
+func (x *occurrenceIndex) build() {
+	if x.defs != nil {
+		return
+	}
+	x.defs = make(map[token.Pos]any)
+	x.uses = make(map[any][]*ast.Ident)
+
+	if info := x.pass.TypesInfo; info != nil {
+		for ident, obj := range info.Defs {
+			if obj != nil {
+				x.defs[ident.Pos()] = obj
+			}
+		}
+		for ident, obj := range info.Uses {
+			x.uses[obj] = append(x.uses[obj], ident)
+		}
+		return
+	}
+
+	for _, file := range x.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			//nolint:staticcheck // ast.Object is the only resolution available without type information
+			if ident, ok := n.(*ast.Ident); ok && ident.Obj != nil {
+				x.defs[ident.Pos()] = ident.Obj
+				x.uses[ident.Obj] = append(x.uses[ident.Obj], ident)
+			}
+			return true
+		})
+	}
+}
+
+// related returns up to limit other occurrences of the identifier declared at
+// pos, in source order.
+func (x *occurrenceIndex) related(pos token.Pos, limit int) []analysis.RelatedInformation {
+	x.build()
+	obj, ok := x.defs[pos]
+	if !ok {
+		return nil
+	}
+
+	var others []*ast.Ident
+	for _, ident := range x.uses[obj] {
+		if ident.Pos() != pos {
+			others = append(others, ident)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].Pos() < others[j].Pos() })
+	if len(others) > limit {
+		others = others[:limit]
+	}
+
+	related := make([]analysis.RelatedInformation, 0, len(others))
+	for _, ident := range others {
+		related = append(related, analysis.RelatedInformation{Pos: ident.Pos(), End: ident.End(), Message: "used here"})
+	}
+	return related
+}
+
+// withRelatedOccurrences returns a copy of pass whose Report attaches the other
+// occurrences of the reported identifier as related information.
+func withRelatedOccurrences(pass *analysis.Pass, limit int) *analysis.Pass {
+	if limit == 0 {
+		limit = defaultMaxRelated
+	}
+	if limit < 0 {
+		return pass
+	}
+
+	index := &occurrenceIndex{pass: pass}
+	report := pass.Report
+	related := *pass
+	related.Report = func(d analysis.Diagnostic) {
+		d.Related = append(d.Related, index.related(d.Pos, limit)...)
+		report(d)
+	}
+	return &related
+}
@@ -0,0 +1,156 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// packageScope is the scope key of top-level declarations when there is no
+// type information.
+const packageScope = "package"
+
+// plannedRename is a rename suggested for a single identifier.
+type plannedRename struct {
+	ident    *ast.Ident
+	newName  string
+	category string
+	url      string
+}
+
+// renamePlan collects the renames suggested for individual identifiers across
+// the package, so that collisions can be found before any fix is emitted: a
+// new name that is already declared in the same scope, or two declarations
+// renamed to the same name. Colliding renames are reported without a fix; the
+// other renames keep theirs.
+type renamePlan struct {
+	renames []plannedRename
+}
+
+func (p *renamePlan) add(ident *ast.Ident, newName, category, url string) {
+	p.renames = append(p.renames, plannedRename{ident: ident, newName: newName, category: category, url: url})
+}
+
+// report emits a diagnostic for every planned rename. Scopes are known for
+// top-level declarations and, with type information, for every variable,
+// constant, type and function; other renames cannot be checked and keep
+// their fix.
+func (p *renamePlan) report(pass *analysis.Pass) {
+	topLevel := make(map[string]*ast.Ident)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			for _, ident := range topLevelIdents(decl) {
+				topLevel[ident.Name] = ident
+			}
+		}
+	}
+
+	scopes := make([]any, len(p.renames))
+	renamedAway := make(map[any]map[string]bool)
+	newNames := make(map[any]map[string][]*ast.Ident)
+	for i, r := range p.renames {
+		scope := renameScope(pass, r.ident, topLevel)
+		if scope == nil {
+			continue
+		}
+		scopes[i] = scope
+		if renamedAway[scope] == nil {
+			renamedAway[scope] = make(map[string]bool)
+			newNames[scope] = make(map[string][]*ast.Ident)
+		}
+		renamedAway[scope][r.ident.Name] = true
+		if !containsIdent(newNames[scope][r.newName], r.ident) {
+			newNames[scope][r.newName] = append(newNames[scope][r.newName], r.ident)
+		}
+	}
+
+	for i, r := range p.renames {
+		scope := scopes[i]
+		if scope == nil {
+			reportRename(pass, r.ident, r.newName, r.category, r.url)
+			continue
+		}
+
+		// A declaration that is renamed itself no longer holds its name
+		if existing := declaredIn(scope, r.newName, topLevel); existing.IsValid() && !renamedAway[scope][r.newName] {
+			reportCollision(pass, r, fmt.Sprintf("'%s' is already declared", r.newName),
+				analysis.RelatedInformation{Pos: existing, Message: fmt.Sprintf("'%s' declared here", r.newName)})
+			continue
+		}
+
+		var others []*ast.Ident
+		var related []analysis.RelatedInformation
+		for _, other := range newNames[scope][r.newName] {
+			if other != r.ident {
+				others = append(others, other)
+				related = append(related, analysis.RelatedInformation{
+					Pos:     other.Pos(),
+					End:     other.End(),
+					Message: fmt.Sprintf("'%s' is also renamed to '%s' here", other.Name, r.newName),
+				})
+			}
+		}
+		if len(others) > 0 {
+			reportCollision(pass, r, fmt.Sprintf("'%s' would also be renamed to '%s'", others[0].Name, r.newName), related...)
+			continue
+		}
+
+		reportRename(pass, r.ident, r.newName, r.category, r.url)
+	}
+}
+
+// renameScope returns the scope in which ident is declared, or nil when it is
+// unknown: a *types.Scope with type information, packageScope for top-level
+// declarations without it.
+func renameScope(pass *analysis.Pass, ident *ast.Ident, topLevel map[string]*ast.Ident) any {
+	if pass.TypesInfo != nil {
+		if obj := pass.TypesInfo.Defs[ident]; obj != nil && obj.Parent() != nil {
+			return obj.Parent()
+		}
+		return nil
+	}
+	if topLevel[ident.Name] == ident {
+		return packageScope
+	}
+	return nil
+}
+
+// declaredIn returns the position of the declaration of name in scope, or
+// token.NoPos.
+func declaredIn(scope any, name string, topLevel map[string]*ast.Ident) token.Pos {
+	if scope == packageScope {
+		if ident, ok := topLevel[name]; ok {
+			return ident.Pos()
+		}
+		return token.NoPos
+	}
+	if obj := scope.(*types.Scope).Lookup(name); obj != nil {
+		return obj.Pos()
+	}
+	return token.NoPos
+}
+
+// reportCollision reports a planned rename without a fix, explaining the
+// collision and pointing at the other location involved.
+func reportCollision(pass *analysis.Pass, r plannedRename, reason string, related ...analysis.RelatedInformation) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      r.ident.Pos(),
+		End:      r.ident.End(),
+		Category: r.category,
+		URL:      r.url,
+		Message:  withURL(fmt.Sprintf("suggest replacing '%s' with '%s' (not fixed: %s)", r.ident.Name, r.newName, reason), r.url),
+		Related:  related,
+	})
+}
+
+func containsIdent(idents []*ast.Ident, ident *ast.Ident) bool {
+	for _, other := range idents {
+		if other == ident {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// snakeCaseCategory is the diagnostic category used by the snake_case rule.
+const snakeCaseCategory = "snake-case"
+
+// testNamePrefixes are function name prefixes for which the testing package
+// conventions allow underscores (Test_parse_fails, ExampleType_method).
+var testNamePrefixes = []string{"Test", "Benchmark", "Fuzz", "Example"}
+
+// isSnakeCase reports whether name has an underscore between two lower case
+// words, such as user_name or max_retry_count.
+func isSnakeCase(name string) bool {
+	runes := []rune(name)
+	for i := 1; i+1 < len(runes); i++ {
+		if runes[i] != '_' {
+			continue
+		}
+		prev, next := runes[i-1], runes[i+1]
+		if (unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsLower(next) {
+			return true
+		}
+	}
+	return false
+}
+
+// snakeToCamel joins the words of a snake_case name into camelCase, keeping
+// the case of the first letter so exported names stay exported.
+func snakeToCamel(name string) string {
+	words := splitWords(name)
+	for i := 1; i < len(words); i++ {
+		first, size := utf8.DecodeRuneInString(words[i])
+		words[i] = string(unicode.ToUpper(first)) + words[i][size:]
+	}
+	return strings.Join(words, "")
+}
+
+func isTestFuncName(name string) bool {
+	for _, prefix := range testNamePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkSnakeCase(ident *ast.Ident, plan *renamePlan) {
+	name := ident.Name
+	if strings.Trim(name, "_") == "" || isTestFuncName(name) || !isSnakeCase(name) {
+		return
+	}
+
+	suggestedName := snakeToCamel(name)
+	if suggestedName == "" || suggestedName == name || isGoKeyword(suggestedName) {
+		return
+	}
+	plan.add(ident, suggestedName, snakeCaseCategory, "")
+}
+
+// hasExportDirective reports whether decl is exported to C with a //export
+// comment. Its name is fixed by the C side and must never be renamed.
+func hasExportDirective(decl *ast.FuncDecl) bool {
+	if decl.Doc == nil {
+		return false
+	}
+	for _, comment := range decl.Doc.List {
+		if strings.HasPrefix(comment.Text, "//export ") {
+			return true
+		}
+	}
+	return false
+}
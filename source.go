@@ -0,0 +1,79 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// AnalyzeSource runs the analyzer for config on a single file held in memory,
+// such as an unsaved editor buffer, and returns its diagnostics together with
+// the file set their positions refer to. The file is analyzed on its own,
+// without type information, like the gonamefix command does.
+func AnalyzeSource(filename string, src []byte, config Config) (*token.FileSet, []analysis.Diagnostic, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return fset, nil, err
+	}
+
+	diagnostics, err := AnalyzeFile(fset, file, config)
+	if err != nil {
+		return fset, nil, err
+	}
+	return fset, diagnostics, nil
+}
+
+// AnalyzeFile is like AnalyzeSource for a file that was already parsed into
+// fset, for callers that need the syntax tree as well. The diagnostics are
+// sorted as by SortDiagnostics.
+func AnalyzeFile(fset *token.FileSet, file *ast.File, config Config) ([]analysis.Diagnostic, error) {
+	analyzer := NewAnalyzer(config)
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: analyzer,
+		Fset:     fset,
+		Files:    []*ast.File{file},
+		Report: func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+		ResultOf: make(map[*analysis.Analyzer]interface{}),
+	}
+
+	for _, req := range analyzer.Requires {
+		res, err := req.Run(pass)
+		if err != nil {
+			return nil, fmt.Errorf("required analyzer %s failed: %w", req.Name, err)
+		}
+		pass.ResultOf[req] = res
+	}
+
+	if _, err := analyzer.Run(pass); err != nil {
+		return nil, err
+	}
+	SortDiagnostics(fset, diagnostics)
+	return diagnostics, nil
+}
+
+// SortDiagnostics sorts diagnostics by file, line, column and message, so
+// that the order of the findings does not depend on the order the analyzer
+// reports them in. Diagnostics equal in all four keep their order.
+func SortDiagnostics(fset *token.FileSet, diagnostics []analysis.Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := fset.Position(diagnostics[i].Pos), fset.Position(diagnostics[j].Pos)
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return diagnostics[i].Message < diagnostics[j].Message
+	})
+}
@@ -0,0 +1,134 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// stutterCategory is the diagnostic category used by the stutter rule.
+const stutterCategory = "stutter"
+
+// packageBaseName strips a version suffix from a package name, so configv2
+// stutters like config.
+func packageBaseName(name string) string {
+	name = strings.ToLower(name)
+	i := strings.LastIndex(name, "v")
+	if i > 0 && i < len(name)-1 && isDigits(name[i+1:]) {
+		return name[:i]
+	}
+	return name
+}
+
+// trimStutter returns name without the leading words that spell the package
+// name, e.g. ConfigLoader -> Loader in package config. The case of the first
+// letter is kept so exported names stay exported.
+func trimStutter(name, pkgName string) (string, bool) {
+	base := packageBaseName(pkgName)
+	words := splitWords(name)
+
+	prefix := ""
+	for i, word := range words[:max(len(words)-1, 0)] {
+		prefix += strings.ToLower(word)
+		if prefix != base {
+			if !strings.HasPrefix(base, prefix) {
+				return "", false
+			}
+			continue
+		}
+
+		rest := strings.Join(words[i+1:], "")
+		first, size := utf8.DecodeRuneInString(rest)
+		if !unicode.IsLetter(first) {
+			return "", false
+		}
+		if ast.IsExported(name) {
+			return string(unicode.ToUpper(first)) + rest[size:], true
+		}
+		return string(unicode.ToLower(first)) + rest[size:], true
+	}
+	return "", false
+}
+
+// checkStutter reports top-level declarations whose names repeat the package
+// name, such as config.ConfigLoader. Unexported names are only checked when
+// unexported is set.
+func checkStutter(pass *analysis.Pass, files []*ast.File, unexported bool) {
+	pkgName := files[0].Name.Name
+	declared := make(map[string]bool)
+	var idents []*ast.Ident
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			for _, ident := range topLevelIdents(decl) {
+				declared[ident.Name] = true
+			}
+		}
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && hasExportDirective(fn) {
+				continue
+			}
+			idents = append(idents, topLevelIdents(decl)...)
+		}
+	}
+
+	for _, ident := range idents {
+		if !unexported && !ident.IsExported() {
+			continue
+		}
+		suggestedName, ok := trimStutter(ident.Name, pkgName)
+		if !ok || isGoKeyword(suggestedName) {
+			continue
+		}
+
+		diagnostic := analysis.Diagnostic{
+			Pos:      ident.Pos(),
+			End:      ident.End(),
+			Category: stutterCategory,
+			Message:  fmt.Sprintf("'%s' stutters as %s.%s; suggest '%s'", ident.Name, pkgName, ident.Name, suggestedName),
+		}
+		if declared[suggestedName] {
+			// Renaming would collide with an existing declaration
+			pass.Report(diagnostic)
+			continue
+		}
+		if edits := objectRenameEdits(pass, ident, suggestedName); edits != nil {
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Rename '%s' to '%s'", ident.Name, suggestedName),
+				TextEdits: edits,
+			}}
+		}
+		pass.Report(diagnostic)
+	}
+}
+
+// topLevelIdents returns the package level names declared by decl. Methods
+// are not package level names and are skipped.
+func topLevelIdents(decl ast.Decl) []*ast.Ident {
+	var idents []*ast.Ident
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv == nil {
+			idents = append(idents, decl.Name)
+		}
+	case *ast.GenDecl:
+		if decl.Tok == token.IMPORT {
+			break
+		}
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				idents = append(idents, spec.Name)
+			case *ast.ValueSpec:
+				idents = append(idents, spec.Names...)
+			}
+		}
+	}
+	return idents
+}
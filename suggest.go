@@ -0,0 +1,59 @@
+package gonamefix
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Replacement is one possible rename of an identifier.
+type Replacement struct {
+	// SuggestedName is the identifier after applying the pattern
+	SuggestedName string
+	// MatchedPattern is the mapping that produced the suggestion, as "original→replacement"
+	MatchedPattern string
+	// Confidence ranges from 0 to 1. It is the share of the identifier covered by
+	// the matched word, so a pattern matching the whole name scores 1.
+	Confidence float64
+}
+
+// Suggest returns every replacement the configured mappings offer for name,
+// one per matching pattern, sorted by confidence with the highest first. Unlike
+// the analyzer, which reports only the first match, it lets callers such as IDE
+// quick fixes present all options. Like the analyzer it offers none for
+// keywords, allowed names, the standard library names of
+// GoStdlibAllowedNames and names excluded by Config.ExcludeNames. Invalid
+// mappings, which Config.Validate reports, are left out.
+func Suggest(name string, config Config) []Replacement {
+	if name == "" || isGoKeyword(name) || allowedNames(config)[name] {
+		return nil
+	}
+	compiled, _ := compileConfig(config)
+	if compiled.names.match(name) {
+		return nil
+	}
+
+	var replacements []Replacement
+	for _, pattern := range compiled.patterns {
+		suggestedName := replaceInName(name, pattern.original, pattern.replacement, config.CaseSensitive)
+		if suggestedName == name {
+			continue
+		}
+		suggestedName = applyTransforms(config.Transforms, pattern, name, suggestedName)
+		if suggestedName == name || suggestedName == "" {
+			continue
+		}
+		replacements = append(replacements, Replacement{
+			SuggestedName:  suggestedName,
+			MatchedPattern: MappingCategory(pattern.original, pattern.replacement),
+			Confidence:     float64(utf8.RuneCountInString(pattern.original)) / float64(utf8.RuneCountInString(name)),
+		})
+	}
+
+	sort.Slice(replacements, func(i, j int) bool {
+		if replacements[i].Confidence != replacements[j].Confidence {
+			return replacements[i].Confidence > replacements[j].Confidence
+		}
+		return replacements[i].MatchedPattern < replacements[j].MatchedPattern
+	})
+	return replacements
+}
@@ -0,0 +1,80 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// synonymCategory is the diagnostic category used by the synonym consistency rule.
+const synonymCategory = "synonym"
+
+// synonymGroups groups the originals of check by their replacement, the
+// canonical form: cfg:config and conf:config give config -> [cfg conf].
+// Originals are lower-cased and sorted.
+func synonymGroups(check [][]string) map[string][]string {
+	groups := make(map[string][]string)
+	for original, replacement := range buildNameMappings(check) {
+		canonical := strings.ToLower(replacement)
+		if strings.ToLower(original) == canonical {
+			continue
+		}
+		groups[canonical] = append(groups[canonical], strings.ToLower(original))
+	}
+	for _, synonyms := range groups {
+		sort.Strings(synonyms)
+	}
+	return groups
+}
+
+// checkSynonyms reports identifiers using a synonym of a canonical form when
+// the package also uses the canonical form itself, so stragglers left after a
+// partial cleanup stand out. Identifiers are counted once per form they
+// contain as a whole word.
+func checkSynonyms(pass *analysis.Pass, files []*ast.File, check [][]string) {
+	groups := synonymGroups(check)
+	if len(groups) == 0 {
+		return
+	}
+
+	uses := make(map[string][]*ast.Ident)
+	for _, file := range files {
+		for _, ident := range declaredIdents(file) {
+			seen := make(map[string]bool)
+			for _, word := range splitWords(ident.Name) {
+				word = strings.ToLower(word)
+				if !seen[word] {
+					seen[word] = true
+					uses[word] = append(uses[word], ident)
+				}
+			}
+		}
+	}
+
+	canonicals := make([]string, 0, len(groups))
+	for canonical := range groups {
+		canonicals = append(canonicals, canonical)
+	}
+	sort.Strings(canonicals)
+
+	for _, canonical := range canonicals {
+		canonicalCount := len(uses[canonical])
+		if canonicalCount == 0 {
+			continue
+		}
+		for _, synonym := range groups[canonical] {
+			for _, ident := range uses[synonym] {
+				pass.Report(analysis.Diagnostic{
+					Pos:      ident.Pos(),
+					End:      ident.End(),
+					Category: synonymCategory,
+					Message: fmt.Sprintf("'%s': package uses both '%s' (%d identifiers) and canonical '%s' (%d)",
+						ident.Name, synonym, len(uses[synonym]), canonical, canonicalCount),
+				})
+			}
+		}
+	}
+}
@@ -48,6 +48,19 @@ var forNested int      // OK - compound with keyword
 var ifCondition bool   // OK - compound with keyword
 var packageInfo string // want "suggest replacing 'packageInfo' with 'pkgInfo'"
 
+// Test map types: key and value types are references, reported once at their declaration
+type requestKey string // want "suggest replacing 'requestKey' with 'reqKey'"
+
+type responseValue struct{} // want "suggest replacing 'responseValue' with 'resValue'"
+
+var requestCache map[requestKey]responseValue // want "suggest replacing 'requestCache' with 'reqCache'"
+
+var cache map[requestKey]responseValue // OK - only type references
+
+type Registry struct {
+	handlers map[requestKey][]responseValue // OK - only type references
+}
+
 func testBasic() {
 	_ = 1 // avoid unused warnings
 }
@@ -0,0 +1,32 @@
+package allcaps
+
+import _ "unsafe"
+
+const MAX_RETRY_COUNT = 3 // want "suggest replacing 'MAX_RETRY_COUNT' with 'MaxRetryCount'"
+
+const (
+	HTTP_TIMEOUT = 30   // want "suggest replacing 'HTTP_TIMEOUT' with 'HTTPTimeout'"
+	A_B          = 1    // OK - too short to matter
+	DefaultPort  = 8080 // OK - already CamelCase
+	_PRIVATE     = 0    // OK - leading underscore
+)
+
+// Renaming would collide with BufferSize, so no fix is offered
+const BUFFER_SIZE = 64 // want "suggest replacing 'BUFFER_SIZE' with 'BufferSize'"
+
+var BufferSize = BUFFER_SIZE
+
+var LOG_LEVEL = "info" // want "suggest replacing 'LOG_LEVEL' with 'LogLevel'"
+
+// Bound to a symbol elsewhere, so the name is fixed
+//
+//go:linkname GC_PERCENT runtime.gcpercent
+var GC_PERCENT int32
+
+func retries() int {
+	const LOCAL_LIMIT = 2 // want "suggest replacing 'LOCAL_LIMIT' with 'LocalLimit'"
+	if MAX_RETRY_COUNT > LOCAL_LIMIT {
+		return HTTP_TIMEOUT
+	}
+	return len(LOG_LEVEL)
+}
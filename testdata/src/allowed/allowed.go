@@ -0,0 +1,12 @@
+package allowed
+
+type Handler struct{}
+
+// ServeHTTP is on the built-in standard library list
+func (h *Handler) ServeHTTP(w, r interface{}) {}
+
+type Request struct{} // OK - configured as an allowed long name
+
+var ErrRequestTimeout error // OK - configured as an allowed long name
+
+var requestTimeout int // want "suggest replacing 'requestTimeout' with 'reqTimeout'"
@@ -0,0 +1,45 @@
+package booleans
+
+import "sort"
+
+var enabled = true // want `bool 'enabled' should read as a predicate, e.g. 'isEnabled'`
+
+var IsReady bool
+
+type Node struct {
+	children bool // want `bool 'children' should read as a predicate, e.g. 'hasChildren'`
+	hasItems bool
+	name     string
+}
+
+func (n *Node) Valid() bool { // want `bool 'Valid' should read as a predicate, e.g. 'IsValid'`
+	return n.name != "" && enabled
+}
+
+func (n *Node) Exists() bool { // want `bool 'Exists' should read as a predicate, with a prefix such as is/has/are/can/should/will/was/does`
+	return n.Valid()
+}
+
+func (n *Node) Describe() string { return n.name }
+
+type byName []*Node
+
+func (b byName) Len() int           { return len(b) }
+func (b byName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byName) Less(i, j int) bool { return b[i].name < b[j].name }
+
+var _ sort.Interface = byName(nil)
+
+func walk(nodes []*Node) int {
+	count := 0
+	for _, n := range nodes {
+		v, ok := interface{}(n).(*Node)
+		visible := ok && v.children // want `bool 'visible' should read as a predicate, e.g. 'isVisible'`
+		if visible && !n.hasItems {
+			count++
+		}
+	}
+	done := count > 0
+	_ = done
+	return count
+}
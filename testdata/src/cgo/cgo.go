@@ -0,0 +1,22 @@
+package cgo
+
+/*
+#include <stdlib.h>
+
+struct request_info {
+	int request_size;
+};
+
+static int request_count(void) { return 1; }
+*/
+import "C"
+
+//export goRequestCallback
+func goRequestCallback(size C.int) C.int {
+	return size
+}
+
+func newRequestInfo() C.struct_request_info {
+	requestInfo := C.struct_request_info{request_size: C.request_count()}
+	return requestInfo
+}
@@ -0,0 +1,16 @@
+package checkstrings
+
+import "log/slog"
+
+type entry struct{}
+
+func (entry) WithField(key string, value any) entry { return entry{} }
+
+func run(logger *slog.Logger, e entry, id int) {
+	slog.With("request_id", id)                  // want "suggest replacing 'request_id' with 'req_id'"
+	e.WithField(`response_code`, id)             // want "suggest replacing 'response_code' with 'res_code'"
+	e.WithField("request\tid", id)               // want "suggest replacing 'request\tid' with 'req\tid'"
+	logger.Info("requestStarted", "request", id) // want "suggest replacing 'request' with 'req'"
+	slog.Info("request_id")
+	e.WithField("user_id", id)
+}
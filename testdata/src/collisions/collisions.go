@@ -0,0 +1,20 @@
+package collisions
+
+var requestTimeout int // want `suggest replacing 'requestTimeout' with 'reqTimeout' \(not fixed: 'reqTimeout' is already declared\)`
+var reqTimeout int
+
+var requestCount int     // want `suggest replacing 'requestCount' with 'reqCount' \(not fixed: 'requisitionCount' would also be renamed to 'reqCount'\)`
+var requisitionCount int // want `suggest replacing 'requisitionCount' with 'reqCount' \(not fixed: 'requestCount' would also be renamed to 'reqCount'\)`
+
+var requestLimit int // want `suggest replacing 'requestLimit' with 'reqLimit'$`
+
+func handle() int {
+	requestBody := 1 // want `suggest replacing 'requestBody' with 'reqBody' \(not fixed: 'reqBody' is already declared\)`
+	reqBody := 2
+	return requestBody + reqBody
+}
+
+func other() int {
+	requestBody := 1 // want `suggest replacing 'requestBody' with 'reqBody'$`
+	return requestBody
+}
@@ -0,0 +1,19 @@
+// Package comments handles the incoming request. // want "comment: suggest replacing 'r.quest' with 'req'"
+package comments
+
+//go:generate stringer -type=requestKind
+
+// See https://example.com/docs/request for the format and `request` for the field.
+type format struct{}
+
+// TODO: drop the response cache. // want "comment: suggest replacing 'r.sponse' with 'res'"
+var cache = map[string]string{}
+
+//nolint:gonamefix // request handling is legacy
+func handle() {
+	/* Each request is batched. */ // want "comment: suggest replacing 'r.quest' with 'req'"
+	_ = cache
+}
+
+// Deprecated: use handle. ResponseWriter support is gone. // want "comment: suggest replacing 'R.sponseWriter' with 'ResWriter'"
+func legacy() {}
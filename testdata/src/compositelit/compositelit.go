@@ -0,0 +1,14 @@
+package compositelit
+
+type Config struct {
+	request  string // want "suggest replacing 'request' with 'req'"
+	database string // want "suggest replacing 'database' with 'db'"
+}
+
+var config = Config{
+	request:  "foo", // want "suggest replacing 'request' with 'req'"
+	database: "bar", // want "suggest replacing 'database' with 'db'"
+}
+
+// Positional elements have no key to check
+var positional = Config{"foo", "bar"}
@@ -0,0 +1,29 @@
+package configv2
+
+type ConfigLoader struct{} // want `'ConfigLoader' stutters as configv2.ConfigLoader; suggest 'Loader'`
+
+// Config alone is the idiomatic name of the package's main type
+type Config struct{}
+
+func ConfigParse() *Config { // want `'ConfigParse' stutters as configv2.ConfigParse; suggest 'Parse'`
+	return &Config{}
+}
+
+// Renaming would collide with Validate, so only a report is made
+func ConfigValidate() {} // want `'ConfigValidate' stutters as configv2.ConfigValidate; suggest 'Validate'`
+
+func Validate() {}
+
+// Configure is a word of its own, not config + ure
+func Configure() {}
+
+const configDefaultPath = "config.yml" // want `'configDefaultPath' stutters as configv2.configDefaultPath; suggest 'defaultPath'`
+
+func (l *ConfigLoader) ConfigLoad() {}
+
+func load() *Config {
+	var l ConfigLoader
+	l.ConfigLoad()
+	_ = configDefaultPath
+	return ConfigParse()
+}
@@ -0,0 +1,21 @@
+package coverage
+
+type RequestHandler struct {
+	userRequest string
+}
+
+func handleRequest(request string) {
+	for _, user := range []string{request} {
+		userResponse := user
+		_ = userResponse
+	}
+}
+
+// ResponseWriter is allowed by the test config
+type ResponseWriter struct{}
+
+// WriteString is a name of the standard library, never flagged
+func (RequestHandler) WriteString(s string) (int, error) { return len(s), nil }
+
+// legacyUser is excluded by name in the test config
+var legacyUser string
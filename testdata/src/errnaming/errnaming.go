@@ -0,0 +1,57 @@
+package errnaming
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+var errClosed = errors.New("closed")
+
+var FooError = errors.New("foo") // want `error variable 'FooError' should be named 'ErrFoo'`
+
+var timeoutErr = fmt.Errorf("timeout") // want `error variable 'timeoutErr' should be named 'errTimeout'`
+
+var Failure = errors.New("failure") // want `error variable 'Failure' should be named 'ErrFailure'`
+
+var E = errors.New("e") // want `error variable 'E' should be named ErrXxx`
+
+// Not errors
+var errorCount int
+
+func doThing() error { return FooError }
+
+func run() error {
+	if err := doThing(); err != nil {
+		return err
+	}
+
+	e := doThing() // want `error variable 'e' should be named 'err'`
+	if e != nil {
+		return e
+	}
+
+	var error1 error = doThing() // want `error variable 'error1' should be named 'err1'`
+
+	parseErr := doThing() // want `error variable 'parseErr' should be named 'errParse'`
+	n, errWrite := 0, doThing()
+	_ = n
+
+	err2 := doThing()
+	if err2 != nil || errWrite != nil {
+		return parseErr
+	}
+	return error1
+}
+
+// Renaming e to err would capture the use after err is declared, so no fix
+// is offered
+func collide() error {
+	e := doThing() // want `error variable 'e' should be named 'err'`
+	if e != nil {
+		err := fmt.Errorf("wrap: %w", e)
+		return errors.Join(err, e)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package excludenames
+
+type userService struct{} // OK - configured as an excluded name
+
+type RequestID string // OK - configured as an excluded name
+
+type orderService struct{} // OK - matches the *Service glob
+
+func newUserService() *userService { return nil } // OK - matches the *Service glob
+
+var userName string // want "suggest replacing 'userName' with 'usrName'"
+
+var RequestBody string // want "suggest replacing 'RequestBody' with 'ReqBody'"
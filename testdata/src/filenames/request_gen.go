@@ -0,0 +1,3 @@
+package filenames // OK - excluded by exclude-files
+
+var request string
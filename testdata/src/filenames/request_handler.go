@@ -0,0 +1,4 @@
+package filenames // want "suggest renaming file 'request_handler.go' to 'req_handler.go'"
+
+// The identifiers of the file are checked as usual
+var handlerName string
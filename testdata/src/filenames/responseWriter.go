@@ -0,0 +1 @@
+package filenames // want "suggest renaming file 'responseWriter.go' to 'resWriter.go'"
@@ -0,0 +1 @@
+package filenames // OK - no mapping applies to the name
@@ -0,0 +1,5 @@
+// Code generated by hand, and edited since.
+
+package generated
+
+type requestKind int // want "suggest replacing 'requestKind' with 'reqKind'"
@@ -0,0 +1,52 @@
+package getter
+
+import "getterdep"
+
+type User struct {
+	name  string
+	Email string
+}
+
+func (u *User) GetName() string { // want `getter 'GetName' should be named 'Name'`
+	return u.name
+}
+
+// A field named Email exists, which is why the Get prefix was used
+func (u *User) GetEmail() string {
+	return u.Email
+}
+
+func (u *User) Label() string { return "label" }
+
+// A method named Label exists
+func (u User) GetLabel() string {
+	return u.Label()
+}
+
+// Parameters make it more than a getter
+func (u *User) GetField(key string) string { return key }
+
+func (u *User) GetOrCreateName() string { return u.name }
+
+// GetOrX names do more than get
+func (u *User) GetOrCreateSession() string { return "" }
+
+// Words starting with Or are plain getters
+func (u *User) GetOrder() int { // want `getter 'GetOrder' should be named 'Order'`
+	return 0
+}
+
+func (u *User) GetAll() []string { return nil }
+
+func (u *User) Getter() string { return "" }
+
+// Book implements getterdep.Titled, so GetTitle cannot be renamed
+type Book struct{}
+
+func (b Book) GetTitle() string { return "" }
+
+var _ getterdep.Titled = Book{}
+
+func describe(u *User) string {
+	return u.GetName()
+}
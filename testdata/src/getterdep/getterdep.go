@@ -0,0 +1,6 @@
+package getterdep
+
+// Titled is implemented by types in other packages.
+type Titled interface {
+	GetTitle() string
+}
@@ -0,0 +1,25 @@
+package hungarian
+
+var strUserName string // want "suggest replacing 'strUserName' with 'userName'"
+
+var iCount int // want "suggest replacing 'iCount' with 'count'"
+
+var bIsValid bool // want "suggest replacing 'bIsValid' with 'isValid'"
+
+var pConfig *int // want "suggest replacing 'pConfig' with 'config'"
+
+var m_total int // want "suggest replacing 'm_total' with 'total'"
+
+var pURL string // want "suggest replacing 'pURL' with 'url'"
+
+// Ordinary words starting with a prefix letter are not flagged
+var price int
+var binary []byte
+var string1 string
+
+// Exceptions
+var iOS bool
+var iPhoneModel string // want "suggest replacing 'iPhoneModel' with 'phoneModel'"
+var nGram string       // OK - configured exception
+
+func setFlag(bType bool) {} // OK - stripping would produce a keyword
@@ -0,0 +1,19 @@
+package initialisms
+
+var userId string // want "suggest replacing 'userId' with 'userID'"
+
+var idList []string // OK - leading initialism of a lowerCamel name stays lower case
+
+var IdList []string // want "suggest replacing 'IdList' with 'IDList'"
+
+var userID string // OK - already normalized
+
+func parseUrl(rawUrl string) {} // want "suggest replacing 'parseUrl' with 'parseURL'" "suggest replacing 'rawUrl' with 'rawURL'"
+
+type HttpClient struct { // want "suggest replacing 'HttpClient' with 'HTTPClient'"
+	baseUrl string // want "suggest replacing 'baseUrl' with 'baseURL'"
+	Json    []byte // want "suggest replacing 'Json' with 'JSON'"
+	Sku     string // want "suggest replacing 'Sku' with 'SKU'"
+}
+
+var identity string // OK - "id" is only a prefix of a longer word
@@ -0,0 +1,48 @@
+package interfaces
+
+type Reader interface {
+	Read(p []byte) (int, error)
+}
+
+type Closer interface {
+	Close() error
+}
+
+type Stopper interface {
+	Stop()
+}
+
+type Generator interface {
+	Generate() string
+}
+
+type FileReader interface { // OK - qualified agent noun
+	Read(p []byte) (int, error)
+}
+
+type DataSource interface { // want `single-method interface 'DataSource' should be named after its method Fetch, e.g. 'Fetcher'`
+	Fetch() ([]byte, error)
+}
+
+type closeable interface { // want `single-method interface 'closeable' should be named after its method Close, e.g. 'closer'`
+	Close() error
+}
+
+type Handler interface { // OK - built-in exception
+	ServeHTTP()
+}
+
+type Repository interface { // OK - configured exception
+	Find(id int) string
+}
+
+// Multi-method interfaces are never flagged
+type Store interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// Embedded interfaces are not single-method interfaces
+type ReadStore interface {
+	Store
+}
@@ -0,0 +1,27 @@
+package kinds
+
+// Request is a type, which the request mapping does not apply to
+type Request struct {
+	requestID string
+	// configuration applies everywhere
+	configuration string // want `suggest replacing 'configuration' with 'config'`
+}
+
+type Sender interface {
+	SendRequest(request Request) error // want `suggest replacing 'request' with 'req'`
+}
+
+const requestLimit = 10
+
+var requestCount int // want `suggest replacing 'requestCount' with 'reqCount'`
+
+func NewRequest(request string) (requestResult Request) { // want `suggest replacing 'request' with 'req'`
+	requestCopy := request            // want `suggest replacing 'requestCopy' with 'reqCopy'`
+	handle := func(request string) {} // want `suggest replacing 'request' with 'req'`
+	handle(requestCopy)
+	return Request{requestID: requestCopy}
+}
+
+func (request *Request) Retry() {} // want `suggest replacing 'request' with 'req'`
+
+func loadConfiguration() {} // want `suggest replacing 'loadConfiguration' with 'loadConfig'`
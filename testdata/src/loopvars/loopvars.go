@@ -0,0 +1,16 @@
+package loopvars
+
+func sum(values map[string]int, rows [][]int) int {
+	total := 0
+	for i := 0; i < len(rows); i++ { // OK - idiomatic loop variable
+		for j := range rows[i] { // OK - idiomatic loop variable
+			total += rows[i][j]
+		}
+	}
+	for k, v := range values { // OK - idiomatic range bindings
+		_ = k
+		total += v
+	}
+	i := total // want "suggest replacing 'i' with 'index'"
+	return i
+}
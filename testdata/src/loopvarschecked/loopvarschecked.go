@@ -0,0 +1,13 @@
+package loopvarschecked
+
+func sum(values map[string]int) int {
+	total := 0
+	for i := 0; i < 3; i++ { // want "suggest replacing 'i' with 'index'"
+		total += i
+	}
+	for k, v := range values { // want "suggest replacing 'k' with 'key'" "suggest replacing 'v' with 'value'"
+		_ = k
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,9 @@
+package maxlength
+
+var configurationManagerRequestTimeout int // want "suggest replacing 'configurationManagerRequestTimeout' with 'configManagerRequestTimeout'" `'configurationManagerRequestTimeout' is 34 chars \(max 20\); applying known abbreviations gives 'configMgrReqTimeout' \(19\)`
+
+var somewhatUnreasonablyLongName int // want `'somewhatUnreasonablyLongName' is 28 chars \(max 20\)$`
+
+var shortEnough int // OK - below the limit
+
+func requestRequestHandler() {} // want "suggest replacing 'requestRequestHandler' with 'reqRequestHandler'" `applying known abbreviations gives 'reqReqHandler' \(13\)`
@@ -0,0 +1,27 @@
+package onlykinds
+
+// Only functions and parameters are checked
+
+type RequestHandler struct {
+	requestCount int
+}
+
+type RequestSender interface {
+	SendRequest(request string) error // want `suggest replacing 'request' with 'req'`
+}
+
+const requestLimit = 10
+
+var requestTimeout int
+
+func handleRequest(request string) (requestResult string) { // want `suggest replacing 'handleRequest' with 'handleReq'` `suggest replacing 'request' with 'req'`
+	requestCopy := request
+	for _, requestItem := range []string{requestCopy} {
+		_ = requestItem
+	}
+	return requestCopy
+}
+
+func (h *RequestHandler) ServeRequest() {}
+
+var _ = RequestHandler{requestCount: requestLimit + requestTimeout}
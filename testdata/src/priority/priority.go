@@ -0,0 +1,6 @@
+package priority
+
+// The request mapping is prioritized, so it wins over the user mapping
+var userRequest string // want "suggest replacing 'userRequest' with 'userReq'"
+
+var user string // want "suggest replacing 'user' with 'usr'"
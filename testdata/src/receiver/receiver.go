@@ -0,0 +1,42 @@
+package receiver
+
+type Server struct {
+	name string
+}
+
+func (s *Server) Start() {}
+
+func (s Server) Name() string { return s.name }
+
+func (srv *Server) Stop() { // want `receiver name 'srv' of Server.Stop should be 's' for consistency with other methods of Server`
+	srv.name = ""
+}
+
+// The expected name is already taken by a local variable, so no fix is offered
+func (self *Server) Reset() { // want `receiver name 'self' of Server.Reset should be 's'`
+	s := "reset"
+	self.name = s
+}
+
+// The expected name is a parameter or a result, so no fix is offered either
+func (server *Server) SetName(s string) { // want `receiver name 'server' of Server.SetName should be 's'`
+	server.name = "x"
+}
+
+func (sv *Server) Clone() (s *Server) { // want `receiver name 'sv' of Server.Clone should be 's'`
+	return &Server{name: sv.name}
+}
+
+type List[T any] struct {
+	items []T
+}
+
+func (l *List[T]) Len() int { return len(l.items) }
+
+func (list *List[T]) Push(item T) { // want `receiver name 'list' of List.Push should be 'l'`
+	list.items = append(list.items, item)
+}
+
+// Unnamed and blank receivers are ignored
+func (*List[T]) Kind() string { return "list" }
+func (_ *List[T]) Cap() int   { return 0 }
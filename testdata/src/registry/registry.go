@@ -0,0 +1,5 @@
+package registry
+
+var request string // want "suggest replacing 'request' with 'req'"
+
+var response string // OK - not registered
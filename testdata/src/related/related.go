@@ -0,0 +1,11 @@
+package related
+
+var requestCount int // want "suggest replacing 'requestCount' with 'reqCount'"
+
+func increment() {
+	requestCount++
+}
+
+func count() int {
+	return requestCount
+}
@@ -0,0 +1,5 @@
+package ruleids
+
+var requestTimeout int // want `suggest replacing 'requestTimeout' with 'reqTimeout' \(see https://wiki.example.com/req\)`
+
+var responseBody string // want `suggest replacing 'responseBody' with 'resBody' \(see https://docs.example.com/naming#gonamefix/response-res\)`
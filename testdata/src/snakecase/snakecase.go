@@ -0,0 +1,32 @@
+package snakecase
+
+var user_name string // want "suggest replacing 'user_name' with 'userName'"
+
+var Max_retry_count int // want "suggest replacing 'Max_retry_count' with 'MaxRetryCount'"
+
+var user_ID string // OK - the underscore is not between lower case words
+
+func parse_input(raw_data string) {} // want "suggest replacing 'parse_input' with 'parseInput'" "suggest replacing 'raw_data' with 'rawData'"
+
+// Test naming conventions are exempt
+func Test_parse_fails() {}
+
+// Blank identifiers are exempt
+var _ = user_name
+var __ int
+
+//export go_callback
+func go_callback() {} // OK - name is fixed by cgo
+
+func local() {
+	for idx_value := 0; idx_value < 1; idx_value++ { // want "suggest replacing 'idx_value' with 'idxValue'"
+	}
+}
+
+func redeclare() {
+	raw_count := 0                                             // want "suggest replacing 'raw_count' with 'rawCount'"
+	raw_count, extra_count := 1, 2                             // want "suggest replacing 'extra_count' with 'extraCount'"
+	for _, item_value := range []int{raw_count, extra_count} { // want "suggest replacing 'item_value' with 'itemValue'"
+		_ = item_value
+	}
+}
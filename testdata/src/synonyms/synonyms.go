@@ -0,0 +1,12 @@
+package synonyms
+
+var confPath string // want "suggest replacing 'confPath' with 'configPath'" `'confPath': package uses both 'conf' \(2 identifiers\) and canonical 'config' \(3\)`
+
+func loadConfig(confDir string) {} // want "suggest replacing 'confDir' with 'configDir'" `'confDir': package uses both 'conf' \(2 identifiers\) and canonical 'config' \(3\)`
+
+var configName string
+
+type configLoader struct{}
+
+// srv has canonical 'server' nowhere in the package, so it is only a mapping finding
+var srvAddr string // want "suggest replacing 'srvAddr' with 'serverAddr'"
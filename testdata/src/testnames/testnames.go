@@ -0,0 +1,3 @@
+package testnames
+
+func processRequestHandler() {} // want "suggest replacing 'processRequestHandler' with 'processReqHandler'"
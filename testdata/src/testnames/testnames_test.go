@@ -0,0 +1,23 @@
+package testnames
+
+import "testing"
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"processRequestHandler", "request"},       // want "suggest replacing 'processRequestHandler' with 'processReqHandler'"
+		{name: "handles empty request", input: ""}, // want "suggest replacing 'handles empty request' with 'handles empty req'"
+		{name: "no mapping", input: "request"},
+	}
+	for _, tt := range tests {
+		_ = tt.input
+		processRequestHandler()
+	}
+
+	cases := map[string]struct{ desc string }{
+		"request": {desc: "escaped\trequest"}, // want "suggest replacing 'escaped\trequest' with 'escaped\treq'"
+	}
+	_ = cases
+}
@@ -0,0 +1,7 @@
+package transforms
+
+type requestImpl struct{} // want "suggest replacing 'requestImpl' with 'req'"
+
+var UserRequestInfo string // want "suggest replacing 'UserRequestInfo' with 'UserReqI'"
+
+var count_Request int // want "suggest replacing 'count_Request' with 'countReq'"
@@ -0,0 +1,29 @@
+package api
+
+// Handler is in scope of both mappings
+type Handler struct {
+	requestCount  int // want `suggest replacing 'requestCount' with 'reqCount'`
+	responseCount int // want `suggest replacing 'responseCount' with 'resCount'`
+}
+
+func (h *Handler) ServeRequest() {} // want `suggest replacing 'ServeRequest' with 'ServeReq'`
+
+// Client is only in scope of the mapping for every type of the package
+type Client struct {
+	requestCount  int
+	responseCount int // want `suggest replacing 'responseCount' with 'resCount'`
+}
+
+func (c *Client) SendRequest() {}
+
+// Process takes a parameter of a type in scope
+func Process(request *Handler) {} // want `suggest replacing 'request' with 'req'`
+
+// Plain identifiers have no owning type and are left alone
+func Plain(request string) {
+	var requestTotal int
+	_ = requestTotal
+}
+
+// Literal keys follow the field they name
+var defaultHandler = Handler{requestCount: 1} // want `suggest replacing 'requestCount' with 'reqCount'`
@@ -0,0 +1,11 @@
+package other
+
+import "typescope/internal/api"
+
+// Server is declared outside the scoped package
+type Server struct {
+	responseCount int
+}
+
+// Handle takes a parameter whose type is in scope
+func Handle(response *api.Client) {} // want `suggest replacing 'response' with 'res'`
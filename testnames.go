@@ -0,0 +1,158 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// DefaultTestNameFields returns the struct field names whose string values are
+// treated as test case names in table-driven tests.
+func DefaultTestNameFields() []string {
+	return []string{"name", "testName", "caseName", "desc"}
+}
+
+func buildTestNameFields(config Config) map[string]bool {
+	names := config.TestNameFields
+	if len(names) == 0 {
+		names = DefaultTestNameFields()
+	}
+	fields := make(map[string]bool, len(names))
+	for _, name := range names {
+		fields[name] = true
+	}
+	return fields
+}
+
+// checkTestTableNames checks the test case name of a table entry such as
+// {name: "processRequestHandler"}, so it keeps matching the function it is
+// named after once that is renamed. Positional entries like
+// {"processRequestHandler", ...} need type information to know which
+// element is the name field.
+func checkTestTableNames(pass *analysis.Pass, lit *ast.CompositeLit, fields map[string]bool, patterns []namePattern, caseSensitive bool) {
+	var structType *types.Struct
+	if pass.TypesInfo != nil {
+		if typ := pass.TypesInfo.TypeOf(lit); typ != nil {
+			structType, _ = typ.Underlying().(*types.Struct)
+		}
+	}
+
+	for i, elt := range lit.Elts {
+		var value ast.Expr
+		switch elt := elt.(type) {
+		case *ast.KeyValueExpr:
+			if key, ok := elt.Key.(*ast.Ident); ok && fields[key.Name] {
+				value = elt.Value
+			}
+		default:
+			if structType != nil && i < structType.NumFields() && fields[structType.Field(i).Name()] {
+				value = elt
+			}
+		}
+
+		if basic, ok := value.(*ast.BasicLit); ok && basic.Kind == token.STRING {
+			checkTestName(pass, basic, patterns, caseSensitive)
+		}
+	}
+}
+
+func checkTestName(pass *analysis.Pass, lit *ast.BasicLit, patterns []namePattern, caseSensitive bool) {
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+	suggestedName, pattern, ok := suggestTestName(name, patterns, caseSensitive)
+	if !ok {
+		return
+	}
+
+	reportStringLiteral(pass, lit, name, suggestedName, pattern)
+}
+
+// reportStringLiteral reports the string literal lit holding value. The fix
+// rewrites only the changed part between the quotes, so raw and interpreted
+// literals keep their quoting; literals with escapes get no fix because their
+// offsets do not line up with value.
+func reportStringLiteral(pass *analysis.Pass, lit *ast.BasicLit, value, suggested string, pattern namePattern) {
+	message := "suggest replacing '" + value + "' with '" + suggested + "'"
+	diagnostic := analysis.Diagnostic{
+		Pos:      lit.Pos(),
+		End:      lit.End(),
+		Category: pattern.id,
+		URL:      pattern.url,
+		Message:  withURL(message, pattern.url),
+	}
+
+	if lit.Value[1:len(lit.Value)-1] == value {
+		start, end, newText := changedRange(value, suggested)
+		pos := lit.Pos() + 1
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: message,
+			TextEdits: []analysis.TextEdit{{
+				Pos:     pos + token.Pos(start),
+				End:     pos + token.Pos(end),
+				NewText: []byte(newText),
+			}},
+		}}
+	}
+	pass.Report(diagnostic)
+}
+
+// suggestTestName applies the mappings to every word of a test case name such
+// as "handles empty request", each word being matched like an identifier. The
+// first pattern that matched is returned.
+func suggestTestName(name string, patterns []namePattern, caseSensitive bool) (string, namePattern, bool) {
+	var b strings.Builder
+	var first namePattern
+	matched := false
+
+	rest := name
+	for rest != "" {
+		end := strings.IndexFunc(rest, func(r rune) bool { return !isIdentRune(r) })
+		if end == 0 {
+			_, size := utf8.DecodeRuneInString(rest)
+			b.WriteString(rest[:size])
+			rest = rest[size:]
+			continue
+		}
+		if end < 0 {
+			end = len(rest)
+		}
+
+		word := rest[:end]
+		if suggested, pattern, ok := suggestName(word, patterns, caseSensitive); ok && !isGoKeyword(word) {
+			if !matched {
+				first, matched = pattern, true
+			}
+			word = suggested
+		}
+		b.WriteString(word)
+		rest = rest[end:]
+	}
+	return b.String(), first, matched
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// changedRange returns the byte range of old that differs from updated and the
+// text replacing it, after trimming the common prefix and suffix.
+func changedRange(old, updated string) (int, int, string) {
+	prefix := 0
+	for prefix < len(old) && prefix < len(updated) && old[prefix] == updated[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(updated)-prefix &&
+		old[len(old)-1-suffix] == updated[len(updated)-1-suffix] {
+		suffix++
+	}
+	return prefix, len(old) - suffix, updated[prefix : len(updated)-suffix]
+}
@@ -0,0 +1,72 @@
+package gonamefix
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// partialPrefix marks diagnostics produced without an AST.
+const partialPrefix = "[partial] "
+
+// AnalyzeTokens applies the name mappings to every identifier token of src.
+// It is the fallback for files that fail to parse: without an AST every
+// identifier is checked, including uses, so the results may contain false
+// positives. Diagnostic messages are prefixed with "[partial]". Invalid
+// patterns, which Config.Validate reports, are left out.
+func AnalyzeTokens(fset *token.FileSet, filename string, src []byte, config Config) []analysis.Diagnostic {
+	compiled, _ := compileConfig(config)
+	if _, excluded := compiled.exclusions.match(filename); excluded || !config.IncludeGenerated && isGeneratedSource(src) {
+		return nil
+	}
+
+	// Without an AST no identifier has an owning type or a known kind
+	patterns := patternsFor(compiled.patterns, nil, "")
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	allowed := allowedNames(config)
+
+	file := fset.AddFile(filename, fset.Base(), len(src))
+
+	var s scanner.Scanner
+	// Scan errors are expected here, the file already failed to parse
+	s.Init(file, src, nil, 0)
+
+	var diagnostics []analysis.Diagnostic
+	// Names selected from cgo's C pseudo-package follow "C" and "."
+	afterC, afterCPeriod := false, false
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		cgoName := afterCPeriod
+		afterCPeriod = afterC && tok == token.PERIOD
+		afterC = tok == token.IDENT && lit == "C"
+		if tok != token.IDENT || isGoKeyword(lit) || allowed[lit] || compiled.names.match(lit) || cgoName {
+			continue
+		}
+
+		suggestedName, pattern, ok := suggestName(lit, patterns, config.CaseSensitive)
+		if !ok {
+			continue
+		}
+		suggestedName = applyTransforms(config.Transforms, pattern, lit, suggestedName)
+		if suggestedName == lit || suggestedName == "" {
+			continue
+		}
+		diagnostics = append(diagnostics, analysis.Diagnostic{
+			Pos:      pos,
+			End:      pos + token.Pos(len(lit)),
+			Category: pattern.id,
+			URL:      pattern.url,
+			Message:  withURL(fmt.Sprintf("%ssuggest replacing '%s' with '%s'", partialPrefix, lit, suggestedName), pattern.url),
+		})
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,31 @@
+package gonamefix
+
+import (
+	"go/ast"
+	"log/slog"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// newTraceLogger returns the logger used by Config.TraceMappings. Trace lines
+// are written to stderr at debug level.
+func newTraceLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// traceMappings logs one line per pattern tested against ident, whether or not
+// it matched. Unlike suggestName it does not stop at the first match, so
+// interactions between overlapping patterns are visible.
+func traceMappings(logger *slog.Logger, pass *analysis.Pass, ident *ast.Ident, patterns []namePattern, caseSensitive bool) {
+	position := pass.Fset.Position(ident.Pos()).String()
+	for _, pattern := range patterns {
+		matched := replaceInName(ident.Name, pattern.original, pattern.replacement, caseSensitive) != ident.Name
+		logger.Debug("mapping tested",
+			slog.String("identifier", ident.Name),
+			slog.String("pattern_tested", MappingCategory(pattern.original, pattern.replacement)),
+			slog.Bool("matched", matched),
+			slog.String("position", position),
+		)
+	}
+}
@@ -0,0 +1,61 @@
+package gonamefix
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Transform post-processes the name suggested by a mapping. It receives the
+// mapping's original, the suggestion built so far and the identifier being
+// renamed, and returns the new suggestion.
+type Transform func(original, replacement, identifierName string) string
+
+// TrimSuffix returns a Transform that drops suffix from the suggestion when
+// the identifier ends with it, e.g. requestImpl -> reqImpl -> req with
+// TrimSuffix("Impl").
+func TrimSuffix(suffix string) Transform {
+	return func(_, replacement, identifierName string) string {
+		if !strings.HasSuffix(identifierName, suffix) {
+			return replacement
+		}
+		if trimmed := strings.TrimSuffix(replacement, suffix); trimmed != "" {
+			return trimmed
+		}
+		return replacement
+	}
+}
+
+// EnsureMaxLength returns a Transform that cuts the suggestion to at most n
+// characters.
+func EnsureMaxLength(n int) Transform {
+	return func(_, replacement, _ string) string {
+		if n <= 0 || utf8.RuneCountInString(replacement) <= n {
+			return replacement
+		}
+		return string([]rune(replacement)[:n])
+	}
+}
+
+// EnsureCamelCase joins underscore separated words of the suggestion into
+// camelCase, keeping the identifier's exported or unexported first letter.
+var EnsureCamelCase Transform = func(_, replacement, identifierName string) string {
+	camel := snakeToCamel(strings.Trim(replacement, "_"))
+	if camel == "" {
+		return replacement
+	}
+
+	first, size := utf8.DecodeRuneInString(camel)
+	if nameFirst, _ := utf8.DecodeRuneInString(identifierName); unicode.IsUpper(nameFirst) {
+		return string(unicode.ToUpper(first)) + camel[size:]
+	}
+	return string(unicode.ToLower(first)) + camel[size:]
+}
+
+// applyTransforms runs transforms in order on the suggestion for name.
+func applyTransforms(transforms []Transform, pattern namePattern, name, suggestedName string) string {
+	for _, transform := range transforms {
+		suggestedName = transform(pattern.original, suggestedName, name)
+	}
+	return suggestedName
+}
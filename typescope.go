@@ -0,0 +1,144 @@
+package gonamefix
+
+import (
+	"errors"
+	"go/ast"
+	"go/types"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// typeOwners finds the named type an identifier belongs to, for mappings
+// scoped to types: the receiver type of a method, the type declaring a field
+// or interface method, or the declared type of a parameter or receiver, after
+// pointers. Other identifiers, such as plain locals, have no owner. Owners
+// need type information; the index is built on first use.
+type typeOwners struct {
+	pass    *analysis.Pass
+	idents  map[*ast.Ident]*types.Named
+	members map[types.Object]*types.Named
+}
+
+func (o *typeOwners) build() {
+	if o.idents != nil {
+		return
+	}
+	o.idents = make(map[*ast.Ident]*types.Named)
+	o.members = make(map[types.Object]*types.Named)
+
+	info := o.pass.TypesInfo
+	declare := func(ident *ast.Ident, owner *types.Named) {
+		if owner == nil {
+			return
+		}
+		o.idents[ident] = owner
+		if obj := info.Defs[ident]; obj != nil {
+			o.members[obj] = owner
+		}
+	}
+	params := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				if obj := info.Defs[name]; obj != nil {
+					o.idents[name] = namedOf(obj.Type())
+				}
+			}
+		}
+	}
+
+	for _, file := range o.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				if node.Recv != nil && len(node.Recv.List) > 0 {
+					declare(node.Name, namedOf(info.TypeOf(node.Recv.List[0].Type)))
+				}
+				params(node.Recv)
+				params(node.Type.Params)
+			case *ast.FuncLit:
+				params(node.Type.Params)
+			case *ast.TypeSpec:
+				owner := namedOf(info.TypeOf(node.Name))
+				var members *ast.FieldList
+				switch t := node.Type.(type) {
+				case *ast.StructType:
+					members = t.Fields
+				case *ast.InterfaceType:
+					members = t.Methods
+				}
+				if members != nil {
+					for _, field := range members.List {
+						for _, name := range field.Names {
+							declare(name, owner)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// of returns the owner of ident, which may also be a use of a field, as in
+// a composite literal key, or nil. A nil index has no owners.
+func (o *typeOwners) of(ident *ast.Ident) *types.Named {
+	if o == nil || o.pass.TypesInfo == nil {
+		return nil
+	}
+	o.build()
+	if owner, ok := o.idents[ident]; ok {
+		return owner
+	}
+	if obj := o.pass.TypesInfo.Uses[ident]; obj != nil {
+		return o.members[obj]
+	}
+	return nil
+}
+
+// namedOf returns the named type of t after pointers, as declared for
+// generic types, or nil.
+func namedOf(t types.Type) *types.Named {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Origin()
+	}
+	return nil
+}
+
+// matchesType reports whether owner matches one of the qualified type
+// patterns, e.g. "internal/api.Handler" or "internal/api.*". The package part
+// matches the whole import path or its trailing elements; the type part is a
+// glob matched against the type name.
+func matchesType(owner *types.Named, patterns []string) bool {
+	if owner == nil || owner.Obj().Pkg() == nil {
+		return false
+	}
+	pkgPath, name := owner.Obj().Pkg().Path(), owner.Obj().Name()
+	for _, pattern := range patterns {
+		pkg, typ, _ := cutLast(pattern, ".")
+		if pkgPath != pkg && !strings.HasSuffix(pkgPath, "/"+pkg) {
+			continue
+		}
+		if matched, _ := path.Match(typ, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTypePattern reports whether pattern is a qualified type pattern.
+func validateTypePattern(pattern string) error {
+	pkg, typ, ok := cutLast(pattern, ".")
+	if !ok || pkg == "" || typ == "" {
+		return errors.New("expected package path and type, e.g. internal/api.Handler")
+	}
+	_, err := path.Match(typ, "")
+	return err
+}
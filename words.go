@@ -0,0 +1,62 @@
+package gonamefix
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// splitWords splits an identifier into its words. Word boundaries are
+// underscores, a lower-to-upper case transition (userName -> user, Name) and
+// the end of an upper case run followed by a lower case letter
+// (HTTPServer -> HTTP, Server). Digits stay attached to the preceding word.
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				flush(i)
+				start = i
+			} else if unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
+
+// capitalize upper-cases the first letter of s and keeps the rest as it is.
+func capitalize(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	if first == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(first)) + s[size:]
+}
+
+// capitalizeWord upper-cases the first letter of word and lower-cases the rest.
+func capitalizeWord(word string) string {
+	if word == "" {
+		return word
+	}
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}